@@ -1,20 +1,82 @@
 package main
 
 import (
+	"backend-go/internal/antispam"
 	"backend-go/internal/api"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/captcha"
 	"backend-go/internal/config"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/feeds"
+	"backend-go/internal/mailers"
+	"backend-go/internal/normalize"
+	"backend-go/internal/queue"
+	"backend-go/internal/ratelimit"
 	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"context"
+	"fmt"
 	"log"
+	"net"
+	"os"
+	"sync"
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Printf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// run starts the API and returns when it stops. Setup failures are returned
+// rather than exiting, so the deferred cleanup runs before main exits.
+func run() error {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Unsubscribe and preference links never expire, so a secret that
+	// changes on restart breaks every link already sent
+	secret := []byte(cfg.SigningSecret)
+	if len(secret) == 0 {
+		if cfg.MailTransport != "log" {
+			return fmt.Errorf("SIGNING_SECRET must be set when MAIL_TRANSPORT is %q, otherwise links in sent emails stop working after a restart", cfg.MailTransport)
+		}
+		log.Printf("SIGNING_SECRET is not set, using a random secret for local development")
+		secret = tokens.RandomSecret()
+	}
+
+	transport, err := mailers.NewMailer(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	verifier, err := captcha.NewVerifier(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize captcha verifier: %w", err)
+	}
+
+	var emailOptions []validators.EmailValidatorOption
+	if cfg.DisposableDomainsFile != "" {
+		disposable, loadErr := validators.LoadDomainSet(cfg.DisposableDomainsFile)
+		if loadErr != nil {
+			return fmt.Errorf("failed to load disposable domains: %w", loadErr)
+		}
+		emailOptions = append(emailOptions, validators.WithDisposableDomains(disposable))
+	}
+	if cfg.EmailDNSCheck {
+		emailOptions = append(emailOptions, validators.WithResolver(net.DefaultResolver, validators.DefaultLookupTimeout))
+	}
+	validator := validators.NewMailingListValidator(
+		validators.WithEmailValidator(validators.NewEmailValidator(emailOptions...)),
+	)
+
 	// Initialize SQLite repository
 	repo, err := repositories.NewSqliteMailingListRepository(cfg.DatabasePath)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 	defer func() {
 		if closeErr := repo.Close(); closeErr != nil {
@@ -22,10 +84,148 @@ func main() {
 		}
 	}()
 
-	// Create and start server
-	srv := api.NewApiServer(repo)
-	err = srv.ListenAndServe(cfg.ServerAddr)
+	mailQueue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize mail queue: %w", err)
+	}
+	if requeued, requeueErr := mailQueue.RequeueInterrupted(); requeueErr != nil {
+		log.Printf("Failed to requeue interrupted emails: %v", requeueErr)
+	} else if requeued > 0 {
+		log.Printf("Requeued %d emails interrupted by the last shutdown", requeued)
+	}
+
+	proxies, err := api.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("failed to parse TRUSTED_PROXIES: %w", err)
+	}
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
 	if err != nil {
-		log.Printf("Server error: %v", err)
+		return fmt.Errorf("failed to initialize api keys: %w", err)
+	}
+
+	privacy, err := repositories.NewSqlitePrivacyRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize privacy requests: %w", err)
+	}
+
+	consent, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize consent ledger: %w", err)
+	}
+
+	topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize topic repository: %w", err)
+	}
+
+	listRepo, err := repositories.NewSqliteListRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize list repository: %w", err)
+	}
+	lists, err := listRepo.ListLists()
+	if err != nil {
+		return fmt.Errorf("failed to load mailing lists: %w", err)
+	}
+
+	signer := tokens.NewSigner(secret)
+
+	// Deliver queued emails in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	defer func() {
+		cancel()
+		workers.Wait()
+	}()
+
+	options := queue.DefaultOptions()
+	options.Workers = cfg.MailQueueWorkers
+	options.MaxAttempts = cfg.MailQueueMaxAttempts
+	options.Retention = cfg.MailQueueRetention
+	dispatcher := queue.NewDispatcher(mailQueue, transport, options)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		dispatcher.Run(ctx)
+	}()
+
+	// Every list gets its own sender and templates, falling back to the
+	// configured ones
+	var (
+		builder      *emails.Builder
+		listBuilders []*emails.Builder
+	)
+	for _, list := range lists {
+		listBuilder, listErr := emails.NewListBuilder(list, cfg.MailFrom, cfg.BaseURL, cfg.TemplateDir, signer,
+			emails.WithPreferencesPage(cfg.PreferencesPageURL),
+		)
+		if listErr != nil {
+			return fmt.Errorf("failed to load email templates: %w", listErr)
+		}
+		if list.ID == dto.DefaultListID {
+			builder = listBuilder
+		}
+		listBuilders = append(listBuilders, listBuilder)
+	}
+
+	// Announce new blog posts from the feed to the default list
+	if cfg.FeedURL != "" {
+		posts, postsErr := repositories.NewSqlitePostRepository(repo.DB())
+		if postsErr != nil {
+			return fmt.Errorf("failed to initialize post repository: %w", postsErr)
+		}
+		watcher := feeds.NewWatcher(cfg.FeedURL, posts, repo, builder)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			watcher.Run(ctx, cfg.FeedPollInterval)
+		}()
+	}
+
+	// Bot protection for the signup form
+	forms := antispam.NewForms(signer, cfg.FormMinAge, cfg.FormMaxAge, cfg.PowDifficulty)
+	checks := []antispam.Check{antispam.Honeypot()}
+	if cfg.FormMinAge > 0 {
+		checks = append(checks, antispam.MinimumAge(forms))
+	}
+	if forms.Difficulty > 0 {
+		checks = append(checks, antispam.ProofOfWork(forms))
+	}
+	if cfg.FormMinAge > 0 || forms.Difficulty > 0 {
+		checks = append(checks, antispam.SingleUse(forms))
+	}
+
+	// Create and start server
+	serverOptions := []api.ServerOption{
+		api.WithValidator(validator),
+		api.WithNormalizer(normalize.Normalizer{FoldAliases: cfg.EmailFoldAliases}),
+		api.WithMailer(queue.NewMailer(mailQueue)),
+		api.WithMailQueue(mailQueue),
+		api.WithSigner(signer),
+		api.WithEmailBuilder(builder),
+		api.WithAPIKeys(apikeys.NewManager(keys)),
+		api.WithPrivacy(privacy),
+		api.WithConsentLedger(consent, cfg.PrivacyPolicyVersion),
+		api.WithTopics(topics),
+		api.WithTrustedProxies(proxies),
+		api.WithForms(forms),
+		api.WithSignupChecks(checks...),
+	}
+	for _, listBuilder := range listBuilders {
+		serverOptions = append(serverOptions, api.WithList(listBuilder))
+	}
+	if verifier != nil {
+		serverOptions = append(serverOptions, api.WithCaptcha(verifier, cfg.CaptchaRequired))
+	}
+	if cfg.RateLimitPerIP > 0 {
+		serverOptions = append(serverOptions, api.WithIPRateLimit(ratelimit.NewLimiter(cfg.RateLimitPerIP, cfg.RateLimitWindow)))
+	}
+	if cfg.RateLimitPerEmail > 0 {
+		serverOptions = append(serverOptions, api.WithEmailRateLimit(ratelimit.NewLimiter(cfg.RateLimitPerEmail, cfg.RateLimitWindow)))
+	}
+	srv := api.NewApiServer(repo, serverOptions...)
+	if err := srv.ListenAndServe(cfg.ServerAddr); err != nil {
+		return fmt.Errorf("server error: %w", err)
 	}
+	return nil
 }
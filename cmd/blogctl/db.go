@@ -0,0 +1,179 @@
+package main
+
+import (
+	"backend-go/internal/migrate"
+	"backend-go/internal/repositories"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runDB(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("db requires migrate or backup")
+	}
+
+	switch args[0] {
+	case "migrate":
+		return migrateDB(a, args[1:])
+	case "backup":
+		return backupDB(a, args[1:])
+	default:
+		return errUsage("unknown db command %q", args[0])
+	}
+}
+
+// migrationResult is a migration applied or rolled back by db migrate.
+type migrationResult struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// migrationStatus is one row of db migrate status.
+type migrationStatus struct {
+	AppliedAt *time.Time `json:"appliedAt"`
+	Name      string     `json:"name"`
+	Version   int        `json:"version"`
+}
+
+func migrateDB(a *app, args []string) error {
+	action := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	flags := a.flagSet("db migrate")
+	steps := flags.Int("steps", 1, "Number of migrations to roll back (down)")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	// Opened without NewSqliteMailingListRepository, which would apply
+	// pending migrations before status or down could look at them.
+	db, err := repositories.OpenSqlite(a.dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing database: %v", closeErr)
+		}
+	}()
+
+	runner, err := repositories.NewMigrationRunner(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	switch action {
+	case "up":
+		applied, err := runner.Up()
+		if err != nil {
+			return err
+		}
+		return a.output(migrationResults(applied), func(w io.Writer) {
+			for _, migration := range applied {
+				fmt.Fprintf(w, "Applied %03d_%s\n", migration.Version, migration.Name)
+			}
+			if len(applied) == 0 {
+				fmt.Fprintf(w, "Database is up to date at version %d\n", runner.Latest())
+			}
+		})
+	case "down":
+		if *steps < 1 {
+			return errUsage("-steps must be at least 1")
+		}
+		rolledBack, err := runner.Down(*steps)
+		if err != nil {
+			return err
+		}
+		return a.output(migrationResults(rolledBack), func(w io.Writer) {
+			for _, migration := range rolledBack {
+				fmt.Fprintf(w, "Rolled back %03d_%s\n", migration.Version, migration.Name)
+			}
+			if len(rolledBack) == 0 {
+				fmt.Fprintln(w, "No migrations to roll back")
+			}
+		})
+	case "status":
+		statuses, err := runner.Status()
+		if err != nil {
+			return err
+		}
+		results := make([]migrationStatus, 0, len(statuses))
+		for _, status := range statuses {
+			results = append(results, migrationStatus{Version: status.Version, Name: status.Name, AppliedAt: status.AppliedAt})
+		}
+		return a.output(results, func(w io.Writer) {
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED")
+			for _, status := range statuses {
+				applied := "pending"
+				if status.AppliedAt != nil {
+					applied = status.AppliedAt.Format(time.DateTime)
+				}
+				fmt.Fprintf(writer, "%03d\t%s\t%s\n", status.Version, status.Name, applied)
+			}
+			if err := writer.Flush(); err != nil {
+				log.Printf("Error writing output: %v", err)
+			}
+		})
+	default:
+		return errUsage("unknown db migrate action %q, expected up, down or status", action)
+	}
+}
+
+func migrationResults(migrations []migrate.Migration) []migrationResult {
+	results := make([]migrationResult, 0, len(migrations))
+	for _, migration := range migrations {
+		results = append(results, migrationResult{Version: migration.Version, Name: migration.Name})
+	}
+	return results
+}
+
+// backupDB writes a consistent copy of the database with VACUUM INTO, which
+// is safe while the API is running.
+func backupDB(a *app, args []string) error {
+	flags := a.flagSet("db backup")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage("db backup requires the backup file path")
+	}
+	target := flags.Arg(0)
+
+	if _, err := os.Stat(a.dbPath); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("backup file %s already exists", target)
+	}
+
+	db, err := repositories.OpenSqlite(a.dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing database: %v", closeErr)
+		}
+	}()
+
+	if _, err := db.Exec(`VACUUM INTO ?`, target); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	return a.output(map[string]any{"file": target, "bytes": info.Size()}, func(w io.Writer) {
+		fmt.Fprintf(w, "Backed up %s to %s (%d bytes)\n", a.dbPath, target, info.Size())
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/export"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// runExport streams subscribers as CSV in the format import reads, as JSON
+// Lines or as vCards.
+func runExport(a *app, args []string) error {
+	flags := a.flagSet("export")
+	outPath := flags.String("o", "", "Output file (default: stdout)")
+	format := flags.String("format", export.FormatCSV, "Output format: "+strings.Join(export.Formats(), ", "))
+	status := flags.String("status", "", "Only export pending, active or unsubscribed subscribers")
+	createdAfter := flags.String("created-after", "", "Only export subscribers who signed up at or after this RFC 3339 time or YYYY-MM-DD date")
+	createdBefore := flags.String("created-before", "", "Only export subscribers who signed up before this RFC 3339 time or YYYY-MM-DD date")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if !slices.Contains(export.Formats(), *format) {
+		return errUsage("-format must be one of %s", strings.Join(export.Formats(), ", "))
+	}
+	switch *status {
+	case "", dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed:
+	default:
+		return errUsage("-status must be %s, %s or %s", dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed)
+	}
+	if a.json && *outPath == "" {
+		return errUsage("export -json requires -o, the export itself is written to stdout")
+	}
+
+	query := dto.SubscriberQuery{Status: *status}
+	var err error
+	if query.CreatedAfter, err = parseDate("created-after", *createdAfter); err != nil {
+		return err
+	}
+	if query.CreatedBefore, err = parseDate("created-before", *createdBefore); err != nil {
+		return err
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	var out io.Writer = a.stdout
+	if *outPath != "" {
+		file, err := os.OpenFile(*outPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil {
+				log.Printf("Error closing export file: %v", closeErr)
+			}
+		}()
+		out = file
+	}
+
+	exported, err := export.Export(repo, a.list, query, *format, out)
+	if err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		return nil
+	}
+	return a.output(map[string]any{"exported": exported, "file": *outPath}, func(w io.Writer) {
+		fmt.Fprintf(w, "Exported %d subscribers to %s\n", exported, *outPath)
+	})
+}
+
+// parseDate reads an optional RFC 3339 time or YYYY-MM-DD date flag.
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, errUsage("-%s must be an RFC 3339 time or a YYYY-MM-DD date", name)
+}
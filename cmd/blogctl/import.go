@@ -0,0 +1,103 @@
+package main
+
+import (
+	"backend-go/internal/importer"
+	"backend-go/internal/normalize"
+	"backend-go/internal/validators"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// runImport streams subscribers from a CSV file, or an export of another
+// newsletter provider, into the database. Rows are validated like signups,
+// without the DNS check, and stored in batches.
+func runImport(a *app, args []string) error {
+	flags := a.flagSet("import")
+	format := flags.String("format", importer.FormatCSV, "File format: "+strings.Join(importer.Formats(), ", ")+"; use export for files written by blogctl export")
+	dryRun := flags.Bool("dry-run", false, "Validate the file and report what would be imported without storing anything")
+	rejectsPath := flags.String("rejects", "", "Write rows that were not imported to this CSV file, with the reason")
+	batchSize := flags.Int("batch-size", importer.DefaultBatchSize, "Rows stored per transaction")
+	foldAliases := flags.Bool("fold-aliases", a.cfg.EmailFoldAliases, "Apply provider rules such as Gmail ignoring dots and +tags when detecting duplicates")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage("import requires one file")
+	}
+	if !slices.Contains(importer.Formats(), *format) {
+		return errUsage("-format must be one of %s", strings.Join(importer.Formats(), ", "))
+	}
+	if *batchSize < 1 {
+		return errUsage("-batch-size must be at least 1")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Printf("Error closing import file: %v", closeErr)
+		}
+	}()
+
+	source, err := importer.NewSource(*format, file)
+	if err != nil {
+		return err
+	}
+
+	options := importer.Options{
+		Normalizer: normalize.Normalizer{FoldAliases: *foldAliases},
+		ListID:     a.list,
+		BatchSize:  *batchSize,
+		DryRun:     *dryRun,
+	}
+	if *rejectsPath != "" {
+		rejects, err := os.OpenFile(*rejectsPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create rejects file: %w", err)
+		}
+		defer func() {
+			if closeErr := rejects.Close(); closeErr != nil {
+				log.Printf("Error closing rejects file: %v", closeErr)
+			}
+		}()
+		options.Rejects = rejects
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+	if options.Consent, err = consentLedger(repo); err != nil {
+		return err
+	}
+
+	report, err := importer.NewImporter(repo, validators.NewMailingListValidator(), options).Import(source)
+	if err != nil {
+		return fmt.Errorf("import stopped after %d rows: %w", report.Rows, err)
+	}
+
+	if err := a.output(report, func(w io.Writer) {
+		imported := "Imported"
+		if report.DryRun {
+			fmt.Fprintln(w, "Dry run, nothing was stored")
+			imported = "Would import"
+		}
+		fmt.Fprintf(w, "Rows: %d\n%s: %d\nDuplicates: %d\nRejected: %d\n", report.Rows, imported, report.Imported, report.Duplicates, report.Rejected)
+		if report.Rejected+report.Duplicates > 0 && *rejectsPath != "" {
+			fmt.Fprintf(w, "Rows that were not imported are listed in %s\n", *rejectsPath)
+		}
+	}); err != nil {
+		return err
+	}
+	if report.Rejected > 0 {
+		return fmt.Errorf("%w: %d rows were rejected", errIncomplete, report.Rejected)
+	}
+	return nil
+}
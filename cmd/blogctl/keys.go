@@ -0,0 +1,102 @@
+package main
+
+import (
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// createdKey is the output of keys create, the only time the token is shown.
+type createdKey struct {
+	*dto.APIKey
+	Token string `json:"token"`
+}
+
+func runKeys(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("keys requires create, list or revoke")
+	}
+	command, args := args[0], args[1:]
+
+	flags := a.flagSet("keys " + command)
+	name := flags.String("name", "", "Name describing what the key is used for (create)")
+	scopes := flags.String("scopes", "", "Comma-separated scopes granted to the key: "+strings.Join(dto.Scopes, ", ")+" (create)")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize api keys: %w", err)
+	}
+	manager := apikeys.NewManager(keys)
+
+	switch command {
+	case "create":
+		if *name == "" || *scopes == "" {
+			return errUsage("keys create requires -name and -scopes")
+		}
+		token, key, err := manager.Create(*name, strings.Split(*scopes, ","))
+		if err != nil {
+			return fmt.Errorf("failed to create api key: %w", err)
+		}
+		return a.output(createdKey{APIKey: key, Token: token}, func(w io.Writer) {
+			fmt.Fprintf(w, "Created api key %s (%s) with scopes %s\n", key.Prefix, key.Name, strings.Join(key.Scopes, ", "))
+			fmt.Fprintf(w, "\n  %s\n\nStore it now, it cannot be shown again.\n", token)
+		})
+	case "list":
+		list, err := manager.List()
+		if err != nil {
+			return fmt.Errorf("failed to list api keys: %w", err)
+		}
+		if list == nil {
+			list = []dto.APIKey{}
+		}
+		return a.output(list, func(w io.Writer) {
+			printKeys(w, list)
+		})
+	case "revoke":
+		if flags.NArg() != 1 {
+			return errUsage("keys revoke requires the key prefix")
+		}
+		prefix := flags.Arg(0)
+		if err := manager.Revoke(prefix); err != nil {
+			return fmt.Errorf("failed to revoke api key: %w", err)
+		}
+		return a.output(map[string]string{"revoked": prefix}, func(w io.Writer) {
+			fmt.Fprintf(w, "Revoked api key %s\n", prefix)
+		})
+	default:
+		return errUsage("unknown keys command %q", command)
+	}
+}
+
+func printKeys(w io.Writer, keys []dto.APIKey) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PREFIX\tNAME\tSCOPES\tCREATED\tLAST USED\tSTATUS")
+	for _, key := range keys {
+		lastUsed, status := "never", "active"
+		if key.LastUsedAt != nil {
+			lastUsed = key.LastUsedAt.Format(time.DateTime)
+		}
+		if key.RevokedAt != nil {
+			status = "revoked " + key.RevokedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", key.Prefix, key.Name, strings.Join(key.Scopes, ","), key.CreatedAt.Format(time.DateTime), lastUsed, status)
+	}
+	if err := writer.Flush(); err != nil {
+		log.Printf("Error writing output: %v", err)
+	}
+}
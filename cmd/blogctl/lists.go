@@ -0,0 +1,97 @@
+package main
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/validators"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runLists(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("lists requires list, add or remove")
+	}
+	command, args := args[0], args[1:]
+
+	flags := a.flagSet("lists " + command)
+	name := flags.String("name", "", "Name emails call the list by (add, default: the id)")
+	from := flags.String("from", "", "Sender of the list's emails (add, default: MAIL_FROM)")
+	origins := flags.String("origins", "", "Comma-separated origins whose signup forms may call the list (add, default: the built-in origins)")
+	templates := flags.String("templates", "", "Directory of email templates overriding the built-in ones (add, default: TEMPLATE_DIR)")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	lists, err := listRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "list":
+		all, err := lists.ListLists()
+		if err != nil {
+			return err
+		}
+		return a.output(all, func(w io.Writer) {
+			printLists(w, all)
+		})
+	case "add":
+		if flags.NArg() != 1 {
+			return errUsage("lists add requires the list id")
+		}
+		list := &dto.List{Name: *name, MailFrom: *from, TemplateDir: *templates, ID: flags.Arg(0)}
+		if !validators.ValidListID(list.ID) {
+			return errUsage("list ids are lowercase letters and digits separated by dashes, got %q", list.ID)
+		}
+		if list.Name == "" {
+			list.Name = list.ID
+		}
+		for _, origin := range strings.Split(*origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				list.AllowedOrigins = append(list.AllowedOrigins, origin)
+			}
+		}
+		if err := lists.CreateList(list); err != nil {
+			return err
+		}
+		return a.output(list, func(w io.Writer) {
+			fmt.Fprintf(w, "Added list %s (%s), served at /lists/%s/subscribers\n", list.ID, list.Name, list.ID)
+		})
+	case "remove":
+		if flags.NArg() != 1 {
+			return errUsage("lists remove requires the list id")
+		}
+		id := flags.Arg(0)
+		if err := lists.DeleteList(id); err != nil {
+			return err
+		}
+		return a.output(map[string]string{"removed": id}, func(w io.Writer) {
+			fmt.Fprintf(w, "Removed list %s\n", id)
+		})
+	default:
+		return errUsage("unknown lists command %q", command)
+	}
+}
+
+func printLists(w io.Writer, lists []dto.List) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tFROM\tORIGINS\tTEMPLATES\tCREATED")
+	for _, list := range lists {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", list.ID, list.Name, orDash(list.MailFrom),
+			orDash(strings.Join(list.AllowedOrigins, ",")), orDash(list.TemplateDir), list.CreatedAt.Format(time.DateTime))
+	}
+	if err := writer.Flush(); err != nil {
+		log.Printf("Error writing output: %v", err)
+	}
+}
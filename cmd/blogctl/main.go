@@ -0,0 +1,232 @@
+package main
+
+import (
+	"backend-go/internal/config"
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// Exit codes shared by every command.
+const (
+	exitOK = 0
+	// exitError means the command failed.
+	exitError = 1
+	// exitUsage means the command line was invalid.
+	exitUsage = 2
+	// exitIncomplete means the command ran but some records failed, for
+	// example import rows or newsletter recipients.
+	exitIncomplete = 3
+)
+
+const usage = `Usage: blogctl [-db PATH] [-list ID] [-json] COMMAND [FLAGS] [ARGS]
+
+Commands:
+  import [-format FORMAT] [-dry-run] [-rejects FILE] [-batch-size N] [-fold-aliases] FILE
+  export [-format FORMAT] [-o FILE] [-status STATUS] [-created-after DATE] [-created-before DATE]
+  subscribers list [-status STATUS] [-q TEXT] [-limit N]
+  subscribers add [-username NAME] EMAIL
+  subscribers remove EMAIL
+  subscribers dedupe [-dry-run] [-fold-aliases]
+  subscribers consent [-fold-aliases] EMAIL
+  db migrate [up|down|status] [-steps N]
+  db backup FILE
+  send -file FILE [-subject TEXT] [-campaign ID] [-topic SLUG] [-frequency FREQUENCY] [-dry-run] [-test-to EMAIL]
+       [-retry-uncertain] [-mark-sent EMAIL[,EMAIL...]]
+  topics list
+  topics add [-name NAME] [-description TEXT] SLUG
+  topics remove SLUG
+  lists list
+  lists add [-name NAME] [-from ADDRESS] [-origins ORIGIN[,ORIGIN...]] [-templates DIR] ID
+  lists remove ID
+  keys create -name NAME -scopes SCOPE[,SCOPE...]
+  keys list
+  keys revoke PREFIX
+  privacy list [-email EMAIL]
+  privacy access EMAIL
+  privacy erase EMAIL
+
+Flags go before a command's arguments. -db, -list and -json are accepted by
+every command. -list picks the mailing list that import, export, send and
+the subscribers commands work on (default: default). With -json, results
+are written to stdout as JSON and errors to stderr as
+{"error":{"message":"..."}}.
+
+Exit codes: 0 success, 1 failure, 2 invalid usage, 3 completed with failed
+records.`
+
+// errIncomplete is wrapped by errors of commands that finished but could not
+// process every record.
+var errIncomplete = errors.New("completed with failures")
+
+// usageError reports an invalid command line.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func errUsage(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// app carries the configuration and global flags shared by all commands.
+type app struct {
+	cfg    *config.Config
+	stdout io.Writer
+	dbPath string
+	// list is the id of the mailing list commands work on.
+	list string
+	json bool
+}
+
+type command func(a *app, args []string) error
+
+var commands = map[string]command{
+	"import":      runImport,
+	"export":      runExport,
+	"subscribers": runSubscribers,
+	"db":          runDB,
+	"send":        runSend,
+	"keys":        runKeys,
+	"privacy":     runPrivacy,
+	"topics":      runTopics,
+	"lists":       runLists,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	cfg := config.LoadConfig()
+	a := &app{cfg: cfg, stdout: os.Stdout, dbPath: cfg.DatabasePath, list: dto.DefaultListID}
+
+	flags := a.flagSet("blogctl")
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	if err := flags.Parse(args); err != nil {
+		return a.exit(err)
+	}
+	if flags.NArg() == 0 {
+		return a.exit(errUsage("missing command"))
+	}
+
+	name := flags.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		return a.exit(errUsage("unknown command %q", name))
+	}
+	return a.exit(cmd(a, flags.Args()[1:]))
+}
+
+// exit reports err and maps it to an exit code.
+func (a *app) exit(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if a.json {
+		body := map[string]map[string]string{"error": {"message": err.Error()}}
+		if encodeErr := json.NewEncoder(os.Stderr).Encode(body); encodeErr != nil {
+			log.Printf("Error writing output: %v", encodeErr)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "blogctl: %v\n", err)
+	}
+
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		if !a.json {
+			fmt.Fprintf(os.Stderr, "\n%s\n", usage)
+		}
+		return exitUsage
+	case errors.Is(err, errIncomplete):
+		return exitIncomplete
+	default:
+		return exitError
+	}
+}
+
+// flagSet returns a flag set that also accepts the global -db, -list and
+// -json flags, so they can be given before or after the command name.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&a.dbPath, "db", a.dbPath, "Path to SQLite database")
+	flags.StringVar(&a.list, "list", a.list, "Mailing list to work on")
+	flags.BoolVar(&a.json, "json", a.json, "Write results as JSON")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", name)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses args, turning flag errors into usage errors.
+func parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage("%v", err)
+	}
+	return nil
+}
+
+// output writes v as JSON with -json, and otherwise calls text to write it
+// for humans.
+func (a *app) output(v any, text func(w io.Writer)) error {
+	if a.json {
+		if err := json.NewEncoder(a.stdout).Encode(v); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+	text(a.stdout)
+	return nil
+}
+
+// openRepository opens and migrates the database. Callers must close it.
+func (a *app) openRepository() (*repositories.SqliteMailingListRepository, error) {
+	repo, err := repositories.NewSqliteMailingListRepository(a.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return repo, nil
+}
+
+// consentLedger returns the consent ledger stored in repo's database.
+func consentLedger(repo *repositories.SqliteMailingListRepository) (*repositories.SqliteConsentRepository, error) {
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize consent ledger: %w", err)
+	}
+	return ledger, nil
+}
+
+// listRepository returns the mailing lists stored in repo's database.
+func listRepository(repo *repositories.SqliteMailingListRepository) (*repositories.SqliteListRepository, error) {
+	lists, err := repositories.NewSqliteListRepository(repo.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize lists: %w", err)
+	}
+	return lists, nil
+}
+
+func closeRepository(repo *repositories.SqliteMailingListRepository) {
+	if closeErr := repo.Close(); closeErr != nil {
+		log.Printf("Error closing database: %v", closeErr)
+	}
+}
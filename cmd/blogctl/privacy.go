@@ -0,0 +1,125 @@
+package main
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+	"time"
+)
+
+// runPrivacy answers data subject requests received outside the website,
+// such as by email, once the requester's identity was checked. Like the
+// self-service flow, every request is recorded in the audit log.
+func runPrivacy(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("privacy requires list, access or erase")
+	}
+	command, args := args[0], args[1:]
+
+	flags := a.flagSet("privacy " + command)
+	email := flags.String("email", "", "Only list the requests about this address (list)")
+	foldAliases := flags.Bool("fold-aliases", a.cfg.EmailFoldAliases, "Apply provider rules such as Gmail ignoring dots and +tags when matching the address")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	normalizer := normalize.Normalizer{FoldAliases: *foldAliases}
+
+	var kind string
+	switch command {
+	case "list":
+		if flags.NArg() != 0 {
+			return errUsage("privacy list takes no arguments")
+		}
+	case dto.PrivacyAccess, "erase":
+		if flags.NArg() != 1 {
+			return errUsage("privacy %s requires one email address", command)
+		}
+		kind = dto.PrivacyAccess
+		if command == "erase" {
+			kind = dto.PrivacyErasure
+		}
+	default:
+		return errUsage("unknown privacy command %q", command)
+	}
+
+	// Requests are logged under a digest keyed with the API's secret
+	if a.cfg.SigningSecret == "" {
+		return fmt.Errorf("SIGNING_SECRET must be set to match the addresses in the privacy request log")
+	}
+	signer := tokens.NewSigner([]byte(a.cfg.SigningSecret))
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	privacy, err := repositories.NewSqlitePrivacyRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize privacy requests: %w", err)
+	}
+
+	if command == "list" {
+		var hash string
+		if *email != "" {
+			hash = signer.Digest(tokens.PurposePrivacyEmail, normalizer.Key(normalize.Email(*email)))
+		}
+		requests, err := privacy.ListRequests(hash)
+		if err != nil {
+			return err
+		}
+		return a.output(requests, func(w io.Writer) {
+			printPrivacyRequests(w, requests)
+		})
+	}
+
+	address := normalize.Email(flags.Arg(0))
+	key := normalizer.Key(address)
+	request := &dto.PrivacyRequest{Kind: kind, EmailHash: signer.Digest(tokens.PurposePrivacyEmail, key), Status: dto.PrivacyPending, Details: "answered with blogctl"}
+	if err := privacy.CreateRequest(request); err != nil {
+		return err
+	}
+
+	if kind == dto.PrivacyAccess {
+		data, err := privacy.Access(request, address, key)
+		if err != nil {
+			return err
+		}
+		// The export is JSON either way
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(data); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+
+	report, err := privacy.Erase(request, address, key)
+	if err != nil {
+		return err
+	}
+	return a.output(dto.PrivacyResult{Request: *request, Erased: report}, func(w io.Writer) {
+		fmt.Fprintf(w, "Erased %s: %s\n", address, request.Details)
+	})
+}
+
+func printPrivacyRequests(w io.Writer, requests []dto.PrivacyRequest) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tKIND\tSTATUS\tREQUESTED\tCOMPLETED\tEMAIL HASH\tDETAILS")
+	for _, request := range requests {
+		completed := "-"
+		if request.CompletedAt != nil {
+			completed = request.CompletedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%.12s\t%s\n", request.ID, request.Kind, request.Status, request.RequestedAt.Format(time.DateTime), completed, request.EmailHash, request.Details)
+	}
+	if err := writer.Flush(); err != nil {
+		log.Printf("Error writing output: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"backend-go/internal/campaigns"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/newsletter"
+	"backend-go/internal/normalize"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// runSend delivers a newsletter to every active subscriber of the -list
+// list. Runs are resumable: sending the same campaign again skips recipients
+// who already got it.
+func runSend(a *app, args []string) error {
+	flags := a.flagSet("send")
+	filePath := flags.String("file", "", "Path to the newsletter (.md or .html)")
+	subject := flags.String("subject", "", "Email subject (default: newsletter title)")
+	campaign := flags.String("campaign", "", "Campaign ID used to resume interrupted runs (default: file name)")
+	dryRun := flags.Bool("dry-run", false, "List recipients without sending")
+	testTo := flags.String("test-to", "", "Send a single preview to this address instead of the list")
+	topic := flags.String("topic", "", "Only send to subscribers of this topic or of every topic")
+	frequency := flags.String("frequency", "", "Only send to subscribers with this frequency (immediate, weekly or monthly)")
+	retryUncertain := flags.Bool("retry-uncertain", false, "Send again to recipients whose previous send was interrupted, who may get a second copy")
+	markSent := flags.String("mark-sent", "", "Comma-separated recipients whose interrupted send arrived, to record as sent")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if *filePath == "" {
+		return errUsage("send requires -file")
+	}
+	if *frequency != "" && !validators.ValidFrequency(*frequency) {
+		return errUsage("-frequency must be immediate, weekly or monthly")
+	}
+
+	var markSentEmails []string
+	for _, email := range strings.Split(*markSent, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			markSentEmails = append(markSentEmails, normalize.Email(email))
+		}
+	}
+
+	issue, err := newsletter.Load(*filePath)
+	if err != nil {
+		return fmt.Errorf("failed to load newsletter: %w", err)
+	}
+
+	if *subject == "" {
+		*subject = issue.Title
+	}
+	if *subject == "" {
+		return errUsage("newsletter has no title, pass -subject")
+	}
+
+	if *campaign == "" {
+		*campaign = strings.TrimSuffix(filepath.Base(*filePath), filepath.Ext(*filePath))
+	}
+
+	secret := []byte(a.cfg.SigningSecret)
+	if len(secret) == 0 {
+		if !*dryRun && *testTo == "" {
+			return fmt.Errorf("SIGNING_SECRET must be set, otherwise unsubscribe links in the newsletter will not work")
+		}
+		secret = tokens.RandomSecret()
+	}
+	signer := tokens.NewSigner(secret)
+
+	mailer, err := mailers.NewMailer(a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	lists, err := listRepository(repo)
+	if err != nil {
+		return err
+	}
+	list, err := lists.GetList(a.list)
+	if err != nil {
+		return err
+	}
+	builder, err := emails.NewListBuilder(*list, a.cfg.MailFrom, a.cfg.BaseURL, a.cfg.TemplateDir, signer,
+		emails.WithPreferencesPage(a.cfg.PreferencesPageURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := repositories.NewSqliteDeliveryRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize delivery log: %w", err)
+	}
+
+	sender := campaigns.NewSender(repo, deliveries, mailer, builder)
+
+	log.Printf("Sending campaign %q: %s", *campaign, *subject)
+	report, err := sender.Send(*campaign, *subject, issue, campaigns.Options{
+		TestTo:         *testTo,
+		Topic:          *topic,
+		Frequency:      *frequency,
+		DryRun:         *dryRun,
+		RetryUncertain: *retryUncertain,
+		MarkSent:       markSentEmails,
+	})
+	if report == nil {
+		return fmt.Errorf("campaign aborted: %w", err)
+	}
+
+	if outputErr := a.output(report, func(w io.Writer) {
+		for _, email := range report.Planned {
+			fmt.Fprintf(w, "Would send to %s\n", email)
+		}
+		for _, email := range report.Uncertain {
+			fmt.Fprintf(w, "Warning: previous send to %s was interrupted, skipping to avoid a duplicate\n", email)
+		}
+
+		fmt.Fprintf(w, "\n=== Campaign %s ===\n", *campaign)
+		fmt.Fprintf(w, "Recipients: %d\n", report.Recipients)
+		fmt.Fprintf(w, "Sent: %d\n", report.Sent)
+		fmt.Fprintf(w, "Skipped (already sent): %d\n", report.Skipped)
+		fmt.Fprintf(w, "Skipped (interrupted): %d\n", len(report.Uncertain))
+		fmt.Fprintf(w, "Failed: %d\n", report.Failed)
+		if *dryRun {
+			fmt.Fprintf(w, "Dry run: %d emails would be sent\n", len(report.Planned))
+		}
+		if report.Failed > 0 {
+			fmt.Fprintln(w, "\nRun the same command again to retry failed recipients.")
+		}
+		if len(report.Uncertain) > 0 {
+			fmt.Fprintf(w, "\n%d recipients were skipped because their previous send was interrupted. Check the mail server's logs, then run the same command with -mark-sent EMAIL[,EMAIL...] for those who got the issue or -retry-uncertain to send to them again.\n", len(report.Uncertain))
+		}
+	}); outputErr != nil {
+		return outputErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: campaign aborted: %w", errIncomplete, err)
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%w: %d recipients failed", errIncomplete, report.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"backend-go/internal/validators"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runSubscribers(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("subscribers requires list, add, remove, dedupe or consent")
+	}
+
+	switch args[0] {
+	case "list":
+		return listSubscribers(a, args[1:])
+	case "add":
+		return addSubscriber(a, args[1:])
+	case "remove":
+		return removeSubscriber(a, args[1:])
+	case "dedupe":
+		return dedupeSubscribers(a, args[1:])
+	case "consent":
+		return listConsentEvents(a, args[1:])
+	default:
+		return errUsage("unknown subscribers command %q", args[0])
+	}
+}
+
+func listSubscribers(a *app, args []string) error {
+	flags := a.flagSet("subscribers list")
+	status := flags.String("status", "", "Only list pending, active or unsubscribed subscribers")
+	search := flags.String("q", "", "Only list subscribers whose email or username contains this text")
+	limit := flags.Int("limit", 0, "Maximum number of subscribers to list (default: all)")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	switch *status {
+	case "", dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed:
+	default:
+		return errUsage("-status must be %s, %s or %s", dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed)
+	}
+	if *limit < 0 {
+		return errUsage("-limit must not be negative")
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	query := dto.SubscriberQuery{Status: *status, Search: *search}
+	subscribers := []dto.MailingList{}
+	for {
+		query.Limit = handlers.MaxPageSize
+		if remaining := *limit - len(subscribers); *limit > 0 && remaining < query.Limit {
+			query.Limit = remaining
+		}
+
+		page, err := repo.List(a.list, query)
+		if err != nil {
+			return fmt.Errorf("failed to list subscribers: %w", err)
+		}
+		subscribers = append(subscribers, page.Subscribers...)
+		if page.NextCursor == "" || (*limit > 0 && len(subscribers) >= *limit) {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	return a.output(subscribers, func(w io.Writer) {
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "EMAIL\tUSERNAME\tSTATUS\tCREATED")
+		for _, subscriber := range subscribers {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", subscriber.Email, subscriber.Username, subscriber.Status, subscriber.CreatedAt.Format(time.DateTime))
+		}
+		if err := writer.Flush(); err != nil {
+			log.Printf("Error writing output: %v", err)
+		}
+	})
+}
+
+// addSubscriber stores an active subscriber, skipping double opt-in. The
+// address is validated like a signup, without the DNS check.
+func addSubscriber(a *app, args []string) error {
+	flags := a.flagSet("subscribers add")
+	username := flags.String("username", "", "Display name (default: the email's local part)")
+	foldAliases := flags.Bool("fold-aliases", a.cfg.EmailFoldAliases, "Apply provider rules such as Gmail ignoring dots and +tags when detecting duplicates")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage("subscribers add requires one email address")
+	}
+
+	email := normalize.Email(flags.Arg(0))
+	if *username == "" {
+		*username, _, _ = strings.Cut(email, "@")
+	}
+	subscriber := &dto.MailingList{
+		Username:        *username,
+		Email:           email,
+		NormalizedEmail: normalize.Normalizer{FoldAliases: *foldAliases}.Key(email),
+		Status:          dto.StatusActive,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if fieldErrors := validators.NewMailingListValidator().Validate(subscriber); len(fieldErrors) > 0 {
+		return &handlers.ValidationError{Errors: fieldErrors}
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	ledger, err := consentLedger(repo)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Save(a.list, subscriber); err != nil {
+		return fmt.Errorf("failed to add subscriber: %w", err)
+	}
+	if subscriber.Status == dto.StatusActive {
+		consent := handlers.Consent{Ledger: ledger, Event: dto.ConsentEvent{Source: dto.ConsentSourceAdmin}}
+		if err := consent.Record(dto.ConsentSubscribe, *subscriber); err != nil {
+			return err
+		}
+	}
+
+	return a.output(subscriber, func(w io.Writer) {
+		if subscriber.Status == dto.StatusActive {
+			fmt.Fprintf(w, "Subscribed %s\n", subscriber.Email)
+		} else {
+			fmt.Fprintf(w, "%s is already on the list with status %s\n", subscriber.Email, subscriber.Status)
+		}
+	})
+}
+
+func removeSubscriber(a *app, args []string) error {
+	flags := a.flagSet("subscribers remove")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage("subscribers remove requires one email address")
+	}
+	email := flags.Arg(0)
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+	ledger, err := consentLedger(repo)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Delete(a.list, email); err != nil {
+		if errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			return fmt.Errorf("%s is not on the %s mailing list", email, a.list)
+		}
+		return fmt.Errorf("failed to remove subscriber: %w", err)
+	}
+	consent := handlers.Consent{Ledger: ledger, Event: dto.ConsentEvent{Source: dto.ConsentSourceAdmin}}
+	if err := consent.Record(dto.ConsentUnsubscribe, dto.MailingList{ListID: a.list, Email: email}); err != nil {
+		return err
+	}
+
+	return a.output(map[string]string{"removed": email}, func(w io.Writer) {
+		fmt.Fprintf(w, "Removed %s\n", email)
+	})
+}
+
+func dedupeSubscribers(a *app, args []string) error {
+	flags := a.flagSet("subscribers dedupe")
+	foldAliases := flags.Bool("fold-aliases", a.cfg.EmailFoldAliases, "Apply provider rules such as Gmail ignoring dots and +tags")
+	dryRun := flags.Bool("dry-run", false, "Only report duplicates without merging them")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	groups, err := repo.Dedupe(a.list, normalize.Normalizer{FoldAliases: *foldAliases}, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to merge duplicates: %w", err)
+	}
+	if groups == nil {
+		groups = []dto.DuplicateGroup{}
+	}
+
+	return a.output(groups, func(w io.Writer) {
+		merged := 0
+		for _, group := range groups {
+			fmt.Fprintf(w, "%s: keeping %s (%s), merging %s\n", group.NormalizedEmail, group.Kept, group.Status, strings.Join(group.Merged, ", "))
+			merged += len(group.Merged)
+		}
+
+		switch {
+		case len(groups) == 0:
+			fmt.Fprintln(w, "No duplicate subscribers found")
+		case *dryRun:
+			fmt.Fprintf(w, "Would merge %d subscribers into %d, run without -dry-run to apply\n", merged, len(groups))
+		default:
+			fmt.Fprintf(w, "Merged %d subscribers into %d\n", merged, len(groups))
+		}
+	})
+}
+
+// listConsentEvents prints the consent ledger of one subscriber.
+func listConsentEvents(a *app, args []string) error {
+	flags := a.flagSet("subscribers consent")
+	foldAliases := flags.Bool("fold-aliases", a.cfg.EmailFoldAliases, "Apply provider rules such as Gmail ignoring dots and +tags when matching the address")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage("subscribers consent requires one email address")
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+	ledger, err := consentLedger(repo)
+	if err != nil {
+		return err
+	}
+
+	events, err := handlers.HandleConsentEvents(flags.Arg(0), ledger, normalize.Normalizer{FoldAliases: *foldAliases})
+	if err != nil {
+		return err
+	}
+
+	return a.output(events, func(w io.Writer) {
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TIME\tLIST\tEVENT\tSOURCE\tEMAIL\tPOLICY\tPAGE\tIP HASH\tUSER AGENT")
+		for _, event := range events {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%.12s\t%s\n", event.CreatedAt.Format(time.DateTime), event.ListID, event.Event, event.Source, event.Email,
+				orDash(event.PolicyVersion), orDash(event.PageURL), orDash(event.IPHash), orDash(event.UserAgent))
+		}
+		if err := writer.Flush(); err != nil {
+			log.Printf("Error writing output: %v", err)
+		}
+	})
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
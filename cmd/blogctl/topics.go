@@ -0,0 +1,89 @@
+package main
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"backend-go/internal/validators"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+	"time"
+)
+
+func runTopics(a *app, args []string) error {
+	if len(args) == 0 {
+		return errUsage("topics requires list, add or remove")
+	}
+	command, args := args[0], args[1:]
+
+	flags := a.flagSet("topics " + command)
+	name := flags.String("name", "", "Name shown to subscribers (add, default: the slug)")
+	description := flags.String("description", "", "Description shown on the preference page (add)")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	repo, err := a.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeRepository(repo)
+
+	topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+	if err != nil {
+		return fmt.Errorf("failed to initialize topics: %w", err)
+	}
+
+	switch command {
+	case "list":
+		list, err := topics.ListTopics()
+		if err != nil {
+			return err
+		}
+		return a.output(list, func(w io.Writer) {
+			printTopics(w, list)
+		})
+	case "add":
+		if flags.NArg() != 1 {
+			return errUsage("topics add requires the topic slug")
+		}
+		topic := &dto.Topic{Slug: flags.Arg(0), Name: *name, Description: *description}
+		if !validators.ValidTopicSlug(topic.Slug) {
+			return errUsage("topic slugs are lowercase letters and digits separated by dashes, got %q", topic.Slug)
+		}
+		if topic.Name == "" {
+			topic.Name = topic.Slug
+		}
+		if err := topics.CreateTopic(topic); err != nil {
+			return err
+		}
+		return a.output(topic, func(w io.Writer) {
+			fmt.Fprintf(w, "Added topic %s (%s)\n", topic.Slug, topic.Name)
+		})
+	case "remove":
+		if flags.NArg() != 1 {
+			return errUsage("topics remove requires the topic slug")
+		}
+		slug := flags.Arg(0)
+		if err := topics.DeleteTopic(slug); err != nil {
+			return err
+		}
+		return a.output(map[string]string{"removed": slug}, func(w io.Writer) {
+			fmt.Fprintf(w, "Removed topic %s\n", slug)
+		})
+	default:
+		return errUsage("unknown topics command %q", command)
+	}
+}
+
+func printTopics(w io.Writer, topics []dto.Topic) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SLUG\tNAME\tDESCRIPTION\tCREATED")
+	for _, topic := range topics {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", topic.Slug, topic.Name, orDash(topic.Description), topic.CreatedAt.Format(time.DateTime))
+	}
+	if err := writer.Flush(); err != nil {
+		log.Printf("Error writing output: %v", err)
+	}
+}
@@ -8,3 +8,8 @@ require (
 )
 
 require github.com/mattn/go-sqlite3 v1.14.32
+
+require (
+	golang.org/x/net v0.42.0
+	golang.org/x/text v0.27.0 // indirect
+)
@@ -0,0 +1,93 @@
+package antispam
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRejected is wrapped by every error a Check returns.
+var ErrRejected = errors.New("submission rejected")
+
+// ErrTrapped is returned for submissions that filled in the honeypot field.
+// They should get the normal response and be dropped, so the bot doesn't
+// learn it was caught.
+var ErrTrapped = fmt.Errorf("%w: honeypot field was filled in", ErrRejected)
+
+// Submission is the part of a signup request the checks look at.
+type Submission struct {
+	ReceivedAt time.Time
+	// Honeypot is a form field hidden from humans; bots tend to fill it.
+	Honeypot string
+	// FormToken is the token from GET /mailing_list/form-token.
+	FormToken string
+	// Nonce solves the proof-of-work challenge of FormToken.
+	Nonce string
+}
+
+// Check inspects a submission and returns an error wrapping ErrRejected when
+// it looks automated.
+type Check func(submission Submission) error
+
+// Run runs checks in order and returns the first rejection.
+func Run(checks []Check, submission Submission) error {
+	for _, check := range checks {
+		if err := check(submission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Honeypot rejects submissions that filled in the honeypot field.
+func Honeypot() Check {
+	return func(submission Submission) error {
+		if submission.Honeypot != "" {
+			return ErrTrapped
+		}
+		return nil
+	}
+}
+
+// MinimumAge rejects submissions without a valid form token and those sent
+// sooner than the form's MinAge after the token was issued, which humans
+// filling in the form don't manage.
+func MinimumAge(forms *Forms) Check {
+	return func(submission Submission) error {
+		issuedAt, err := forms.Verify(submission.FormToken)
+		if err != nil {
+			return err
+		}
+		if submission.ReceivedAt.Sub(issuedAt) < forms.MinAge {
+			return fmt.Errorf("%w: form was submitted too quickly", ErrRejected)
+		}
+		return nil
+	}
+}
+
+// ProofOfWork rejects submissions whose nonce doesn't solve the challenge
+// of their form token at the form's Difficulty.
+func ProofOfWork(forms *Forms) Check {
+	return func(submission Submission) error {
+		if _, err := forms.Verify(submission.FormToken); err != nil {
+			return err
+		}
+		if !Solves(submission.FormToken, submission.Nonce, forms.Difficulty) {
+			return fmt.Errorf("%w: invalid proof of work", ErrRejected)
+		}
+		return nil
+	}
+}
+
+// SingleUse rejects form tokens that were already submitted, so a solved
+// token can't be replayed for more signups. It goes after the other checks
+// so only submissions that pass them use up their token.
+func SingleUse(forms *Forms) Check {
+	return func(submission Submission) error {
+		issuedAt, err := forms.Verify(submission.FormToken)
+		if err != nil {
+			return err
+		}
+		return forms.Redeem(submission.FormToken, issuedAt, submission.ReceivedAt)
+	}
+}
@@ -0,0 +1,149 @@
+package antispam
+
+import (
+	"backend-go/internal/tokens"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is how long a form token stays valid by default.
+const DefaultMaxAge = 24 * time.Hour
+
+// MaxDifficulty caps the proof-of-work difficulty so a misconfiguration
+// can't make the form unusable.
+const MaxDifficulty = 32
+
+// FormToken is returned by GET /mailing_list/form-token.
+type FormToken struct {
+	Token string `json:"token"`
+	// Difficulty is the number of leading zero bits the SHA-256 of
+	// "<token>:<nonce>" must have; 0 when no proof of work is required.
+	Difficulty int `json:"difficulty"`
+}
+
+// Forms issues and verifies signed "form issued at" tokens for the signup
+// form. A token doubles as the proof-of-work challenge.
+type Forms struct {
+	signer *tokens.Signer
+	// MinAge is how long after issuing a token the form may be submitted.
+	MinAge time.Duration
+	// MaxAge is how long a token stays valid.
+	MaxAge     time.Duration
+	Difficulty int
+
+	mu sync.Mutex
+	// used maps the tokens submitted so far to when they expire.
+	used      map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewForms(signer *tokens.Signer, minAge, maxAge time.Duration, difficulty int) *Forms {
+	return &Forms{
+		signer:     signer,
+		MinAge:     minAge,
+		MaxAge:     maxAge,
+		Difficulty: min(max(difficulty, 0), MaxDifficulty),
+		used:       make(map[string]time.Time),
+	}
+}
+
+// Issue returns a token issued at now. Tokens carry a random suffix so two
+// forms loaded in the same millisecond don't share one.
+func (f *Forms) Issue(now time.Time) FormToken {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		panic(fmt.Sprintf("antispam: failed to read random suffix: %v", err))
+	}
+	subject := strconv.FormatInt(now.UnixMilli(), 10) + "." + hex.EncodeToString(suffix)
+	return FormToken{
+		Token:      f.signer.Sign(tokens.PurposeSignupForm, subject, now.Add(f.MaxAge)),
+		Difficulty: f.Difficulty,
+	}
+}
+
+// Verify checks token and returns when it was issued.
+func (f *Forms) Verify(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, fmt.Errorf("%w: form token is required", ErrRejected)
+	}
+
+	subject, err := f.signer.Verify(tokens.PurposeSignupForm, token)
+	if errors.Is(err, tokens.ErrExpired) {
+		return time.Time{}, fmt.Errorf("%w: form has expired, reload the page", ErrRejected)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid form token", ErrRejected)
+	}
+
+	millis, _, _ := strings.Cut(subject, ".")
+	issued, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid form token", ErrRejected)
+	}
+	return time.UnixMilli(issued), nil
+}
+
+// Redeem marks token, issued at issuedAt, as submitted and rejects tokens
+// that were submitted before. Tokens are remembered until they expire, after
+// which Verify rejects them anyway.
+func (f *Forms) Redeem(token string, issuedAt, now time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sweep(now)
+
+	if _, ok := f.used[token]; ok {
+		return fmt.Errorf("%w: form was already submitted, reload the page", ErrRejected)
+	}
+	f.used[token] = issuedAt.Add(f.MaxAge)
+	return nil
+}
+
+// sweep forgets expired tokens at most once a minute.
+func (f *Forms) sweep(now time.Time) {
+	if now.Sub(f.lastSweep) < time.Minute {
+		return
+	}
+	f.lastSweep = now
+
+	for token, expiresAt := range f.used {
+		if now.After(expiresAt) {
+			delete(f.used, token)
+		}
+	}
+}
+
+// Solves reports whether the SHA-256 of "<challenge>:<nonce>" starts with at
+// least difficulty zero bits.
+func Solves(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+	return zeros >= difficulty
+}
+
+// Solve finds a nonce for challenge by brute force, the same way the signup
+// form does in the browser.
+func Solve(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		if Solves(challenge, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
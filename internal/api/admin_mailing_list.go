@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/export"
+)
+
+// listMailingList handles GET /admin/mailing_list and
+// /admin/lists/{listID}/subscribers.
+func (s *Server) listMailingList(w http.ResponseWriter, r *http.Request) {
+	query, err := handlers.ParseSubscriberQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := handlers.HandleList(s.listFor(r).ID, query, s.mailingListRepository)
+	switch {
+	case errors.Is(err, handlers.ErrInvalidQuery):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error listing subscribers: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list subscribers")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// exportMailingList handles GET /admin/mailing_list/export. It takes the
+// filters of listMailingList, without paging, and a format of csv (the
+// default), ndjson or vcard, and streams every matching subscriber.
+func (s *Server) exportMailingList(w http.ResponseWriter, r *http.Request) {
+	query, err := handlers.ParseSubscriberQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if export.ContentType(format) == "" {
+		writeError(w, http.StatusBadRequest, "format must be one of "+strings.Join(export.Formats(), ", "))
+		return
+	}
+
+	// Large lists take longer than the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Error lifting write deadline for export: %v", err)
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename(format)))
+	exported, err := export.Export(s.mailingListRepository, s.listFor(r).ID, query, format, w)
+	if err == nil {
+		return
+	}
+
+	log.Printf("Error exporting subscribers after %d rows: %v", exported, err)
+	if exported == 0 {
+		w.Header().Del("Content-Disposition")
+		writeError(w, http.StatusInternalServerError, "failed to export subscribers")
+		return
+	}
+	// The status was already sent, so abort the response to keep the client
+	// from taking a truncated export for a complete one
+	panic(http.ErrAbortHandler)
+}
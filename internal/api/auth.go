@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+)
+
+type contextKey int
+
+const (
+	apiKeyContextKey contextKey = iota
+	listContextKey
+)
+
+// authenticate requires a valid API key in the Authorization header as a
+// bearer token and stores it in the request context.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.apiKeys == nil {
+			unauthorized(w)
+			return
+		}
+
+		key, err := s.apiKeys.Authenticate(token)
+		switch {
+		case errors.Is(err, apikeys.ErrInvalidKey):
+			unauthorized(w)
+			return
+		case err != nil:
+			log.Printf("Error authenticating api key: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to authenticate")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey, key)))
+	})
+}
+
+// requireScope rejects authenticated requests whose key lacks scope.
+func (s *Server) requireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := r.Context().Value(apiKeyContextKey).(*dto.APIKey)
+			if !ok || !key.HasScope(scope) {
+				writeError(w, http.StatusForbidden, "api key lacks the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+	writeError(w, http.StatusUnauthorized, "unauthorized")
+}
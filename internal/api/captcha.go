@@ -0,0 +1,33 @@
+package api
+
+import (
+	"backend-go/internal/captcha"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// verifyCaptcha checks the CAPTCHA token of a signup and writes the error
+// response when it doesn't pass.
+func (s *Server) verifyCaptcha(w http.ResponseWriter, r *http.Request, token string) bool {
+	if s.captcha == nil || (token == "" && !s.captchaRequired) {
+		return true
+	}
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "captchaToken is required")
+		return false
+	}
+
+	err := s.captcha.Verify(r.Context(), token, s.clientIP(r))
+	switch {
+	case errors.Is(err, captcha.ErrFailed):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	case err != nil:
+		log.Printf("Error verifying captcha: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "captcha verification is unavailable, try again later")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+)
+
+func (s *Server) confirmMailingList(w http.ResponseWriter, r *http.Request) {
+	consent := s.consentFor(r, dto.ConsentSourceEmail, "")
+	list := s.listFor(r)
+	mailingList, err := handlers.HandleConfirm(list.ID, r.URL.Query().Get("token"), s.mailingListRepository, consent)
+	switch {
+	case errors.Is(err, handlers.ErrTokenRequired):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, interfaces.ErrInvalidToken):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error confirming subscription: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to confirm subscription")
+		return
+	}
+
+	s.notify(list.emails.Welcome(mailingList))
+
+	writeJSON(w, http.StatusOK, mailingList)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/tokens"
+)
+
+// maxConsentField bounds the user agent and page URL stored per event, as
+// both come from the client.
+const maxConsentField = 512
+
+// consentFor describes how r changed a subscription, for the consent ledger.
+// Without a ledger it records nothing.
+func (s *Server) consentFor(r *http.Request, source, pageURL string) handlers.Consent {
+	if s.consent == nil {
+		return handlers.Consent{}
+	}
+
+	return handlers.Consent{
+		Ledger: s.consent,
+		Event: dto.ConsentEvent{
+			Source:        source,
+			IPHash:        s.signer.Digest(tokens.PurposeConsentIP, s.clientIP(r)),
+			UserAgent:     clip(r.UserAgent(), maxConsentField),
+			PageURL:       clip(pageURL, maxConsentField),
+			PolicyVersion: s.policyVersion,
+		},
+	}
+}
+
+// listConsentEvents handles GET /admin/mailing_list/consent?email=...
+func (s *Server) listConsentEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := handlers.HandleConsentEvents(r.URL.Query().Get("email"), s.consent, s.normalizer)
+	switch {
+	case errors.Is(err, handlers.ErrInvalidQuery):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error listing consent events: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list consent events")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// clip shortens s to at most n bytes without splitting a UTF-8 sequence.
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -4,21 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"log"
 	"net/http"
+	"time"
 
+	"backend-go/internal/antispam"
 	"backend-go/internal/api/handlers"
 	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
 )
 
 func (s *Server) createMailingList(w http.ResponseWriter, r *http.Request) {
-	var newMailingList dto.MailingList
+	var signup dto.Signup
 
-	err := json.NewDecoder(r.Body).Decode(&newMailingList)
+	err := json.NewDecoder(r.Body).Decode(&signup)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
 		var msg string
 		if errors.Is(err, io.EOF) {
 			msg = "request body is empty"
@@ -26,35 +25,70 @@ func (s *Server) createMailingList(w http.ResponseWriter, r *http.Request) {
 			msg = "invalid JSON: " + err.Error()
 		}
 
-		errorResponse := map[string]map[string]string{
-			"error": {
-				"message": msg,
-			},
-		}
-		if encodeErr := json.NewEncoder(w).Encode(errorResponse); encodeErr != nil {
-			log.Default().Print(encodeErr)
-		}
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	err = antispam.Run(s.signupChecks, antispam.Submission{
+		ReceivedAt: time.Now(),
+		Honeypot:   signup.Website,
+		FormToken:  signup.FormToken,
+		Nonce:      signup.Nonce,
+	})
+	if errors.Is(err, antispam.ErrTrapped) {
+		writeJSON(w, http.StatusCreated, pendingSignup(s.listFor(r).ID, signup.MailingList))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !s.verifyCaptcha(w, r, signup.CaptchaToken) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	if !s.allowEmail(w, signup.Email) {
+		return
+	}
+
+	pageURL := signup.PageURL
+	if pageURL == "" {
+		pageURL = r.Referer()
+	}
+	consent := s.consentFor(r, dto.ConsentSourceForm, pageURL)
 
-	mailingList, err := handlers.HandleCreate(newMailingList, s.mailingListRepository)
+	list := s.listFor(r)
+	mailingList, err := handlers.HandleCreate(list.ID, signup.MailingList, s.mailingListRepository, s.validator, s.normalizer, consent)
+	var validationErr *handlers.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationProblem(w, validationErr.Errors)
+		return
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		errorResponse := map[string]map[string]string{
-			"error": {
-				"message": err.Error(),
-			},
-		}
-		if encodeErr := json.NewEncoder(w).Encode(errorResponse); encodeErr != nil {
-			log.Default().Print(encodeErr)
-		}
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	if encodeErr := json.NewEncoder(w).Encode(mailingList); encodeErr != nil {
-		log.Default().Print(encodeErr)
+	if mailingList.ConfirmationToken != "" {
+		s.notify(list.emails.Confirmation(mailingList))
+	}
+
+	// The stored state would tell anyone whether the address is subscribed
+	writeJSON(w, http.StatusCreated, pendingSignup(list.ID, signup.MailingList))
+}
+
+// pendingSignup is the response to every accepted signup of newMailingList
+// to the list with listID: the same whether the address was new, pending,
+// already subscribed or dropped as a bot.
+func pendingSignup(listID string, newMailingList dto.MailingList) dto.MailingList {
+	return dto.MailingList{
+		CreatedAt: time.Now(),
+		ListID:    listID,
+		Username:  newMailingList.Username,
+		Email:     normalize.Email(newMailingList.Email),
+		Status:    dto.StatusPending,
+		Frequency: dto.FrequencyImmediate,
+		Topics:    newMailingList.Topics,
 	}
 }
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// formToken issues the token the signup form sends back with its
+// submission, along with the proof-of-work difficulty.
+func (s *Server) formToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, s.forms.Issue(time.Now()))
+}
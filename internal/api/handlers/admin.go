@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Page sizes for the admin subscriber list.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ErrInvalidQuery is wrapped by every error ParseSubscriberQuery returns.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// ParseSubscriberQuery reads the admin list filters from URL parameters:
+// status, created_after, created_before (RFC 3339 or YYYY-MM-DD), q, cursor
+// and limit.
+func ParseSubscriberQuery(values url.Values) (dto.SubscriberQuery, error) {
+	query := dto.SubscriberQuery{
+		Status: values.Get("status"),
+		Search: values.Get("q"),
+		Cursor: values.Get("cursor"),
+		Limit:  DefaultPageSize,
+	}
+
+	switch query.Status {
+	case "", dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed:
+	default:
+		return query, fmt.Errorf("%w: status must be %s, %s or %s", ErrInvalidQuery, dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed)
+	}
+
+	var err error
+	if query.CreatedAfter, err = parseQueryTime(values, "created_after"); err != nil {
+		return query, err
+	}
+	if query.CreatedBefore, err = parseQueryTime(values, "created_before"); err != nil {
+		return query, err
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 || query.Limit > MaxPageSize {
+			return query, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageSize)
+		}
+	}
+
+	return query, nil
+}
+
+// HandleList returns one page of the subscribers of the list with listID
+// matching query.
+func HandleList(listID string, query dto.SubscriberQuery, repo interfaces.MailingListRepository) (*dto.SubscriberPage, error) {
+	page, err := repo.List(listID, query)
+	if errors.Is(err, interfaces.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	return page, err
+}
+
+func parseQueryTime(values url.Values, key string) (time.Time, error) {
+	value := values.Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidQuery, key)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"fmt"
+)
+
+// Consent records the state changes made by a handler in the consent ledger.
+// The zero value records nothing.
+type Consent struct {
+	Ledger interfaces.ConsentRepository
+	// Event describes how the change was made, such as its source and the
+	// client's hashed IP. The handler fills in the event type and address.
+	Event dto.ConsentEvent
+}
+
+// Record appends an event of type event about subscriber to the ledger.
+func (c Consent) Record(event string, subscriber dto.MailingList) error {
+	if c.Ledger == nil {
+		return nil
+	}
+
+	record := c.Event
+	record.Event = event
+	record.ListID = subscriber.ListID
+	record.Email = subscriber.Email
+	record.NormalizedEmail = subscriber.NormalizedEmail
+	if err := c.Ledger.Record(&record); err != nil {
+		return fmt.Errorf("failed to record %s of %s: %w", event, subscriber.Email, err)
+	}
+	return nil
+}
+
+// HandleConsentEvents returns the consent ledger of the subscriber with
+// email, oldest event first.
+func HandleConsentEvents(email string, ledger interfaces.ConsentRepository, normalizer normalize.Normalizer) ([]dto.ConsentEvent, error) {
+	email = normalize.Email(email)
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", ErrInvalidQuery)
+	}
+	return ledger.ListEvents(email, normalizer.Key(email))
+}
@@ -3,25 +3,126 @@ package handlers
 import (
 	"backend-go/internal/dto"
 	"backend-go/internal/interfaces"
-	"backend-go/internal/validators"
+	"backend-go/internal/normalize"
+	"backend-go/internal/tokens"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"time"
 )
 
-func HandleCreate(newMailingList dto.MailingList, repo interfaces.MailingListRepository) (dto.MailingList, error) {
-	validator := validators.NewMailingListValidator()
-	if err := validator.Validate(&newMailingList); err != nil {
+// ConfirmationTTL is how long a signup can be confirmed after it was made.
+const ConfirmationTTL = 48 * time.Hour
+
+var ErrTokenRequired = errors.New("token is required")
+
+// ValidationError is returned by HandleCreate when fields are invalid.
+type ValidationError struct {
+	Errors []dto.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// HandleCreate normalizes, validates and stores a pending signup to the list
+// with listID with the topics it chose. When the returned entry carries a
+// ConfirmationToken the caller is expected to send the confirmation email,
+// and the signup is recorded in the consent ledger.
+func HandleCreate(listID string, newMailingList dto.MailingList, repo interfaces.MailingListRepository, validator interfaces.MailingListValidator, normalizer normalize.Normalizer, consent Consent) (dto.MailingList, error) {
+	newMailingList.Email = normalize.Email(newMailingList.Email)
+
+	if fieldErrors := validator.Validate(&newMailingList); len(fieldErrors) > 0 {
+		return newMailingList, &ValidationError{Errors: fieldErrors}
+	}
+
+	token, err := newConfirmationToken()
+	if err != nil {
 		return newMailingList, err
 	}
 
+	now := time.Now()
 	mailingList := &dto.MailingList{
-		Username:  newMailingList.Username,
-		Email:     newMailingList.Email,
-		CreatedAt: time.Now(),
+		Username:              newMailingList.Username,
+		Email:                 newMailingList.Email,
+		NormalizedEmail:       normalizer.Key(newMailingList.Email),
+		CreatedAt:             now,
+		Status:                dto.StatusPending,
+		ConfirmationToken:     token,
+		ConfirmationExpiresAt: now.Add(ConfirmationTTL),
+		Topics:                newMailingList.Topics,
 	}
 
-	if err := repo.Save(mailingList); err != nil {
+	err = repo.Save(listID, mailingList)
+	if errors.Is(err, interfaces.ErrTopicNotFound) {
+		return newMailingList, unknownTopic(err)
+	}
+	if err != nil {
 		return newMailingList, err
 	}
 
+	// Saving an address that is already active changes nothing
+	if mailingList.ConfirmationToken != "" {
+		if err := consent.Record(dto.ConsentSubscribe, *mailingList); err != nil {
+			return newMailingList, err
+		}
+	}
+
+	return *mailingList, nil
+}
+
+// HandleConfirm activates the signup to the list with listID identified by
+// token and records it in the consent ledger.
+func HandleConfirm(listID, token string, repo interfaces.MailingListRepository, consent Consent) (dto.MailingList, error) {
+	if token == "" {
+		return dto.MailingList{}, ErrTokenRequired
+	}
+
+	mailingList, err := repo.Confirm(listID, token)
+	if err != nil {
+		return dto.MailingList{}, err
+	}
+	if err := consent.Record(dto.ConsentConfirm, *mailingList); err != nil {
+		return dto.MailingList{}, err
+	}
+
 	return *mailingList, nil
 }
+
+// HandleUnsubscribe verifies an unsubscribe token signed for the list with
+// listID, unsubscribes the address it was issued for from that list and
+// records it in the consent ledger. Repeated unsubscribes are recorded too,
+// as each is a request to stop emailing.
+func HandleUnsubscribe(listID, token string, signer *tokens.Signer, repo interfaces.MailingListRepository, consent Consent) (dto.MailingList, error) {
+	if token == "" {
+		return dto.MailingList{}, ErrTokenRequired
+	}
+
+	email, err := signer.Verify(tokens.ListPurpose(tokens.PurposeUnsubscribe, listID), token)
+	if err != nil {
+		return dto.MailingList{}, interfaces.ErrInvalidToken
+	}
+
+	mailingList, err := repo.Unsubscribe(listID, email)
+	if err != nil {
+		return dto.MailingList{}, err
+	}
+	if err := consent.Record(dto.ConsentUnsubscribe, *mailingList); err != nil {
+		return dto.MailingList{}, err
+	}
+
+	return *mailingList, nil
+}
+
+func newConfirmationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"errors"
+	"strings"
+)
+
+// HandlePreferences verifies a preferences token signed for the list with
+// listID and returns the preferences of the address it was issued for.
+func HandlePreferences(listID, token string, signer *tokens.Signer, topics interfaces.TopicRepository) (*dto.Preferences, error) {
+	email, err := verifyPreferencesToken(listID, token, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return topics.Preferences(listID, email)
+}
+
+// HandleUpdatePreferences verifies a preferences token signed for the list
+// with listID and changes the display name, frequency and topics of the
+// address it was issued for on that list.
+// The subscription itself is left alone, so no confirmation is needed.
+func HandleUpdatePreferences(listID, token string, update dto.PreferencesUpdate, signer *tokens.Signer, topics interfaces.TopicRepository) (*dto.Preferences, error) {
+	email, err := verifyPreferencesToken(listID, token, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		update.Username = &username
+	}
+	if fieldErrors := validators.ValidatePreferences(update); len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	preferences, err := topics.UpdatePreferences(listID, email, update)
+	if errors.Is(err, interfaces.ErrTopicNotFound) {
+		return nil, unknownTopic(err)
+	}
+	return preferences, err
+}
+
+func verifyPreferencesToken(listID, token string, signer *tokens.Signer) (string, error) {
+	if token == "" {
+		return "", ErrTokenRequired
+	}
+
+	email, err := signer.Verify(tokens.ListPurpose(tokens.PurposePreferences, listID), token)
+	if err != nil {
+		return "", interfaces.ErrInvalidToken
+	}
+	return email, nil
+}
+
+// unknownTopic turns the repository's ErrTopicNotFound into a validation
+// error on the topics field.
+func unknownTopic(err error) *ValidationError {
+	return &ValidationError{Errors: []dto.FieldError{{
+		Field:   "topics",
+		Code:    validators.TopicsUnknown,
+		Message: err.Error(),
+	}}}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrivacyRequestTTL is how long the link confirming an access or erasure
+// request can be used.
+const PrivacyRequestTTL = 24 * time.Hour
+
+// HandlePrivacyRequest records an access or erasure request for email. It
+// returns the normalized address and the token of the link to email it, or
+// an empty token when nothing is stored about the address. Callers must
+// respond the same either way, so the endpoint can't tell who is subscribed.
+func HandlePrivacyRequest(kind, email string, signer *tokens.Signer, repo interfaces.PrivacyRepository, normalizer normalize.Normalizer) (string, string, error) {
+	email = normalize.Email(email)
+
+	var fieldErrors []dto.FieldError
+	if kind != dto.PrivacyAccess && kind != dto.PrivacyErasure {
+		fieldErrors = append(fieldErrors, dto.FieldError{Field: "type", Code: "type.invalid", Message: "type must be access or erasure"})
+	}
+	switch local, domain, _ := strings.Cut(email, "@"); {
+	case email == "":
+		fieldErrors = append(fieldErrors, dto.FieldError{Field: "email", Code: validators.EmailRequired, Message: "email is required"})
+	case local == "" || domain == "":
+		fieldErrors = append(fieldErrors, dto.FieldError{Field: "email", Code: validators.EmailInvalid, Message: "invalid email format"})
+	}
+	if len(fieldErrors) > 0 {
+		return email, "", &ValidationError{Errors: fieldErrors}
+	}
+
+	key := normalizer.Key(email)
+	found, err := repo.HasData(email, key)
+	if err != nil {
+		return email, "", err
+	}
+
+	request := &dto.PrivacyRequest{Kind: kind, EmailHash: signer.Digest(tokens.PurposePrivacyEmail, key), Status: dto.PrivacyPending}
+	if !found {
+		request.Status = dto.PrivacyNoData
+	}
+	if err := repo.CreateRequest(request); err != nil {
+		return email, "", err
+	}
+	if !found {
+		return email, "", nil
+	}
+
+	subject := strconv.FormatInt(request.ID, 10) + ":" + email
+	return email, signer.Sign(tokens.PurposePrivacyRequest, subject, request.RequestedAt.Add(PrivacyRequestTTL)), nil
+}
+
+// HandlePrivacyPending verifies the token of a privacy request link and
+// returns the request it was issued for without answering it, so the link
+// can show what confirming it does. Used erasure links are rejected.
+func HandlePrivacyPending(token string, signer *tokens.Signer, repo interfaces.PrivacyRepository, normalizer normalize.Normalizer) (*dto.PrivacyRequest, error) {
+	request, _, _, err := verifyPrivacyToken(token, signer, repo, normalizer)
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// HandlePrivacyConfirm verifies the token of a privacy request link and
+// answers the request. Access links can be opened again until they expire;
+// an erasure is only carried out once.
+func HandlePrivacyConfirm(token string, signer *tokens.Signer, repo interfaces.PrivacyRepository, normalizer normalize.Normalizer) (*dto.PrivacyResult, error) {
+	request, email, key, err := verifyPrivacyToken(token, signer, repo, normalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PrivacyResult{}
+	if request.Kind == dto.PrivacyAccess {
+		result.Data, err = repo.Access(request, email, key)
+	} else {
+		result.Erased, err = repo.Erase(request, email, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Request = *request
+	return result, nil
+}
+
+// verifyPrivacyToken returns the request a privacy link was issued for,
+// with the address it was made for and its normalized key.
+func verifyPrivacyToken(token string, signer *tokens.Signer, repo interfaces.PrivacyRepository, normalizer normalize.Normalizer) (*dto.PrivacyRequest, string, string, error) {
+	if token == "" {
+		return nil, "", "", ErrTokenRequired
+	}
+
+	subject, err := signer.Verify(tokens.PurposePrivacyRequest, token)
+	if err != nil {
+		return nil, "", "", interfaces.ErrInvalidToken
+	}
+	idText, email, _ := strings.Cut(subject, ":")
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if err != nil {
+		return nil, "", "", interfaces.ErrInvalidToken
+	}
+
+	request, err := repo.FindRequest(id)
+	if errors.Is(err, interfaces.ErrPrivacyRequestNotFound) {
+		return nil, "", "", interfaces.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, "", "", err
+	}
+	key := normalizer.Key(email)
+	if request.EmailHash != signer.Digest(tokens.PurposePrivacyEmail, key) {
+		return nil, "", "", interfaces.ErrInvalidToken
+	}
+	if request.Kind == dto.PrivacyErasure && request.Status != dto.PrivacyPending {
+		return nil, "", "", interfaces.ErrInvalidToken
+	}
+
+	return request, email, key, nil
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultOrigins may call the endpoints of lists without allowed origins of
+// their own.
+var defaultOrigins = []string{"http://localhost:1313", "https://zhisme.com/"}
+
+// servedList is a mailing list together with the builder of its emails.
+type servedList struct {
+	dto.List
+	emails *emails.Builder
+}
+
+// WithList serves the list of builder, see emails.NewListBuilder, at
+// /lists/{listID}/subscribers. The default list is always served, at
+// /mailing_list as well, with the builder set by WithEmailBuilder unless
+// one is given here.
+func WithList(builder *emails.Builder) ServerOption {
+	return func(s *Server) {
+		list := builder.List()
+		s.lists[list.ID] = &servedList{List: list, emails: builder}
+	}
+}
+
+// resolveList looks up the list named by the listID URL parameter and stores
+// it in the request context for listFor.
+func (s *Server) resolveList(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, ok := s.lists[chi.URLParam(r, "listID")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "list not found")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), listContextKey, list)))
+	})
+}
+
+// listFor returns the list r is about, the default list for requests to
+// /mailing_list.
+func (s *Server) listFor(r *http.Request) *servedList {
+	if list, ok := r.Context().Value(listContextKey).(*servedList); ok {
+		return list
+	}
+	return s.lists[dto.DefaultListID]
+}
+
+// allowOrigin reports whether a browser on origin may call the endpoint r
+// is for. Requests under /lists/{listID} are checked against that list's
+// origins and every other request against the default list's. Origins are
+// compared without a trailing slash, which browsers never send.
+func (s *Server) allowOrigin(r *http.Request, origin string) bool {
+	list := s.lists[dto.DefaultListID]
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/lists/"); ok {
+		id, _, _ := strings.Cut(rest, "/")
+		if list, ok = s.lists[id]; !ok {
+			return false
+		}
+	}
+
+	allowed := list.AllowedOrigins
+	if len(allowed) == 0 {
+		allowed = defaultOrigins
+	}
+	origin = strings.TrimSuffix(origin, "/")
+	return slices.ContainsFunc(allowed, func(candidate string) bool {
+		return strings.EqualFold(strings.TrimSuffix(candidate, "/"), origin)
+	})
+}
+
+// listLists handles GET /admin/lists.
+func (s *Server) listLists(w http.ResponseWriter, r *http.Request) {
+	lists := make([]dto.List, 0, len(s.lists))
+	for _, list := range s.lists {
+		lists = append(lists, list.List)
+	}
+	slices.SortFunc(lists, func(a, b dto.List) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	writeJSON(w, http.StatusOK, lists)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// metrics exposes monitoring gauges in the Prometheus text format.
+func (s *Server) metrics(w http.ResponseWriter, r *http.Request) {
+	depth, err := s.mailQueue.Depth()
+	if err != nil {
+		log.Printf("Error reading mail queue depth: %v", err)
+		http.Error(w, "failed to read metrics", http.StatusInternalServerError)
+		return
+	}
+
+	statuses := make([]string, 0, len(depth))
+	for status := range depth {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var body strings.Builder
+	body.WriteString("# HELP mail_queue_jobs Number of outbound emails in the mail queue by status.\n")
+	body.WriteString("# TYPE mail_queue_jobs gauge\n")
+	for _, status := range statuses {
+		fmt.Fprintf(&body, "mail_queue_jobs{status=%q} %d\n", status, depth[status])
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body.String())); err != nil {
+		log.Printf("Error writing metrics: %v", err)
+	}
+}
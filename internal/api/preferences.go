@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+)
+
+// listTopics handles GET /mailing_list/topics, which signup forms use to
+// offer the topics there are.
+func (s *Server) listTopics(w http.ResponseWriter, r *http.Request) {
+	topics, err := s.topics.ListTopics()
+	if err != nil {
+		log.Printf("Error listing topics: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list topics")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, topics)
+}
+
+// preferences handles GET /mailing_list/preferences?token=, the data behind
+// the preference page linked from every email.
+func (s *Server) preferences(w http.ResponseWriter, r *http.Request) {
+	preferences, err := handlers.HandlePreferences(s.listFor(r).ID, r.URL.Query().Get("token"), s.signer, s.topics)
+	s.writePreferences(w, preferences, err)
+}
+
+// updatePreferences handles PUT /mailing_list/preferences?token= with a JSON
+// body of the fields to change.
+func (s *Server) updatePreferences(w http.ResponseWriter, r *http.Request) {
+	var update dto.PreferencesUpdate
+
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		var msg string
+		if errors.Is(err, io.EOF) {
+			msg = "request body is empty"
+		} else {
+			msg = "invalid JSON: " + err.Error()
+		}
+
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	preferences, err := handlers.HandleUpdatePreferences(s.listFor(r).ID, r.URL.Query().Get("token"), update, s.signer, s.topics)
+	s.writePreferences(w, preferences, err)
+}
+
+func (s *Server) writePreferences(w http.ResponseWriter, preferences *dto.Preferences, err error) {
+	var validationErr *handlers.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeValidationProblem(w, validationErr.Errors)
+		return
+	case errors.Is(err, handlers.ErrTokenRequired), errors.Is(err, interfaces.ErrInvalidToken):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, interfaces.ErrSubscriberNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error handling preferences: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to handle preferences")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preferences)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+)
+
+type privacyRequest struct {
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+// createPrivacyRequest handles POST /privacy/requests. The response is the
+// same whether or not anything is stored about the address; only its owner
+// learns that, from the email with the confirmation link.
+func (s *Server) createPrivacyRequest(w http.ResponseWriter, r *http.Request) {
+	var request privacyRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		var msg string
+		if errors.Is(err, io.EOF) {
+			msg = "request body is empty"
+		} else {
+			msg = "invalid JSON: " + err.Error()
+		}
+
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if !s.allowEmail(w, request.Email) {
+		return
+	}
+
+	email, token, err := handlers.HandlePrivacyRequest(request.Type, request.Email, s.signer, s.privacy, s.normalizer)
+	var validationErr *handlers.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationProblem(w, validationErr.Errors)
+		return
+	}
+	if err != nil {
+		log.Printf("Error recording privacy request: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to record request")
+		return
+	}
+
+	if token != "" {
+		s.notify(s.emails.PrivacyRequest(email, request.Type, token, time.Now().Add(handlers.PrivacyRequestTTL)))
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"message": "If we store data about this address, we have emailed it a link to confirm the request",
+	})
+}
+
+// erasurePage asks the requester to confirm an erasure. The form posts back
+// to the link's own URL, token included; mail scanners and link prefetchers
+// only ever GET it.
+const erasurePage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Delete your data</title>
+</head>
+<body>
+<h1>Delete your data</h1>
+<p>This deletes your mailing list subscription and the record of the emails sent to you. It cannot be undone.</p>
+<form method="post">
+<button type="submit">Delete my data</button>
+</form>
+</body>
+</html>
+`
+
+// showPrivacyRequest handles GET on the link sent for a privacy request. It
+// returns the requester's data for an access request, which changes
+// nothing, and a page to confirm an erasure, which is carried out by
+// confirmPrivacyRequest.
+func (s *Server) showPrivacyRequest(w http.ResponseWriter, r *http.Request) {
+	request, err := handlers.HandlePrivacyPending(r.URL.Query().Get("token"), s.signer, s.privacy, s.normalizer)
+	if !writePrivacyError(w, err) {
+		return
+	}
+
+	if request.Kind == dto.PrivacyErasure {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if _, err := io.WriteString(w, erasurePage); err != nil {
+			log.Printf("Error writing erasure page: %v", err)
+		}
+		return
+	}
+	s.confirmPrivacyRequest(w, r)
+}
+
+// confirmPrivacyRequest handles POST on the link sent for a privacy
+// request. It returns the requester's data for an access request, and what
+// was erased for an erasure.
+func (s *Server) confirmPrivacyRequest(w http.ResponseWriter, r *http.Request) {
+	result, err := handlers.HandlePrivacyConfirm(r.URL.Query().Get("token"), s.signer, s.privacy, s.normalizer)
+	if !writePrivacyError(w, err) {
+		return
+	}
+
+	if result.Request.Kind == dto.PrivacyAccess {
+		w.Header().Set("Content-Disposition", `attachment; filename="my-data.json"`)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writePrivacyError responds to the error of answering a privacy request
+// link, and reports whether there was none.
+func writePrivacyError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, handlers.ErrTokenRequired):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, interfaces.ErrInvalidToken):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		log.Printf("Error answering privacy request: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to answer request")
+	default:
+		return true
+	}
+	return false
+}
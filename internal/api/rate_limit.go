@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// limitByIP throttles requests per client IP. It is a no-op without an IP
+// limiter.
+func (s *Server) limitByIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.ipLimiter != nil {
+			if ok, wait := s.ipLimiter.Allow(s.clientIP(r)); !ok {
+				tooManyRequests(w, wait)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowEmail throttles requests per target email address and writes the 429
+// response when the limit is exceeded.
+func (s *Server) allowEmail(w http.ResponseWriter, email string) bool {
+	key := strings.ToLower(strings.TrimSpace(email))
+	if s.emailLimiter == nil || key == "" {
+		return true
+	}
+
+	ok, wait := s.emailLimiter.Allow(key)
+	if !ok {
+		tooManyRequests(w, wait)
+	}
+	return ok
+}
+
+func tooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeError(w, http.StatusTooManyRequests, fmt.Sprintf("too many requests, try again in %d seconds", seconds))
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"backend-go/internal/dto"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if encodeErr := json.NewEncoder(w).Encode(payload); encodeErr != nil {
+		log.Default().Print(encodeErr)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]map[string]string{
+		"error": {
+			"message": message,
+		},
+	})
+}
+
+// writeValidationProblem responds with an RFC 7807 problem document listing
+// the invalid fields, so clients can point at the individual inputs. With the
+// about:blank type the title must be the status phrase.
+func writeValidationProblem(w http.ResponseWriter, fieldErrors []dto.FieldError) {
+	detail := "1 field is invalid"
+	if len(fieldErrors) != 1 {
+		detail = fmt.Sprintf("%d fields are invalid", len(fieldErrors))
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusBadRequest)
+	if encodeErr := json.NewEncoder(w).Encode(dto.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: detail,
+		Errors: fieldErrors,
+	}); encodeErr != nil {
+		log.Default().Print(encodeErr)
+	}
+}
@@ -1,10 +1,20 @@
 package api
 
 import (
+	"backend-go/internal/antispam"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
 	"backend-go/internal/interfaces"
+	"backend-go/internal/mailers"
+	"backend-go/internal/normalize"
+	"backend-go/internal/ratelimit"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,6 +25,157 @@ import (
 type Server struct {
 	router                *chi.Mux
 	mailingListRepository interfaces.MailingListRepository
+	validator             interfaces.MailingListValidator
+	normalizer            normalize.Normalizer
+	mailer                interfaces.Mailer
+	emails                *emails.Builder
+	lists                 map[string]*servedList
+	signer                *tokens.Signer
+	mailQueue             interfaces.MailQueueRepository
+	privacy               interfaces.PrivacyRepository
+	consent               interfaces.ConsentRepository
+	policyVersion         string
+	topics                interfaces.TopicRepository
+	apiKeys               *apikeys.Manager
+	ipLimiter             *ratelimit.Limiter
+	emailLimiter          *ratelimit.Limiter
+	trustedProxies        []netip.Prefix
+	forms                 *antispam.Forms
+	signupChecks          []antispam.Check
+	captcha               interfaces.CaptchaVerifier
+	captchaRequired       bool
+}
+
+// ServerOption customizes a Server created by NewApiServer.
+type ServerOption func(*Server)
+
+// WithValidator sets the validator signups must pass, for example one that
+// checks email domains in DNS.
+func WithValidator(validator interfaces.MailingListValidator) ServerOption {
+	return func(s *Server) {
+		s.validator = validator
+	}
+}
+
+// WithNormalizer sets how signup emails are normalized to detect the same
+// subscriber signing up twice.
+func WithNormalizer(normalizer normalize.Normalizer) ServerOption {
+	return func(s *Server) {
+		s.normalizer = normalizer
+	}
+}
+
+// WithMailer sets the transport used for outgoing emails.
+func WithMailer(mailer interfaces.Mailer) ServerOption {
+	return func(s *Server) {
+		s.mailer = mailer
+	}
+}
+
+// WithSigner sets the signer used to verify unsubscribe tokens. It must be
+// the same signer the email builder uses to create them.
+func WithSigner(signer *tokens.Signer) ServerOption {
+	return func(s *Server) {
+		s.signer = signer
+	}
+}
+
+// WithMailQueue exposes the depth of the outbound mail queue on /metrics.
+func WithMailQueue(queue interfaces.MailQueueRepository) ServerOption {
+	return func(s *Server) {
+		s.mailQueue = queue
+	}
+}
+
+// WithPrivacy enables the /privacy endpoints, where subscribers request a
+// copy of their data or its erasure.
+func WithPrivacy(repo interfaces.PrivacyRepository) ServerOption {
+	return func(s *Server) {
+		s.privacy = repo
+	}
+}
+
+// WithConsentLedger records signups, confirmations and unsubscribes in
+// ledger, together with the version of the privacy policy they were made
+// under. It also enables GET /admin/mailing_list/consent.
+func WithConsentLedger(ledger interfaces.ConsentRepository, policyVersion string) ServerOption {
+	return func(s *Server) {
+		s.consent = ledger
+		s.policyVersion = policyVersion
+	}
+}
+
+// WithTopics enables GET /mailing_list/topics and the preference page
+// endpoints, GET and PUT /mailing_list/preferences, on every list. It must
+// share the signer the email builder uses to create preference links.
+func WithTopics(repo interfaces.TopicRepository) ServerOption {
+	return func(s *Server) {
+		s.topics = repo
+	}
+}
+
+// WithAPIKeys sets the API keys accepted by the /admin endpoints. Without it
+// every admin request is rejected.
+func WithAPIKeys(manager *apikeys.Manager) ServerOption {
+	return func(s *Server) {
+		s.apiKeys = manager
+	}
+}
+
+// WithIPRateLimit throttles subscription requests per client IP.
+func WithIPRateLimit(limiter *ratelimit.Limiter) ServerOption {
+	return func(s *Server) {
+		s.ipLimiter = limiter
+	}
+}
+
+// WithEmailRateLimit throttles subscription requests per target email, so a
+// single address can't be flooded with confirmation emails from many IPs.
+func WithEmailRateLimit(limiter *ratelimit.Limiter) ServerOption {
+	return func(s *Server) {
+		s.emailLimiter = limiter
+	}
+}
+
+// WithTrustedProxies sets the reverse proxies whose X-Forwarded-For header is
+// used to find the client IP.
+func WithTrustedProxies(proxies []netip.Prefix) ServerOption {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// WithForms sets the issuer of signup form tokens. Without it tokens are
+// signed with the server's signer and never require proof of work.
+func WithForms(forms *antispam.Forms) ServerOption {
+	return func(s *Server) {
+		s.forms = forms
+	}
+}
+
+// WithSignupChecks replaces the bot protection checks run on every signup
+// before it is stored. The default only checks the honeypot field.
+func WithSignupChecks(checks ...antispam.Check) ServerOption {
+	return func(s *Server) {
+		s.signupChecks = checks
+	}
+}
+
+// WithCaptcha verifies the captchaToken of signups that send one. When
+// required is set, signups without a token are rejected too.
+func WithCaptcha(verifier interfaces.CaptchaVerifier, required bool) ServerOption {
+	return func(s *Server) {
+		s.captcha = verifier
+		s.captchaRequired = required
+	}
+}
+
+// WithEmailBuilder sets the builder used to compose outgoing emails, and the
+// emails of the default list unless WithList sets another.
+func WithEmailBuilder(builder *emails.Builder) ServerOption {
+	return func(s *Server) {
+		s.emails = builder
+	}
 }
 
 // ServeHTTP implements http.Handler interface
@@ -22,6 +183,17 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
+// notify sends an email composed by the email builder. Failures are logged
+// rather than returned so they never fail the request that triggered them.
+func (s *Server) notify(email *dto.Email, err error) {
+	if err == nil {
+		err = s.mailer.Send(email)
+	}
+	if err != nil {
+		log.Printf("Error sending email: %v", err)
+	}
+}
+
 // healthCheck handles the /health endpoint for readiness and liveness probes
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -50,23 +222,83 @@ func (s *Server) ListenAndServe(addr string) error {
 	return nil
 }
 
-func NewApiServer(mailingListRepo interfaces.MailingListRepository) *Server {
+func NewApiServer(mailingListRepo interfaces.MailingListRepository, opts ...ServerOption) *Server {
+	signer := tokens.NewSigner(tokens.RandomSecret())
 	srv := &Server{
 		router:                chi.NewRouter(),
 		mailingListRepository: mailingListRepo,
+		validator:             validators.NewMailingListValidator(),
+		mailer:                mailers.NewLogMailer(),
+		emails:                emails.NewBuilder("noreply@localhost", "http://localhost:8080", signer),
+		signer:                signer,
+		lists:                 make(map[string]*servedList),
+		signupChecks:          []antispam.Check{antispam.Honeypot()},
+	}
+
+	for _, opt := range opts {
+		opt(srv)
+	}
+	if srv.forms == nil {
+		srv.forms = antispam.NewForms(srv.signer, 0, antispam.DefaultMaxAge, 0)
+	}
+	if _, ok := srv.lists[dto.DefaultListID]; !ok {
+		srv.lists[dto.DefaultListID] = &servedList{List: srv.emails.List(), emails: srv.emails}
 	}
 
 	srv.router.Use(middleware.Logger)
 	srv.router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:1313", "https://zhisme.com/"},
-		AllowedMethods:   []string{"GET", "POST", "DELETE"},
+		AllowOriginFunc:  srv.allowOrigin,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 	srv.router.Get("/health", srv.healthCheck)
-	srv.router.Post("/mailing_list", srv.createMailingList)
+	srv.router.Route("/mailing_list", srv.subscriberRoutes)
+	srv.router.Route("/lists/{listID}/subscribers", func(r chi.Router) {
+		r.Use(srv.resolveList)
+		srv.subscriberRoutes(r)
+	})
+	if srv.privacy != nil {
+		srv.router.With(srv.limitByIP).Post("/privacy/requests", srv.createPrivacyRequest)
+		srv.router.Get("/privacy/requests/confirm", srv.showPrivacyRequest)
+		srv.router.Post("/privacy/requests/confirm", srv.confirmPrivacyRequest)
+	}
+	srv.router.Route("/admin", func(r chi.Router) {
+		r.Use(srv.authenticate)
+		r.With(srv.requireScope(dto.ScopeSubscribersRead)).Get("/mailing_list", srv.listMailingList)
+		r.With(srv.requireScope(dto.ScopeSubscribersRead)).Get("/mailing_list/export", srv.exportMailingList)
+		r.With(srv.requireScope(dto.ScopeSubscribersRead)).Get("/lists", srv.listLists)
+		r.Route("/lists/{listID}/subscribers", func(r chi.Router) {
+			r.Use(srv.resolveList, srv.requireScope(dto.ScopeSubscribersRead))
+			r.Get("/", srv.listMailingList)
+			r.Get("/export", srv.exportMailingList)
+		})
+		if srv.consent != nil {
+			r.With(srv.requireScope(dto.ScopeSubscribersRead)).Get("/mailing_list/consent", srv.listConsentEvents)
+		}
+		if srv.mailQueue != nil {
+			r.With(srv.requireScope(dto.ScopeMetricsRead)).Get("/metrics", srv.metrics)
+		}
+	})
 
 	log.Default().Println("api server initialized")
 
 	return srv
 }
+
+// subscriberRoutes registers the endpoints of one list, served for the
+// default list at /mailing_list and for every list at
+// /lists/{listID}/subscribers.
+func (s *Server) subscriberRoutes(r chi.Router) {
+	r.With(s.limitByIP).Post("/", s.createMailingList)
+	r.Delete("/", s.deleteMailingList)
+	r.Get("/form-token", s.formToken)
+	r.Get("/confirm", s.confirmMailingList)
+	r.Get("/unsubscribe", s.unsubscribeMailingList)
+	r.Post("/unsubscribe", s.oneClickUnsubscribe)
+	if s.topics != nil {
+		r.Get("/topics", s.listTopics)
+		r.Get("/preferences", s.preferences)
+		r.Put("/preferences", s.updatePreferences)
+	}
+}
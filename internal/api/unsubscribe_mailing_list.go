@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+)
+
+// maxFormMemory bounds the size of one-click unsubscribe form bodies.
+const maxFormMemory = 1 << 16
+
+type unsubscribeRequest struct {
+	Token string `json:"token"`
+}
+
+// deleteMailingList handles DELETE /mailing_list with a JSON body carrying
+// the signed unsubscribe token.
+func (s *Server) deleteMailingList(w http.ResponseWriter, r *http.Request) {
+	var request unsubscribeRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		var msg string
+		if errors.Is(err, io.EOF) {
+			msg = "request body is empty"
+		} else {
+			msg = "invalid JSON: " + err.Error()
+		}
+
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.unsubscribe(w, r, request.Token)
+}
+
+// unsubscribeMailingList handles the one-click link included in emails.
+func (s *Server) unsubscribeMailingList(w http.ResponseWriter, r *http.Request) {
+	s.unsubscribe(w, r, r.URL.Query().Get("token"))
+}
+
+// oneClickUnsubscribe handles RFC 8058 one-click unsubscribe requests. Mail
+// providers POST "List-Unsubscribe=One-Click" as a form body to the URL from
+// the List-Unsubscribe header, which carries the signed token.
+func (s *Server) oneClickUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormMemory)
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		writeError(w, http.StatusBadRequest, "invalid form body")
+		return
+	}
+
+	if r.PostForm.Get("List-Unsubscribe") != "One-Click" {
+		writeError(w, http.StatusBadRequest, "List-Unsubscribe=One-Click is required")
+		return
+	}
+
+	s.unsubscribe(w, r, r.URL.Query().Get("token"))
+}
+
+func (s *Server) unsubscribe(w http.ResponseWriter, r *http.Request, token string) {
+	consent := s.consentFor(r, dto.ConsentSourceEmail, "")
+	list := s.listFor(r)
+	mailingList, err := handlers.HandleUnsubscribe(list.ID, token, s.signer, s.mailingListRepository, consent)
+	switch {
+	case errors.Is(err, handlers.ErrTokenRequired), errors.Is(err, interfaces.ErrInvalidToken):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, interfaces.ErrSubscriberNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error unsubscribing: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to unsubscribe")
+		return
+	}
+
+	s.notify(list.emails.UnsubscribeReceipt(mailingList))
+
+	writeJSON(w, http.StatusOK, mailingList)
+}
@@ -0,0 +1,130 @@
+package apikeys
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// tokenPrefix starts every key so leaked keys are easy to spot in code and
+// logs.
+const tokenPrefix = "blog_"
+
+// lastUsedResolution limits how often authenticating a key writes its
+// last-used timestamp.
+const lastUsedResolution = time.Minute
+
+var (
+	// ErrInvalidKey is returned for unknown, malformed or revoked keys.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrUnknownScope is returned when creating a key with an unknown scope.
+	ErrUnknownScope = errors.New("unknown scope")
+)
+
+// Manager creates, authenticates and revokes admin API keys. Keys look like
+// blog_<prefix>_<secret>; only a SHA-256 hash of the whole key is stored,
+// which is enough for random 256-bit secrets.
+type Manager struct {
+	repo interfaces.APIKeyRepository
+	now  func() time.Time
+}
+
+func NewManager(repo interfaces.APIKeyRepository) *Manager {
+	return &Manager{repo: repo, now: time.Now}
+}
+
+// Create stores a new key and returns it together with the secret token,
+// which cannot be recovered later.
+func (m *Manager) Create(name string, scopes []string) (string, *dto.APIKey, error) {
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one scope is required", ErrUnknownScope)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(dto.Scopes, scope) {
+			return "", nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownScope, scope, strings.Join(dto.Scopes, ", "))
+		}
+	}
+
+	prefix, err := randomString(4, hex.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	token := tokenPrefix + prefix + "_" + secret
+
+	key := &dto.APIKey{
+		CreatedAt: m.now().UTC(),
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}
+	if err := m.repo.Create(key, Hash(token)); err != nil {
+		return "", nil, err
+	}
+
+	return token, key, nil
+}
+
+// Authenticate returns the active key for token and records its use.
+func (m *Manager) Authenticate(token string) (*dto.APIKey, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := m.repo.FindByHash(Hash(token))
+	if errors.Is(err, interfaces.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidKey
+	}
+
+	now := m.now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := m.repo.TouchLastUsed(key.ID, now); err != nil {
+			log.Printf("Error recording use of api key %s: %v", key.Prefix, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+func (m *Manager) List() ([]dto.APIKey, error) {
+	return m.repo.List()
+}
+
+// Revoke disables the key with prefix immediately.
+func (m *Manager) Revoke(prefix string) error {
+	return m.repo.Revoke(prefix)
+}
+
+// Hash returns the stored form of token.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return encode(buf), nil
+}
@@ -0,0 +1,163 @@
+package campaigns
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/newsletter"
+	"fmt"
+	"log"
+	"slices"
+)
+
+type Options struct {
+	// TestTo sends a single preview to this address instead of the list.
+	// Previews are not recorded as deliveries.
+	TestTo string
+	// Topic limits the campaign to subscribers who chose it or no topic at
+	// all.
+	Topic string
+	// Frequency limits the campaign to subscribers who chose it, for
+	// example to send the weekly digest.
+	Frequency string
+	// DryRun reports who would receive the campaign without sending.
+	DryRun bool
+	// RetryUncertain sends again to recipients whose previous send was
+	// interrupted, accepting that some may get a second copy.
+	RetryUncertain bool
+	// MarkSent lists recipients whose interrupted send is known to have
+	// arrived, for example from the mail server's logs. They are recorded as
+	// sent instead of being reported as uncertain.
+	MarkSent []string
+}
+
+type Report struct {
+	// Planned lists the recipients of a dry run.
+	Planned []string `json:"planned,omitempty"`
+	// Uncertain lists recipients whose previous send was interrupted; they
+	// are skipped rather than risk a second copy until they are resolved with
+	// Options.RetryUncertain or Options.MarkSent.
+	Uncertain  []string `json:"uncertain,omitempty"`
+	Recipients int      `json:"recipients"`
+	Sent       int      `json:"sent"`
+	Skipped    int      `json:"skipped"`
+	Failed     int      `json:"failed"`
+}
+
+// Sender delivers a newsletter to every active subscriber of the email
+// builder's list, recording each delivery so a crashed run can be resumed
+// without sending twice.
+type Sender struct {
+	subscribers interfaces.MailingListRepository
+	deliveries  interfaces.DeliveryRepository
+	mailer      interfaces.Mailer
+	emails      *emails.Builder
+}
+
+func NewSender(subscribers interfaces.MailingListRepository, deliveries interfaces.DeliveryRepository, mailer interfaces.Mailer, builder *emails.Builder) *Sender {
+	return &Sender{
+		subscribers: subscribers,
+		deliveries:  deliveries,
+		mailer:      mailer,
+		emails:      builder,
+	}
+}
+
+// Send delivers issue as campaign. Mailer failures are counted in the report
+// and retried on the next run; storage failures abort the run.
+func (s *Sender) Send(campaign, subject string, issue *newsletter.Newsletter, opts Options) (*Report, error) {
+	report := &Report{}
+
+	if opts.TestTo != "" {
+		report.Recipients = 1
+		if opts.DryRun {
+			report.Planned = append(report.Planned, opts.TestTo)
+			return report, nil
+		}
+		preview := dto.MailingList{Email: opts.TestTo, Status: dto.StatusActive}
+		email, err := s.emails.Newsletter(preview, "[TEST] "+subject, issue.Text, issue.HTML)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.mailer.Send(email); err != nil {
+			return nil, fmt.Errorf("failed to send test email: %w", err)
+		}
+		report.Sent = 1
+		return report, nil
+	}
+
+	listID := s.emails.List().ID
+	active, err := s.subscribers.ListActive(listID)
+	if err != nil {
+		return nil, err
+	}
+	subscribers := active[:0]
+	for _, subscriber := range active {
+		if opts.Topic != "" && !subscriber.WantsTopics([]string{opts.Topic}) {
+			continue
+		}
+		if opts.Frequency != "" && !subscriber.WantsFrequency(opts.Frequency) {
+			continue
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	statuses, err := s.deliveries.Statuses(listID, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Recipients = len(subscribers)
+	for _, subscriber := range subscribers {
+		switch statuses[subscriber.Email] {
+		case dto.DeliverySent:
+			report.Skipped++
+			continue
+		case dto.DeliverySending:
+			if slices.Contains(opts.MarkSent, subscriber.Email) {
+				report.Skipped++
+				if opts.DryRun {
+					continue
+				}
+				if err := s.deliveries.MarkSent(listID, campaign, subscriber.Email); err != nil {
+					return report, err
+				}
+				continue
+			}
+			if !opts.RetryUncertain {
+				report.Uncertain = append(report.Uncertain, subscriber.Email)
+				continue
+			}
+		}
+
+		if opts.DryRun {
+			report.Planned = append(report.Planned, subscriber.Email)
+			continue
+		}
+
+		// A template that fails to render fails for every recipient
+		email, err := s.emails.Newsletter(subscriber, subject, issue.Text, issue.HTML)
+		if err != nil {
+			return report, err
+		}
+
+		if err := s.deliveries.MarkSending(listID, campaign, subscriber.Email); err != nil {
+			return report, err
+		}
+
+		if sendErr := s.mailer.Send(email); sendErr != nil {
+			log.Printf("Error sending %s to %s: %v", campaign, subscriber.Email, sendErr)
+			report.Failed++
+			if err := s.deliveries.MarkFailed(listID, campaign, subscriber.Email, sendErr); err != nil {
+				return report, err
+			}
+			continue
+		}
+
+		report.Sent++
+		if err := s.deliveries.MarkSent(listID, campaign, subscriber.Email); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
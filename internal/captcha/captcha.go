@@ -0,0 +1,46 @@
+package captcha
+
+import (
+	"backend-go/internal/config"
+	"backend-go/internal/interfaces"
+	"errors"
+	"fmt"
+)
+
+// Providers selectable with CAPTCHA_PROVIDER.
+const (
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+	ProviderStub      = "stub"
+)
+
+// Siteverify endpoints of the supported providers.
+const (
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// ErrFailed is returned when a token is not a valid CAPTCHA solution.
+var ErrFailed = errors.New("captcha verification failed")
+
+// NewVerifier returns the verifier selected by cfg.CaptchaProvider, or nil
+// when CAPTCHA verification is disabled.
+func NewVerifier(cfg *config.Config) (interfaces.CaptchaVerifier, error) {
+	switch cfg.CaptchaProvider {
+	case "":
+		return nil, nil
+	case ProviderStub:
+		return NewStubVerifier(), nil
+	case ProviderHCaptcha, ProviderTurnstile:
+		if cfg.CaptchaSecret == "" {
+			return nil, fmt.Errorf("CAPTCHA_SECRET is required for the %s captcha provider", cfg.CaptchaProvider)
+		}
+		url := cfg.CaptchaVerifyURL
+		if url == "" {
+			url = map[string]string{ProviderHCaptcha: HCaptchaVerifyURL, ProviderTurnstile: TurnstileVerifyURL}[cfg.CaptchaProvider]
+		}
+		return NewSiteVerifier(url, cfg.CaptchaSecret, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown CAPTCHA_PROVIDER %q", cfg.CaptchaProvider)
+	}
+}
@@ -0,0 +1,74 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SiteVerifier verifies tokens against a siteverify API as implemented by
+// hCaptcha and Cloudflare Turnstile: a form POST with the secret, the token
+// and the client IP, answered with {"success": bool, "error-codes": [...]}.
+type SiteVerifier struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+// NewSiteVerifier returns a verifier posting to url. A nil client uses one
+// with a 10 second timeout.
+func NewSiteVerifier(url, secret string, client *http.Client) *SiteVerifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &SiteVerifier{client: client, url: url, secret: secret}
+}
+
+type siteverifyResponse struct {
+	ErrorCodes []string `json:"error-codes"`
+	Success    bool     `json:"success"`
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return ErrFailed
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create siteverify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call siteverify: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("siteverify returned %s", resp.Status)
+	}
+
+	var result siteverifyResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode siteverify response: %w", err)
+	}
+	if !result.Success {
+		if len(result.ErrorCodes) > 0 {
+			return fmt.Errorf("%w: %s", ErrFailed, strings.Join(result.ErrorCodes, ", "))
+		}
+		return ErrFailed
+	}
+
+	return nil
+}
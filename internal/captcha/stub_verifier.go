@@ -0,0 +1,22 @@
+package captcha
+
+import "context"
+
+// StubFailToken is rejected by StubVerifier; every other non-empty token is
+// accepted.
+const StubFailToken = "fail"
+
+// StubVerifier accepts CAPTCHA tokens without calling a provider, for local
+// development of the signup form.
+type StubVerifier struct{}
+
+func NewStubVerifier() *StubVerifier {
+	return &StubVerifier{}
+}
+
+func (v *StubVerifier) Verify(_ context.Context, token, _ string) error {
+	if token == "" || token == StubFailToken {
+		return ErrFailed
+	}
+	return nil
+}
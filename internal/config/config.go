@@ -1,27 +1,209 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	DatabasePath string
 	ServerAddr   string
+	BaseURL      string
+	MailFrom     string
+	// SigningSecret keys the HMAC used for unsubscribe links.
+	SigningSecret string
+	// TemplateDir holds email templates that override the embedded defaults.
+	TemplateDir string
+
+	// MailTransport selects how emails are delivered: "log", "file" or "smtp".
+	MailTransport string
+	// MailDir is where the file transport writes .eml files.
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	// SMTPSecurity is "starttls", "tls" (implicit TLS) or "none".
+	SMTPSecurity string
+	// SMTPAuth is "plain", "login" or "none".
+	SMTPAuth string
+
+	// MailQueueWorkers is the number of goroutines delivering queued emails.
+	MailQueueWorkers int
+	// MailQueueMaxAttempts is how often an email is tried before it is
+	// moved to the dead-letter state.
+	MailQueueMaxAttempts int
+	// MailQueueRetention is how long sent emails stay in the queue before
+	// they are deleted.
+	MailQueueRetention time.Duration
+
+	// FeedURL is the blog's RSS or Atom feed, either a URL or a local file
+	// path. New posts are announced to subscribers; empty disables the
+	// watcher.
+	FeedURL          string
+	FeedPollInterval time.Duration
+
+	// RateLimitPerIP and RateLimitPerEmail cap subscription requests per
+	// RateLimitWindow; 0 disables the limit.
+	RateLimitPerIP    int
+	RateLimitPerEmail int
+	RateLimitWindow   time.Duration
+	// TrustedProxies lists the IPs and CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header is trusted.
+	TrustedProxies []string
+
+	// FormMinAge is how long after fetching a form token the signup form
+	// may be submitted; 0 disables the form token check.
+	FormMinAge time.Duration
+	// FormMaxAge is how long a form token stays valid.
+	FormMaxAge time.Duration
+	// PowDifficulty is the number of leading zero bits the signup form's
+	// proof of work must produce; 0 disables it.
+	PowDifficulty int
+
+	// DisposableDomainsFile replaces the built-in list of disposable email
+	// domains that signups are rejected for.
+	DisposableDomainsFile string
+	// EmailDNSCheck rejects signups whose email domain has no MX or address
+	// records.
+	EmailDNSCheck bool
+	// EmailFoldAliases treats provider-specific variants of an address, such
+	// as Gmail's dots and +tags, as the same subscriber.
+	EmailFoldAliases bool
+
+	// CaptchaProvider is "hcaptcha", "turnstile", "stub" or empty to disable
+	// CAPTCHA verification.
+	CaptchaProvider string
+	CaptchaSecret   string
+	// CaptchaVerifyURL overrides the provider's siteverify endpoint.
+	CaptchaVerifyURL string
+	// CaptchaRequired rejects signups without a captchaToken instead of only
+	// verifying the ones that send it.
+	CaptchaRequired bool
+
+	// PrivacyPolicyVersion identifies the privacy policy subscribers agree
+	// to, such as its date. It is recorded with every consent event.
+	PrivacyPolicyVersion string
+
+	// PreferencesPageURL is the page, hosted with the blog, where subscribers
+	// choose topics and frequency. Emails link to it when it is set.
+	PreferencesPageURL string
 }
 
 func LoadConfig() *Config {
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "blog.db" // Default database path
+	return &Config{
+		DatabasePath:  getEnv("DB_PATH", "blog.db"),
+		ServerAddr:    getEnv("SERVER_ADDR", ":8080"),
+		BaseURL:       getEnv("BASE_URL", "http://localhost:8080"),
+		MailFrom:      getEnv("MAIL_FROM", "zhisme.com <noreply@zhisme.com>"),
+		SigningSecret: os.Getenv("SIGNING_SECRET"),
+		TemplateDir:   os.Getenv("TEMPLATE_DIR"),
+
+		MailTransport: getEnv("MAIL_TRANSPORT", "log"),
+		MailDir:       getEnv("MAIL_DIR", "mail"),
+		SMTPHost:      os.Getenv("SMTP_HOST"),
+		SMTPPort:      getEnv("SMTP_PORT", "587"),
+		SMTPUsername:  os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:  os.Getenv("SMTP_PASSWORD"),
+		SMTPSecurity:  getEnv("SMTP_SECURITY", "starttls"),
+		SMTPAuth:      getEnv("SMTP_AUTH", "plain"),
+
+		MailQueueWorkers:     getEnvInt("MAIL_QUEUE_WORKERS", 2),
+		MailQueueMaxAttempts: getEnvInt("MAIL_QUEUE_MAX_ATTEMPTS", 10),
+		MailQueueRetention:   getEnvDuration("MAIL_QUEUE_RETENTION", 7*24*time.Hour),
+
+		FeedURL:          os.Getenv("FEED_URL"),
+		FeedPollInterval: getEnvDuration("FEED_POLL_INTERVAL", 15*time.Minute),
+
+		RateLimitPerIP:    getEnvInt("RATE_LIMIT_PER_IP", 10),
+		RateLimitPerEmail: getEnvInt("RATE_LIMIT_PER_EMAIL", 3),
+		RateLimitWindow:   getEnvDuration("RATE_LIMIT_WINDOW", time.Hour),
+		TrustedProxies:    getEnvList("TRUSTED_PROXIES"),
+
+		FormMinAge:    getEnvDuration("FORM_MIN_AGE", 0),
+		FormMaxAge:    getEnvDuration("FORM_MAX_AGE", 24*time.Hour),
+		PowDifficulty: getEnvInt("POW_DIFFICULTY", 0),
+
+		DisposableDomainsFile: os.Getenv("DISPOSABLE_DOMAINS_FILE"),
+		EmailDNSCheck:         getEnvBool("EMAIL_DNS_CHECK", true),
+		EmailFoldAliases:      getEnvBool("EMAIL_FOLD_ALIASES", false),
+
+		CaptchaProvider:  os.Getenv("CAPTCHA_PROVIDER"),
+		CaptchaSecret:    os.Getenv("CAPTCHA_SECRET"),
+		CaptchaVerifyURL: os.Getenv("CAPTCHA_VERIFY_URL"),
+		CaptchaRequired:  getEnvBool("CAPTCHA_REQUIRED", false),
+
+		PrivacyPolicyVersion: os.Getenv("PRIVACY_POLICY_VERSION"),
+		PreferencesPageURL:   os.Getenv("PREFERENCES_PAGE_URL"),
 	}
+}
 
-	serverAddr := os.Getenv("SERVER_ADDR")
-	if serverAddr == "" {
-		serverAddr = ":8080" // Default server address
+// getEnv returns the value of the environment variable or fallback when it is unset.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return fallback
+}
 
-	return &Config{
-		DatabasePath: dbPath,
-		ServerAddr:   serverAddr,
+// getEnvInt returns the integer value of the environment variable or fallback
+// when it is unset or not a number.
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %d", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvBool returns the boolean value of the environment variable, such as
+// "true" or "0", or fallback when it is unset or invalid.
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %t", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvDuration returns the duration value of the environment variable, such
+// as "15m", or fallback when it is unset or invalid.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvList returns the comma-separated values of the environment variable.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
 	}
+	return values
 }
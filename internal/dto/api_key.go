@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"slices"
+	"time"
+)
+
+// API key scopes.
+const (
+	ScopeSubscribersRead  = "subscribers:read"
+	ScopeSubscribersWrite = "subscribers:write"
+	ScopeCampaignsSend    = "campaigns:send"
+	ScopeMetricsRead      = "metrics:read"
+)
+
+// Scopes lists every scope an API key can be granted.
+var Scopes = []string{ScopeSubscribersRead, ScopeSubscribersWrite, ScopeCampaignsSend, ScopeMetricsRead}
+
+// APIKey is an admin API credential. The secret itself is never stored,
+// only its hash; Prefix identifies the key in listings and logs.
+type APIKey struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ID         int64      `json:"id"`
+}
+
+// HasScope reports whether the key was granted scope.
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// Consent event types recorded in the consent_events table.
+const (
+	ConsentSubscribe   = "subscribe"
+	ConsentConfirm     = "confirm"
+	ConsentUnsubscribe = "unsubscribe"
+)
+
+// Sources a consent event can come from.
+const (
+	// ConsentSourceForm is the signup form on the blog.
+	ConsentSourceForm = "form"
+	// ConsentSourceEmail is a confirmation or unsubscribe link in an email,
+	// including one-click unsubscribes sent by mail providers.
+	ConsentSourceEmail  = "email"
+	ConsentSourceImport = "import"
+	// ConsentSourceAdmin is a change made with blogctl.
+	ConsentSourceAdmin = "admin"
+)
+
+// ConsentEvent is one entry in the consent ledger. The client IP is only kept
+// as IPHash, an HMAC keyed with the signing secret.
+type ConsentEvent struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// ListID is the list consent was given for.
+	ListID string `json:"listId"`
+	Email  string `json:"email"`
+	// NormalizedEmail identifies the subscriber the event is about.
+	NormalizedEmail string `json:"-"`
+	Event           string `json:"event"`
+	Source          string `json:"source"`
+	IPHash          string `json:"ipHash,omitempty"`
+	UserAgent       string `json:"userAgent,omitempty"`
+	// PageURL is the page the signup form was submitted from.
+	PageURL string `json:"pageUrl,omitempty"`
+	// PolicyVersion is the privacy policy in effect when the event happened.
+	PolicyVersion string `json:"policyVersion,omitempty"`
+	ID            int64  `json:"id"`
+}
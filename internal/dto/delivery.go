@@ -0,0 +1,8 @@
+package dto
+
+// Campaign delivery states stored in the campaign_deliveries table.
+const (
+	DeliverySending = "sending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
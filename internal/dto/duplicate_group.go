@@ -0,0 +1,10 @@
+package dto
+
+// DuplicateGroup is a set of subscribers whose emails normalize to the same
+// address and were merged into the one with Kept email.
+type DuplicateGroup struct {
+	NormalizedEmail string   `json:"normalizedEmail"`
+	Kept            string   `json:"kept"`
+	Status          string   `json:"status"`
+	Merged          []string `json:"merged"`
+}
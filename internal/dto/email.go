@@ -0,0 +1,11 @@
+package dto
+
+// Email is an outgoing message before it is encoded for a transport.
+type Email struct {
+	Headers  map[string]string
+	From     string
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
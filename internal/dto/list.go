@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// DefaultListID is the list subscribers of /mailing_list belong to. It is
+// created by the migrations and can't be deleted.
+const DefaultListID = "default"
+
+// List is one mailing list served by the deployment. Empty fields fall back
+// to the server-wide settings.
+type List struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+	// Name is how emails refer to the list, such as "zhisme.com".
+	Name string `json:"name"`
+	// MailFrom is the sender of the list's emails.
+	MailFrom string `json:"mailFrom,omitempty"`
+	// AllowedOrigins are the sites whose signup forms may call the list's
+	// endpoints from the browser.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// TemplateDir overrides the built-in email templates for the list.
+	TemplateDir string `json:"templateDir,omitempty"`
+}
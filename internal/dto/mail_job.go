@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// Mail queue job states stored in the mail_queue table.
+const (
+	JobPending = "pending"
+	JobSending = "sending"
+	JobSent    = "sent"
+	JobDead    = "dead"
+)
+
+// MailJob is an email waiting in the outbound queue.
+type MailJob struct {
+	CreatedAt time.Time
+	Email     Email
+	ID        int64
+	Attempts  int
+}
@@ -1,9 +1,56 @@
 package dto
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+// Subscription states stored in the mailing_list table.
+const (
+	StatusPending      = "pending"
+	StatusActive       = "active"
+	StatusUnsubscribed = "unsubscribed"
+)
 
 type MailingList struct {
-	CreatedAt time.Time `json:"createdAt"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
+	CreatedAt             time.Time  `json:"createdAt"`
+	ConfirmationExpiresAt time.Time  `json:"-"`
+	ConfirmedAt           *time.Time `json:"confirmedAt,omitempty"`
+	UnsubscribedAt        *time.Time `json:"unsubscribedAt,omitempty"`
+	// ListID is the list the subscription belongs to.
+	ListID   string `json:"listId,omitempty"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// NormalizedEmail identifies the mailbox behind Email; subscribers are
+	// unique on it within a list.
+	NormalizedEmail   string `json:"-"`
+	Status            string `json:"status,omitempty"`
+	ConfirmationToken string `json:"-"`
+	// Frequency is how often the subscriber wants to hear from the blog,
+	// FrequencyImmediate unless they chose otherwise.
+	Frequency string `json:"frequency,omitempty"`
+	// Topics holds the slugs of the topics the subscriber chose; none means
+	// every topic.
+	Topics []string `json:"topics,omitempty"`
+}
+
+// WantsTopics reports whether the subscriber should get content about
+// topics. Subscribers who chose no topics get everything, and so does
+// content without topics.
+func (m MailingList) WantsTopics(topics []string) bool {
+	if len(m.Topics) == 0 || len(topics) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(topics, func(topic string) bool {
+		return slices.Contains(m.Topics, topic)
+	})
+}
+
+// WantsFrequency reports whether the subscriber chose frequency. Subscribers
+// without one get every email as it is sent.
+func (m MailingList) WantsFrequency(frequency string) bool {
+	if m.Frequency == "" {
+		return frequency == FrequencyImmediate
+	}
+	return m.Frequency == frequency
 }
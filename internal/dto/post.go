@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// Post is a blog post read from the site's RSS or Atom feed.
+type Post struct {
+	Published time.Time
+	GUID      string
+	Title     string
+	Link      string
+	// Summary is the plain-text description from the feed.
+	Summary string
+	// Categories holds the post's feed categories as topic slugs.
+	Categories []string
+}
@@ -0,0 +1,79 @@
+package dto
+
+import "time"
+
+// Kinds of data subject requests.
+const (
+	PrivacyAccess  = "access"
+	PrivacyErasure = "erasure"
+)
+
+// Privacy request states stored in the privacy_requests table.
+const (
+	// PrivacyPending requests wait for the requester to follow the link
+	// emailed to them.
+	PrivacyPending   = "pending"
+	PrivacyCompleted = "completed"
+	// PrivacyNoData requests were about an address nothing is stored about,
+	// so no link was sent.
+	PrivacyNoData = "no_data"
+)
+
+// PrivacyRequest is the audit record of an access or erasure request. The
+// address is only kept as EmailHash, an HMAC of its normalized form keyed
+// with the signing secret, so the log can't be matched against a list of
+// known addresses without the secret.
+type PrivacyRequest struct {
+	RequestedAt time.Time  `json:"requestedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Kind        string     `json:"kind"`
+	EmailHash   string     `json:"emailHash"`
+	Status      string     `json:"status"`
+	// Details summarizes what was done, such as the rows erased.
+	Details string `json:"details,omitempty"`
+	ID      int64  `json:"id"`
+}
+
+// SubjectData is everything stored about one email address.
+type SubjectData struct {
+	GeneratedAt     time.Time          `json:"generatedAt"`
+	Email           string             `json:"email"`
+	Subscriptions   []MailingList      `json:"subscriptions"`
+	Deliveries      []CampaignDelivery `json:"deliveries"`
+	Emails          []SentEmail        `json:"emails"`
+	ConsentEvents   []ConsentEvent     `json:"consentEvents"`
+	PrivacyRequests []PrivacyRequest   `json:"privacyRequests"`
+}
+
+// CampaignDelivery is one newsletter campaign sent to a subscriber.
+type CampaignDelivery struct {
+	UpdatedAt time.Time  `json:"updatedAt"`
+	SentAt    *time.Time `json:"sentAt,omitempty"`
+	ListID    string     `json:"listId"`
+	Campaign  string     `json:"campaign"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// SentEmail is a message in the outbound mail queue, sent or not.
+type SentEmail struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Subject   string    `json:"subject"`
+	Status    string    `json:"status"`
+}
+
+// ErasureReport counts the rows an erasure deleted or anonymized.
+type ErasureReport struct {
+	Subscribers   int64 `json:"subscribers"`
+	Deliveries    int64 `json:"deliveries"`
+	Emails        int64 `json:"emails"`
+	ConsentEvents int64 `json:"consentEvents"`
+}
+
+// PrivacyResult answers a confirmed request: Data for an access request,
+// Erased for an erasure.
+type PrivacyResult struct {
+	Data    *SubjectData   `json:"data,omitempty"`
+	Erased  *ErasureReport `json:"erased,omitempty"`
+	Request PrivacyRequest `json:"request"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// FieldError describes why the value of one request field was rejected.
+// Code is machine-readable, such as "email.invalid"; Suggestion optionally
+// holds a corrected value.
+type FieldError struct {
+	Field      string `json:"field"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Problem is an RFC 7807 problem details document. Errors lists the
+// individual fields that failed validation.
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+	Status int          `json:"status"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// Signup is the body of POST /mailing_list: the subscriber plus the fields
+// the signup form sends for bot protection.
+type Signup struct {
+	MailingList
+	// Website is the honeypot field; humans never see it.
+	Website   string `json:"website,omitempty"`
+	FormToken string `json:"formToken,omitempty"`
+	Nonce     string `json:"nonce,omitempty"`
+	// CaptchaToken is the response of the hCaptcha or Turnstile widget.
+	CaptchaToken string `json:"captchaToken,omitempty"`
+	// PageURL is the page the form was submitted from, recorded in the
+	// consent ledger. The Referer header is used when it is empty.
+	PageURL string `json:"pageUrl,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// SubscriberQuery filters and pages through the mailing list. Zero values
+// disable a filter.
+type SubscriberQuery struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Status        string
+	// Search matches a substring of the email or username, ignoring case.
+	Search string
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+	Limit  int
+}
+
+// SubscriberPage is one page of subscribers, newest first.
+type SubscriberPage struct {
+	Subscribers []MailingList `json:"subscribers"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
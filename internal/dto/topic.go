@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// How often a subscriber wants to hear from the blog. Immediate subscribers
+// get every post announcement; the others only get the digests sent to
+// their frequency.
+const (
+	FrequencyImmediate = "immediate"
+	FrequencyWeekly    = "weekly"
+	FrequencyMonthly   = "monthly"
+)
+
+// Topic is a category of posts subscribers can choose, such as "go".
+type Topic struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ID          int64     `json:"-"`
+}
+
+// Preferences is what a subscriber can change from the preference page.
+type Preferences struct {
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	Frequency string `json:"frequency"`
+	// Topics holds the slugs of the chosen topics; none means every topic.
+	Topics []string `json:"topics"`
+	// AvailableTopics lists every topic that can be chosen.
+	AvailableTopics []Topic `json:"availableTopics"`
+}
+
+// PreferencesUpdate is the body of PUT /mailing_list/preferences. Fields
+// left out are not changed.
+type PreferencesUpdate struct {
+	Username  *string   `json:"username,omitempty"`
+	Frequency *string   `json:"frequency,omitempty"`
+	Topics    *[]string `json:"topics,omitempty"`
+}
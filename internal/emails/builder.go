@@ -0,0 +1,249 @@
+package emails
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/tokens"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	bodyRe  = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+	styleRe = regexp.MustCompile(`(?is)<style[^>]*>.*?</style>`)
+)
+
+// DefaultListName is what emails call the default list when it has no name.
+const DefaultListName = "zhisme.com"
+
+// Builder composes the emails sent by the service from Templates.
+type Builder struct {
+	signer    *tokens.Signer
+	templates *Templates
+	from      string
+	baseURL   string
+	// list is the mailing list the emails are about. Its links point to
+	// its own endpoints and its tokens are only valid for it.
+	list dto.List
+	// preferencesPage is the page, hosted with the blog, where subscribers
+	// change their preferences through the /mailing_list/preferences API.
+	preferencesPage string
+}
+
+type BuilderOption func(*Builder)
+
+// WithTemplates replaces the embedded default templates.
+func WithTemplates(templates *Templates) BuilderOption {
+	return func(b *Builder) {
+		b.templates = templates
+	}
+}
+
+// WithPreferencesPage links every email with an unsubscribe link to
+// pageURL, with a signed token added as the token query parameter. Links
+// for a list other than the default one also carry its id as the list
+// parameter, so the page knows which /lists/{listID}/subscribers to call.
+func WithPreferencesPage(pageURL string) BuilderOption {
+	return func(b *Builder) {
+		b.preferencesPage = pageURL
+	}
+}
+
+// WithList makes the emails about list instead of the default list.
+func WithList(list dto.List) BuilderOption {
+	return func(b *Builder) {
+		b.list = list
+	}
+}
+
+func NewBuilder(from, baseURL string, signer *tokens.Signer, opts ...BuilderOption) *Builder {
+	b := &Builder{
+		signer:  signer,
+		from:    from,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		list:    dto.List{ID: dto.DefaultListID, Name: DefaultListName},
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.templates == nil {
+		b.templates = defaultTemplates()
+	}
+
+	return b
+}
+
+// NewListBuilder returns a builder for list's emails, sent from list's
+// sender with the templates in its template directory. Lists without their
+// own use from and templateDir.
+func NewListBuilder(list dto.List, from, baseURL, templateDir string, signer *tokens.Signer, opts ...BuilderOption) (*Builder, error) {
+	if list.MailFrom != "" {
+		from = list.MailFrom
+	}
+	if list.TemplateDir != "" {
+		templateDir = list.TemplateDir
+	}
+
+	templates, err := LoadTemplates(templateDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates of list %s: %w", list.ID, err)
+	}
+
+	opts = append([]BuilderOption{WithTemplates(templates), WithList(list)}, opts...)
+	return NewBuilder(from, baseURL, signer, opts...), nil
+}
+
+// List returns the mailing list the builder composes emails for.
+func (b *Builder) List() dto.List {
+	return b.list
+}
+
+// listPath is where the endpoints of the builder's list are served.
+func (b *Builder) listPath() string {
+	if b.list.ID == dto.DefaultListID {
+		return "/mailing_list"
+	}
+	return "/lists/" + url.PathEscape(b.list.ID) + "/subscribers"
+}
+
+// ConfirmURL returns the link a subscriber follows to confirm their address.
+func (b *Builder) ConfirmURL(token string) string {
+	return b.baseURL + b.listPath() + "/confirm?token=" + url.QueryEscape(token)
+}
+
+// UnsubscribeURL returns a signed one-click unsubscribe link for email.
+func (b *Builder) UnsubscribeURL(email string) string {
+	token := b.signer.Sign(tokens.ListPurpose(tokens.PurposeUnsubscribe, b.list.ID), email, time.Time{})
+	return b.baseURL + b.listPath() + "/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// PreferencesURL returns a signed link to the preference page for email, or
+// an empty string when no page is configured.
+func (b *Builder) PreferencesURL(email string) string {
+	if b.preferencesPage == "" {
+		return ""
+	}
+
+	separator := "?"
+	if strings.Contains(b.preferencesPage, "?") {
+		separator = "&"
+	}
+	token := b.signer.Sign(tokens.ListPurpose(tokens.PurposePreferences, b.list.ID), email, time.Time{})
+	link := b.preferencesPage + separator + "token=" + url.QueryEscape(token)
+	if b.list.ID != dto.DefaultListID {
+		link += "&list=" + url.QueryEscape(b.list.ID)
+	}
+	return link
+}
+
+// PrivacyURL returns the link confirming a data access or erasure request.
+func (b *Builder) PrivacyURL(token string) string {
+	return b.baseURL + "/privacy/requests/confirm?token=" + url.QueryEscape(token)
+}
+
+func (b *Builder) Confirmation(subscriber dto.MailingList) (*dto.Email, error) {
+	return b.render(TemplateConfirmation, TemplateData{
+		MailingList: subscriber,
+		ConfirmURL:  b.ConfirmURL(subscriber.ConfirmationToken),
+	})
+}
+
+// Welcome greets a subscriber who just confirmed their address.
+func (b *Builder) Welcome(subscriber dto.MailingList) (*dto.Email, error) {
+	return b.render(TemplateWelcome, TemplateData{
+		MailingList:    subscriber,
+		UnsubscribeURL: b.UnsubscribeURL(subscriber.Email),
+	})
+}
+
+// NewPost announces a blog post to one subscriber.
+func (b *Builder) NewPost(subscriber dto.MailingList, post dto.Post) (*dto.Email, error) {
+	return b.render(TemplateNewPost, TemplateData{
+		MailingList:    subscriber,
+		Post:           post,
+		UnsubscribeURL: b.UnsubscribeURL(subscriber.Email),
+	})
+}
+
+// UnsubscribeReceipt confirms to a subscriber that they have left the list.
+func (b *Builder) UnsubscribeReceipt(subscriber dto.MailingList) (*dto.Email, error) {
+	return b.render(TemplateUnsubscribed, TemplateData{
+		MailingList: subscriber,
+	})
+}
+
+// PrivacyRequest asks the owner of email to confirm an access or erasure
+// request by following a link that expires at expiresAt.
+func (b *Builder) PrivacyRequest(email, kind, token string, expiresAt time.Time) (*dto.Email, error) {
+	return b.render(TemplatePrivacyRequest, TemplateData{
+		MailingList: dto.MailingList{Email: email, ConfirmationExpiresAt: expiresAt},
+		ConfirmURL:  b.PrivacyURL(token),
+		Request:     kind,
+	})
+}
+
+// Newsletter wraps a rendered newsletter issue for one subscriber. When the
+// issue is a complete HTML document, only its body and stylesheets are kept.
+// An issue without HTML is sent as plain text only.
+func (b *Builder) Newsletter(subscriber dto.MailingList, subject, textBody, htmlBody string) (*dto.Email, error) {
+	if match := bodyRe.FindStringSubmatch(htmlBody); match != nil {
+		htmlBody = strings.Join(styleRe.FindAllString(htmlBody, -1), "\n") + match[1]
+	}
+
+	email, err := b.render(TemplateNewsletter, TemplateData{
+		MailingList: subscriber,
+		// The issue is written by the site owner, so its HTML is trusted
+		Content:        htmltemplate.HTML(htmlBody),
+		Subject:        subject,
+		Text:           strings.TrimRight(textBody, "\n"),
+		UnsubscribeURL: b.UnsubscribeURL(subscriber.Email),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if htmlBody == "" {
+		email.HTMLBody = ""
+	}
+	return email, nil
+}
+
+func (b *Builder) render(name string, data TemplateData) (*dto.Email, error) {
+	data.ListName = b.list.Name
+	if data.ListName == "" {
+		data.ListName = DefaultListName
+	}
+	if data.UnsubscribeURL != "" {
+		data.PreferencesURL = b.PreferencesURL(data.Email)
+	}
+
+	rendered, err := b.templates.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	email := b.newEmail(data.Email, rendered.Subject, rendered.Text)
+	email.HTMLBody = rendered.HTML
+	return email, nil
+}
+
+// newEmail creates a message to a subscriber. Every message carries the
+// List-Unsubscribe headers so mail clients can offer one-click unsubscribe
+// as described in RFC 2369 and RFC 8058.
+func (b *Builder) newEmail(to, subject, textBody string) *dto.Email {
+	return &dto.Email{
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + b.UnsubscribeURL(to) + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+		From:     b.from,
+		To:       to,
+		Subject:  subject,
+		TextBody: textBody,
+	}
+}
@@ -0,0 +1,251 @@
+package emails
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	styleBlockRe   = regexp.MustCompile(`(?is)<style[^>]*>(.*?)</style>`)
+	cssCommentRe   = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	simpleSelector = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9]*)?((?:[.#][-_a-zA-Z0-9]+)*)$`)
+	selectorPartRe = regexp.MustCompile(`[.#][-_a-zA-Z0-9]+`)
+	startTagRe     = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^\s=>/]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+))?)*)\s*(/?)>`)
+	attributeRe    = regexp.MustCompile(`(?i)\s+(class|id|style)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// cssRule is a declaration block for one simple selector such as "p",
+// ".button" or "a.footer-link".
+type cssRule struct {
+	tag          string
+	id           string
+	classes      []string
+	declarations []cssDeclaration
+	specificity  int
+	order        int
+}
+
+type cssDeclaration struct {
+	property string
+	value    string
+}
+
+// InlineCSS copies the rules from <style> blocks into style attributes,
+// since many mail clients ignore or strip stylesheets. Only simple type,
+// class and id selectors are inlined; anything else, such as @media
+// queries, stays in a <style> block for the clients that support it.
+// Existing style attributes take precedence over stylesheet rules.
+func InlineCSS(document string) string {
+	blocks := styleBlockRe.FindAllStringSubmatch(document, -1)
+	if len(blocks) == 0 {
+		return document
+	}
+
+	var (
+		rules    []cssRule
+		leftover strings.Builder
+	)
+	for _, block := range blocks {
+		parseStylesheet(block[1], &rules, &leftover)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity < rules[j].specificity
+		}
+		return rules[i].order < rules[j].order
+	})
+
+	// Keep what could not be inlined in the first style block
+	first := true
+	document = styleBlockRe.ReplaceAllStringFunc(document, func(string) string {
+		if !first || leftover.Len() == 0 {
+			return ""
+		}
+		first = false
+		return "<style>\n" + leftover.String() + "</style>"
+	})
+
+	// Only elements inside <body> are styled
+	head, body := "", document
+	if index := strings.Index(strings.ToLower(document), "<body"); index >= 0 {
+		head, body = document[:index], document[index:]
+	}
+
+	body = startTagRe.ReplaceAllStringFunc(body, func(tag string) string {
+		return applyRules(tag, rules)
+	})
+
+	return head + body
+}
+
+// parseStylesheet appends the inlinable rules in css to rules and writes
+// everything else to leftover.
+func parseStylesheet(css string, rules *[]cssRule, leftover *strings.Builder) {
+	css = cssCommentRe.ReplaceAllString(css, "")
+
+	for {
+		open := strings.Index(css, "{")
+		if open < 0 {
+			return
+		}
+		prelude := strings.TrimSpace(css[:open])
+
+		// Find the matching brace so nested blocks like @media stay intact
+		depth, end := 0, -1
+		for i := open; i < len(css); i++ {
+			switch css[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if depth == 0 {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return
+		}
+		block := css[open+1 : end]
+		css = css[end+1:]
+
+		if strings.HasPrefix(prelude, "@") {
+			leftover.WriteString(prelude + " {" + block + "}\n")
+			continue
+		}
+
+		declarations := parseDeclarations(block)
+		var rest []string
+		for _, selector := range strings.Split(prelude, ",") {
+			selector = strings.TrimSpace(selector)
+			rule, ok := parseSelector(selector)
+			if !ok {
+				rest = append(rest, selector)
+				continue
+			}
+			rule.declarations = declarations
+			rule.order = len(*rules)
+			*rules = append(*rules, rule)
+		}
+		if len(rest) > 0 {
+			leftover.WriteString(strings.Join(rest, ", ") + " {" + block + "}\n")
+		}
+	}
+}
+
+func parseSelector(selector string) (cssRule, bool) {
+	match := simpleSelector.FindStringSubmatch(selector)
+	if match == nil || selector == "" {
+		return cssRule{}, false
+	}
+
+	rule := cssRule{tag: strings.ToLower(match[1])}
+	if rule.tag != "" {
+		rule.specificity++
+	}
+	for _, part := range selectorPartRe.FindAllString(match[2], -1) {
+		if part[0] == '#' {
+			rule.id = part[1:]
+			rule.specificity += 100
+		} else {
+			rule.classes = append(rule.classes, part[1:])
+			rule.specificity += 10
+		}
+	}
+	return rule, true
+}
+
+func parseDeclarations(block string) []cssDeclaration {
+	var declarations []cssDeclaration
+	for _, declaration := range strings.Split(block, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+		if property != "" && value != "" {
+			declarations = append(declarations, cssDeclaration{property: property, value: value})
+		}
+	}
+	return declarations
+}
+
+// applyRules rewrites a start tag with the matching declarations merged into
+// its style attribute.
+func applyRules(tag string, rules []cssRule) string {
+	match := startTagRe.FindStringSubmatch(tag)
+	name := strings.ToLower(match[1])
+
+	var id, style string
+	var classes []string
+	for _, attribute := range attributeRe.FindAllStringSubmatch(match[2], -1) {
+		value := html.UnescapeString(attribute[2] + attribute[3] + attribute[4])
+		switch strings.ToLower(attribute[1]) {
+		case "class":
+			classes = strings.Fields(value)
+		case "id":
+			id = value
+		case "style":
+			style = value
+		}
+	}
+
+	var declarations []cssDeclaration
+	for _, rule := range rules {
+		if rule.matches(name, id, classes) {
+			declarations = append(declarations, rule.declarations...)
+		}
+	}
+	if len(declarations) == 0 {
+		return tag
+	}
+	declarations = append(declarations, parseDeclarations(style)...)
+
+	// Later declarations win, but keep the position of the first occurrence
+	values := map[string]string{}
+	var properties []string
+	for _, declaration := range declarations {
+		if _, seen := values[declaration.property]; !seen {
+			properties = append(properties, declaration.property)
+		}
+		values[declaration.property] = declaration.value
+	}
+	parts := make([]string, 0, len(properties))
+	for _, property := range properties {
+		parts = append(parts, property+": "+values[property])
+	}
+
+	attributes := attributeRe.ReplaceAllStringFunc(match[2], func(attribute string) string {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(attribute)), "style") {
+			return ""
+		}
+		return attribute
+	})
+
+	closing := ">"
+	if match[3] != "" {
+		closing = " />"
+	}
+	return "<" + match[1] + attributes + ` style="` + html.EscapeString(strings.Join(parts, "; ")) + `"` + closing
+}
+
+func (r cssRule) matches(tag, id string, classes []string) bool {
+	if r.tag != "" && r.tag != tag {
+		return false
+	}
+	if r.id != "" && r.id != id {
+		return false
+	}
+	for _, class := range r.classes {
+		if !slices.Contains(classes, class) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package emails
+
+import (
+	"backend-go/internal/dto"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// Template names. Each one is a pair of files, <name>.txt for the plain-text
+// body and <name>.html for the HTML body. The text template also defines the
+// subject with {{define "subject"}}.
+const (
+	TemplateConfirmation = "confirmation"
+	TemplateWelcome      = "welcome"
+	TemplateNewPost      = "new_post"
+	TemplateUnsubscribed = "unsubscribed"
+	TemplateNewsletter   = "newsletter"
+	// TemplatePrivacyRequest asks to confirm a data access or erasure request.
+	TemplatePrivacyRequest = "privacy_request"
+)
+
+var templateNames = []string{
+	TemplateConfirmation,
+	TemplateWelcome,
+	TemplateNewPost,
+	TemplateUnsubscribed,
+	TemplateNewsletter,
+	TemplatePrivacyRequest,
+}
+
+//go:embed templates
+var defaultFiles embed.FS
+
+var defaultTemplates = sync.OnceValue(func() *Templates {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		panic(fmt.Sprintf("emails: invalid embedded templates: %v", err))
+	}
+	return templates
+})
+
+// TemplateData is passed to every template. The subscriber's fields, such as
+// .Username and .Email, are available directly.
+type TemplateData struct {
+	dto.MailingList
+	Post dto.Post
+	// Content is the trusted HTML body of a newsletter issue.
+	Content        htmltemplate.HTML
+	Subject        string
+	Text           string
+	ConfirmURL     string
+	UnsubscribeURL string
+	// PreferencesURL links to the preference page, when there is one. It is
+	// set on every email with an UnsubscribeURL.
+	PreferencesURL string
+	// Request is the kind of a privacy request, access or erasure.
+	Request string
+	// ListName names the mailing list the email is about.
+	ListName string
+}
+
+// Rendered is the output of a template pair.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Templates holds the parsed text and HTML templates for every email.
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates parses the email templates. Files found in dir replace the
+// embedded default with the same name, so a directory only needs to contain
+// the templates that are customized. An empty dir uses the defaults.
+//
+// HTML templates define "content", which is rendered inside "layout" from
+// layout.html; text templates can include "footer" from footer.txt.
+func LoadTemplates(dir string) (*Templates, error) {
+	templates := &Templates{
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+	}
+
+	layout, err := readTemplate(dir, "layout.html")
+	if err != nil {
+		return nil, err
+	}
+	footer, err := readTemplate(dir, "footer.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range templateNames {
+		textSource, err := readTemplate(dir, name+".txt")
+		if err != nil {
+			return nil, err
+		}
+		text, err := texttemplate.New(name).Parse(footer)
+		if err == nil {
+			text, err = text.Parse(textSource)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s.txt: %w", name, err)
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s.txt does not define a subject", name)
+		}
+
+		htmlSource, err := readTemplate(dir, name+".html")
+		if err != nil {
+			return nil, err
+		}
+		html, err := htmltemplate.New(name).Parse(layout)
+		if err == nil {
+			html, err = html.Parse(htmlSource)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s.html: %w", name, err)
+		}
+
+		templates.text[name] = text
+		templates.html[name] = html
+	}
+
+	return templates, nil
+}
+
+// Render executes the template pair called name. The HTML body has the
+// layout's CSS inlined so it renders in mail clients that drop <style>.
+func (t *Templates) Render(name string, data TemplateData) (*Rendered, error) {
+	text, ok := t.text[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, body, html bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := text.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s.txt: %w", name, err)
+	}
+	if err := t.html[name].ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s.html: %w", name, err)
+	}
+
+	return &Rendered{
+		// Subjects are a single header line
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    InlineCSS(html.String()),
+	}, nil
+}
+
+func readTemplate(dir, file string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+	}
+
+	data, err := defaultFiles.ReadFile("templates/" + file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", file, err)
+	}
+	return string(data), nil
+}
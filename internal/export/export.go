@@ -0,0 +1,242 @@
+// Package export writes the mailing list as CSV, JSON Lines or vCard.
+package export
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Export formats.
+const (
+	// FormatCSV has the columns import reads back.
+	FormatCSV = "csv"
+	// FormatNDJSON writes one JSON object per line.
+	FormatNDJSON = "ndjson"
+	// FormatVCard writes a vCard 3.0 contact per subscriber.
+	FormatVCard = "vcard"
+)
+
+// PageSize is how many subscribers are read from the repository and written
+// at a time.
+const PageSize = 200
+
+var formats = map[string]struct {
+	contentType string
+	extension   string
+	newWriter   func(w *bufio.Writer) recordWriter
+}{
+	FormatCSV:    {"text/csv; charset=utf-8", "csv", newCSVWriter},
+	FormatNDJSON: {"application/x-ndjson", "ndjson", newNDJSONWriter},
+	FormatVCard:  {"text/vcard; charset=utf-8", "vcf", newVCardWriter},
+}
+
+// Formats returns the names accepted by Export.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ContentType returns the media type of format, or "" for an unknown one.
+func ContentType(format string) string {
+	return formats[format].contentType
+}
+
+// Filename returns the file name an export in format is offered as.
+func Filename(format string) string {
+	return "subscribers." + formats[format].extension
+}
+
+// recordWriter encodes subscribers into a buffered writer.
+type recordWriter interface {
+	// Begin writes what comes before the first subscriber, such as a header.
+	Begin() error
+	Write(subscriber *dto.MailingList) error
+}
+
+// flusher is implemented by http.ResponseWriter.
+type flusher interface {
+	Flush()
+}
+
+// Export writes the subscribers of the list with listID matching query to w,
+// newest first. It reads and writes them a page at a time, so memory doesn't
+// grow with the list, and flushes w after every page when it supports it.
+// query.Cursor and query.Limit are ignored. It returns how many subscribers
+// were written.
+func Export(repo interfaces.MailingListRepository, listID string, query dto.SubscriberQuery, format string, w io.Writer) (int, error) {
+	entry, ok := formats[format]
+	if !ok {
+		return 0, fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+
+	buffer := bufio.NewWriter(w)
+	writer := entry.newWriter(buffer)
+	query.Cursor = ""
+	query.Limit = PageSize
+	// Nothing reaches w before the first page was read, so callers can still
+	// report an error instead
+	if err := writer.Begin(); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	exported := 0
+	for {
+		page, err := repo.List(listID, query)
+		if err != nil {
+			return exported, fmt.Errorf("failed to list subscribers: %w", err)
+		}
+		for n := range page.Subscribers {
+			if err := writer.Write(&page.Subscribers[n]); err != nil {
+				return exported, fmt.Errorf("failed to write export: %w", err)
+			}
+			exported++
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return exported, fmt.Errorf("failed to write export: %w", err)
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+
+		if page.NextCursor == "" {
+			return exported, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w *bufio.Writer) recordWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Begin() error {
+	return c.writer.Write([]string{"Username", "Email", "CreatedAt", "Status", "ConfirmedAt", "UnsubscribedAt"})
+}
+
+func (c *csvWriter) Write(subscriber *dto.MailingList) error {
+	err := c.writer.Write([]string{
+		neutralizeFormula(subscriber.Username),
+		neutralizeFormula(subscriber.Email),
+		subscriber.CreatedAt.UTC().Format(time.RFC3339),
+		subscriber.Status,
+		formatOptional(subscriber.ConfirmedAt),
+		formatOptional(subscriber.UnsubscribedAt),
+	})
+	if err != nil {
+		return err
+	}
+	// Move the row into the page buffer, which Export flushes
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// neutralizeFormula prefixes values that spreadsheets would run as a
+// formula, such as a username of "=HYPERLINK(...)" from a public signup,
+// with a quote so they are shown as text. blogctl import -format export
+// strips it again.
+func neutralizeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatOptional(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w *bufio.Writer) recordWriter {
+	return &ndjsonWriter{encoder: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) Begin() error {
+	return nil
+}
+
+func (n *ndjsonWriter) Write(subscriber *dto.MailingList) error {
+	return n.encoder.Encode(subscriber)
+}
+
+type vcardWriter struct {
+	w *bufio.Writer
+}
+
+func newVCardWriter(w *bufio.Writer) recordWriter {
+	return &vcardWriter{w: w}
+}
+
+func (v *vcardWriter) Begin() error {
+	return nil
+}
+
+// Write writes a contact named after the username, noting the subscription
+// status and signup date.
+func (v *vcardWriter) Write(subscriber *dto.MailingList) error {
+	name := escapeVCard(subscriber.Username)
+	note := escapeVCard(fmt.Sprintf("Mailing list: %s since %s", subscriber.Status, subscriber.CreatedAt.UTC().Format(time.DateOnly)))
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:" + name,
+		"N:;" + name + ";;;",
+		"EMAIL;TYPE=INTERNET:" + subscriber.Email,
+		"NOTE:" + note,
+		"END:VCARD",
+	}
+	for _, line := range lines {
+		if _, err := v.w.WriteString(foldVCard(line) + "\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeVCard escapes the characters with a meaning in vCard text values.
+func escapeVCard(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// foldVCard splits lines longer than 75 bytes, continuing them on lines
+// starting with a space, without splitting UTF-8 characters.
+func foldVCard(line string) string {
+	var folded strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose a byte to the leading space
+		limit = 74
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
@@ -0,0 +1,202 @@
+package feeds
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/newsletter"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxFeedSize bounds how much of a feed is read.
+const maxFeedSize = 10 << 20
+
+// ErrUnknownFormat is returned for documents that are neither RSS nor Atom.
+var ErrUnknownFormat = errors.New("feed is neither RSS nor Atom")
+
+type rssFeed struct {
+	Items []struct {
+		GUID        string   `xml:"guid"`
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link"`
+		Description string   `xml:"description"`
+		PubDate     string   `xml:"pubDate"`
+		Categories  []string `xml:"category"`
+	} `xml:"channel>item"`
+}
+
+type atomFeed struct {
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Summary   string `xml:"summary"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Links     []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+// dateLayouts covers the RFC 822 variants used by RSS generators, Hugo
+// included, and the RFC 3339 dates used by Atom.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// Fetch reads the feed at source, which is either an http(s) URL or a path to
+// a local file such as Hugo's public/index.xml.
+func Fetch(client *http.Client, source string) ([]dto.Post, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feed: %w", err)
+		}
+		return Parse(data)
+	}
+
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch feed: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse extracts posts from an RSS 2.0 or Atom document. Posts without a
+// GUID fall back to their link as identifier; posts with neither are skipped.
+func Parse(data []byte) ([]dto.Post, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []dto.Post
+	switch root {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		for _, item := range feed.Items {
+			posts = append(posts, dto.Post{
+				Published:  parseDate(item.PubDate),
+				GUID:       firstNonEmpty(item.GUID, item.Link),
+				Title:      strings.TrimSpace(item.Title),
+				Link:       strings.TrimSpace(item.Link),
+				Summary:    strings.TrimSpace(newsletter.HTMLToText(item.Description)),
+				Categories: topicSlugs(item.Categories),
+			})
+		}
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		for _, entry := range feed.Entries {
+			var link string
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			terms := make([]string, len(entry.Categories))
+			for i, category := range entry.Categories {
+				terms[i] = category.Term
+			}
+			posts = append(posts, dto.Post{
+				Published:  parseDate(firstNonEmpty(entry.Published, entry.Updated)),
+				GUID:       firstNonEmpty(entry.ID, link),
+				Title:      strings.TrimSpace(entry.Title),
+				Link:       strings.TrimSpace(link),
+				Summary:    strings.TrimSpace(newsletter.HTMLToText(entry.Summary)),
+				Categories: topicSlugs(terms),
+			})
+		}
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	valid := posts[:0]
+	for _, post := range posts {
+		if post.GUID != "" {
+			valid = append(valid, post)
+		}
+	}
+	return valid, nil
+}
+
+// rootElement returns the local name of the document's first element.
+func rootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return "", ErrUnknownFormat
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+// topicSlugs turns feed categories such as "Self Hosting" into topic slugs
+// such as "self-hosting", so posts can be matched to the topics subscribers
+// chose.
+func topicSlugs(categories []string) []string {
+	var slugs []string
+	for _, category := range categories {
+		slug := strings.Join(strings.Fields(strings.ToLower(category)), "-")
+		if slug != "" && !slices.Contains(slugs, slug) {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package feeds
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/interfaces"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Watcher polls the blog feed and announces new posts to the active
+// subscribers of the email builder's list who want every email as it is
+// sent and chose one of the post's categories as a topic, or none at all.
+type Watcher struct {
+	posts       interfaces.PostRepository
+	subscribers interfaces.MailingListRepository
+	emails      *emails.Builder
+	client      *http.Client
+	source      string
+}
+
+func NewWatcher(source string, posts interfaces.PostRepository, subscribers interfaces.MailingListRepository, builder *emails.Builder) *Watcher {
+	return &Watcher{
+		posts:       posts,
+		subscribers: subscribers,
+		emails:      builder,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		source:      source,
+	}
+}
+
+// Run checks the feed immediately and then every interval until ctx is
+// cancelled.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(); err != nil {
+			log.Printf("Feed watcher error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check reads the feed once and queues an announcement for every post that
+// has not been announced yet, oldest first. It returns the number of posts
+// announced. On the very first run every post in the feed is recorded
+// without sending anything, so subscribers are not flooded with the archive;
+// an empty feed counts as a first run too, so the blog's first post is still
+// announced.
+func (w *Watcher) Check() (int, error) {
+	posts, err := Fetch(w.client, w.source)
+	if err != nil {
+		return 0, err
+	}
+
+	initialized, err := w.posts.Initialized()
+	if err != nil {
+		return 0, err
+	}
+	if !initialized {
+		if err := w.posts.Initialize(posts); err != nil {
+			return 0, err
+		}
+		log.Printf("Feed watcher initialized with %d existing posts", len(posts))
+		return 0, nil
+	}
+
+	announced, err := w.posts.AnnouncedGUIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	var fresh []dto.Post
+	for _, post := range posts {
+		if !announced[post.GUID] {
+			fresh = append(fresh, post)
+		}
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return fresh[i].Published.Before(fresh[j].Published)
+	})
+
+	subscribers, err := w.subscribers.ListActive(w.emails.List().ID)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, post := range fresh {
+		messages := make([]*dto.Email, 0, len(subscribers))
+		for _, subscriber := range subscribers {
+			// Digest subscribers hear about posts from the newsletter
+			if !subscriber.WantsFrequency(dto.FrequencyImmediate) || !subscriber.WantsTopics(post.Categories) {
+				continue
+			}
+			email, err := w.emails.NewPost(subscriber, post)
+			if err != nil {
+				return i, err
+			}
+			messages = append(messages, email)
+		}
+
+		if err := w.posts.Announce(post, messages); err != nil {
+			return i, fmt.Errorf("failed to announce %s: %w", post.GUID, err)
+		}
+		log.Printf("Announced post %q to %d subscribers", post.Title, len(messages))
+	}
+
+	return len(fresh), nil
+}
@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CSVSource reads subscribers from a CSV file with a header row. Columns are
+// found by name, so their order doesn't matter and unknown columns are
+// ignored.
+type CSVSource struct {
+	reader  *csv.Reader
+	format  *format
+	header  []string
+	columns map[string]int
+	now     time.Time
+}
+
+// NewCSVSource reads the header of a file in our own CSV format from r. Use
+// NewSource for exports of other providers.
+func NewCSVSource(r io.Reader) (*CSVSource, error) {
+	return newCSVSource(r, formats[FormatCSV])
+}
+
+func newCSVSource(r io.Reader, format *format) (*CSVSource, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	columns, err := mapColumns(header, format.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVSource{reader: reader, format: format, header: header, columns: columns, now: time.Now().UTC()}, nil
+}
+
+// mapColumns finds the index of each field in header, using the first column
+// matching one of its aliases.
+func mapColumns(header []string, aliases map[string][]string) (map[string]int, error) {
+	columns := map[string]int{}
+	for field, names := range aliases {
+		for index, name := range header {
+			if _, found := columns[field]; !found && slices.Contains(names, columnKey(name)) {
+				columns[field] = index
+			}
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("CSV header has no email column: %s", strings.Join(header, ", "))
+	}
+	return columns, nil
+}
+
+func (s *CSVSource) Header() []string {
+	return s.header
+}
+
+func (s *CSVSource) Next() (*Record, error) {
+	fields, err := s.reader.Read()
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &Record{Line: parseErr.StartLine, Fields: fields, Err: fmt.Errorf("malformed CSV row: %w", parseErr.Err)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	value := func(field string) string {
+		index, ok := s.columns[field]
+		if !ok || index >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[index])
+	}
+
+	record := &Record{Line: line, Fields: fields}
+	record.Subscriber, record.Err = s.format.subscriber(value, s.now)
+	return record, nil
+}
@@ -0,0 +1,353 @@
+package importer
+
+import (
+	"backend-go/internal/dto"
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Import formats accepted by NewSource.
+const (
+	// FormatCSV is our own CSV layout, also read by NewCSVSource.
+	FormatCSV = "csv"
+	// FormatExport is our own CSV layout as written by blogctl export, which
+	// quotes values spreadsheets would run as a formula.
+	FormatExport = "export"
+	// FormatMailchimp reads the subscribed, unsubscribed and cleaned CSV
+	// files of a Mailchimp audience export.
+	FormatMailchimp = "mailchimp"
+	// FormatButtondown reads a Buttondown subscriber export, as CSV or as the
+	// JSON returned by its API.
+	FormatButtondown = "buttondown"
+	// FormatSubstack reads the email list CSV exported by Substack.
+	FormatSubstack = "substack"
+)
+
+// row returns the trimmed value of a field, or "" when the record lacks it.
+type row func(field string) string
+
+// format maps the columns of an export onto subscribers. Column names are
+// compared case-insensitively and ignoring spaces and punctuation, so
+// "Email Address" matches emailaddress. Only an email column is required.
+type format struct {
+	columns map[string][]string
+	// subscriber builds a subscriber from a record. Records without a signup
+	// date get now, the time the import started.
+	subscriber func(value row, now time.Time) (dto.MailingList, error)
+}
+
+var formats = map[string]*format{
+	FormatCSV: {
+		columns: map[string][]string{
+			"email":           {"email", "emailaddress", "mail"},
+			"username":        {"username", "name", "displayname", "fullname"},
+			"created_at":      {"createdat", "created", "signupdate", "subscribedat", "date"},
+			"status":          {"status"},
+			"confirmed_at":    {"confirmedat"},
+			"unsubscribed_at": {"unsubscribedat"},
+		},
+		subscriber: csvSubscriber,
+	},
+	FormatExport: {
+		columns: map[string][]string{
+			"email":           {"email"},
+			"username":        {"username"},
+			"created_at":      {"createdat"},
+			"status":          {"status"},
+			"confirmed_at":    {"confirmedat"},
+			"unsubscribed_at": {"unsubscribedat"},
+		},
+		subscriber: exportSubscriber,
+	},
+	FormatMailchimp: {
+		columns: map[string][]string{
+			"email":        {"emailaddress", "email"},
+			"first_name":   {"firstname", "fname"},
+			"last_name":    {"lastname", "lname"},
+			"status":       {"status"},
+			"optin_time":   {"optintime"},
+			"confirm_time": {"confirmtime"},
+			"unsub_time":   {"unsubtime"},
+			"clean_time":   {"cleantime"},
+		},
+		subscriber: mailchimpSubscriber,
+	},
+	FormatButtondown: {
+		columns: map[string][]string{
+			"email":             {"email", "emailaddress"},
+			"subscriber_type":   {"subscribertype", "type"},
+			"creation_date":     {"creationdate"},
+			"unsubscribed_date": {"unsubscriptiondate"},
+		},
+		subscriber: buttondownSubscriber,
+	},
+	FormatSubstack: {
+		columns: map[string][]string{
+			"email":          {"email"},
+			"name":           {"name"},
+			"created_at":     {"createdat"},
+			"email_disabled": {"emaildisabled"},
+		},
+		subscriber: substackSubscriber,
+	},
+}
+
+// timeLayouts are the timestamp formats accepted in date columns.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	time.DateTime,
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// Formats returns the names accepted by NewSource.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NewSource reads an export in the named format from r. Files starting with
+// "[" or "{" are read as JSON, either an array of subscriber objects or an
+// object with them under "results" like Buttondown's API; anything else is
+// read as CSV.
+func NewSource(name string, r io.Reader) (Source, error) {
+	format, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q, expected one of %s", name, strings.Join(Formats(), ", "))
+	}
+
+	reader := bufio.NewReader(r)
+	if bom, _ := reader.Peek(3); string(bom) == "\ufeff" {
+		_, _ = reader.Discard(3)
+	}
+	if isJSON(reader) {
+		return newJSONSource(reader, format)
+	}
+	return newCSVSource(reader, format)
+}
+
+// isJSON peeks at the first character after any white space. The byte
+// order mark has already been skipped.
+func isJSON(reader *bufio.Reader) bool {
+	peeked, _ := reader.Peek(64)
+	text := strings.TrimLeftFunc(string(peeked), unicode.IsSpace)
+	return strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{")
+}
+
+func columnKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+func csvSubscriber(value row, now time.Time) (dto.MailingList, error) {
+	subscriber := dto.MailingList{
+		Username: value("username"),
+		Email:    value("email"),
+		Status:   dto.StatusActive,
+	}
+	if status := strings.ToLower(value("status")); status != "" {
+		switch status {
+		case dto.StatusPending, dto.StatusActive, dto.StatusUnsubscribed:
+			subscriber.Status = status
+		default:
+			return subscriber, fmt.Errorf("unknown status %q", value("status"))
+		}
+	}
+
+	times, err := parseTimes(value, "created_at", "confirmed_at", "unsubscribed_at")
+	if err != nil {
+		return subscriber, err
+	}
+	subscriber.CreatedAt = firstTime(now, times["created_at"])
+	subscriber.ConfirmedAt = times["confirmed_at"]
+	subscriber.UnsubscribedAt = times["unsubscribed_at"]
+	return subscriber, nil
+}
+
+// exportSubscriber reads a row of our own export. Only the export quotes
+// formulas, so a leading quote in any other file is kept as it was written.
+func exportSubscriber(value row, now time.Time) (dto.MailingList, error) {
+	subscriber, err := csvSubscriber(value, now)
+	subscriber.Username = restoreFormula(subscriber.Username)
+	subscriber.Email = restoreFormula(subscriber.Email)
+	return subscriber, err
+}
+
+// restoreFormula removes the quote blogctl export puts in front of values
+// spreadsheets would run as a formula.
+func restoreFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// mailchimpSubscriber keeps the opt-in time as the signup date. Mailchimp
+// exports each status to its own file without a status column, so it is
+// told from the unsubscribe and clean times. Cleaned addresses bounced and
+// are imported as unsubscribed so they are never mailed again.
+func mailchimpSubscriber(value row, now time.Time) (dto.MailingList, error) {
+	subscriber := dto.MailingList{
+		Username: strings.TrimSpace(value("first_name") + " " + value("last_name")),
+		Email:    value("email"),
+	}
+
+	times, err := parseTimes(value, "optin_time", "confirm_time", "unsub_time", "clean_time")
+	if err != nil {
+		return subscriber, err
+	}
+	subscriber.CreatedAt = firstTime(now, times["optin_time"], times["confirm_time"])
+	subscriber.ConfirmedAt = times["confirm_time"]
+
+	status := strings.ToLower(value("status"))
+	if status == "" {
+		switch {
+		case times["clean_time"] != nil:
+			status = "cleaned"
+		case times["unsub_time"] != nil:
+			status = "unsubscribed"
+		default:
+			status = "subscribed"
+		}
+	}
+
+	switch status {
+	case "subscribed":
+		subscriber.Status = dto.StatusActive
+	case "unsubscribed":
+		subscriber.Status = dto.StatusUnsubscribed
+		subscriber.UnsubscribedAt = times["unsub_time"]
+	case "cleaned":
+		subscriber.Status = dto.StatusUnsubscribed
+		subscriber.UnsubscribedAt = times["clean_time"]
+	case "pending":
+		subscriber.Status = dto.StatusPending
+		subscriber.ConfirmedAt = nil
+	default:
+		return subscriber, fmt.Errorf("unknown status %q", value("status"))
+	}
+	return subscriber, nil
+}
+
+// buttondownStatuses maps Buttondown subscriber types onto our statuses.
+// Paid, gifted and trial subscribers receive the newsletter like regular
+// ones. Paused subscribers and addresses Buttondown stopped mailing are
+// imported as unsubscribed, since there is no way to resume them here.
+var buttondownStatuses = map[string]string{
+	"regular":       dto.StatusActive,
+	"premium":       dto.StatusActive,
+	"gifted":        dto.StatusActive,
+	"trialed":       dto.StatusActive,
+	"churned":       dto.StatusActive,
+	"past_due":      dto.StatusActive,
+	"unactivated":   dto.StatusPending,
+	"unsubscribed":  dto.StatusUnsubscribed,
+	"removed":       dto.StatusUnsubscribed,
+	"complained":    dto.StatusUnsubscribed,
+	"undeliverable": dto.StatusUnsubscribed,
+	"spammy":        dto.StatusUnsubscribed,
+	"paused":        dto.StatusUnsubscribed,
+}
+
+func buttondownSubscriber(value row, now time.Time) (dto.MailingList, error) {
+	subscriber := dto.MailingList{
+		Email:  value("email"),
+		Status: dto.StatusActive,
+	}
+	if kind := strings.ToLower(value("subscriber_type")); kind != "" {
+		status, ok := buttondownStatuses[kind]
+		if !ok {
+			return subscriber, fmt.Errorf("unknown subscriber type %q", value("subscriber_type"))
+		}
+		subscriber.Status = status
+	}
+
+	times, err := parseTimes(value, "creation_date", "unsubscribed_date")
+	if err != nil {
+		return subscriber, err
+	}
+	subscriber.CreatedAt = firstTime(now, times["creation_date"])
+	if subscriber.Status == dto.StatusUnsubscribed {
+		subscriber.UnsubscribedAt = times["unsubscribed_date"]
+	}
+	return subscriber, nil
+}
+
+// substackSubscriber imports readers whose email is disabled as
+// unsubscribed. Substack doesn't export when that happened.
+func substackSubscriber(value row, now time.Time) (dto.MailingList, error) {
+	subscriber := dto.MailingList{
+		Username: value("name"),
+		Email:    value("email"),
+		Status:   dto.StatusActive,
+	}
+	if raw := value("email_disabled"); raw != "" {
+		disabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return subscriber, fmt.Errorf("invalid email_disabled %q", raw)
+		}
+		if disabled {
+			subscriber.Status = dto.StatusUnsubscribed
+		}
+	}
+
+	times, err := parseTimes(value, "created_at")
+	if err != nil {
+		return subscriber, err
+	}
+	subscriber.CreatedAt = firstTime(now, times["created_at"])
+	return subscriber, nil
+}
+
+// parseTimes parses the given date fields, leaving empty ones nil.
+func parseTimes(value row, fields ...string) (map[string]*time.Time, error) {
+	times := make(map[string]*time.Time, len(fields))
+	for _, field := range fields {
+		raw := value(field)
+		if raw == "" {
+			continue
+		}
+		parsed, err := parseTime(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		times[field] = &parsed
+	}
+	return times, nil
+}
+
+// firstTime returns the first time that is set, or fallback.
+func firstTime(fallback time.Time, times ...*time.Time) time.Time {
+	for _, t := range times {
+		if t != nil {
+			return *t
+		}
+	}
+	return fallback
+}
+
+// parseTime accepts the timeLayouts; values without a zone are UTC.
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBatchSize is how many rows are written per transaction.
+const DefaultBatchSize = 500
+
+// Record is one row read from an import file.
+type Record struct {
+	Subscriber dto.MailingList
+	// Err is why the row could not be read, such as a malformed date.
+	Err error
+	// Fields is the row as it appears in the file, copied to the reject file.
+	Fields []string
+	Line   int
+}
+
+// Source reads subscribers from an import file one record at a time.
+type Source interface {
+	// Header returns the file's column names, used for the reject file.
+	Header() []string
+	// Next returns the next record, or io.EOF after the last one.
+	Next() (*Record, error)
+}
+
+// Options control an import. The zero value imports into the default list in
+// batches of DefaultBatchSize without a reject file.
+type Options struct {
+	Normalizer normalize.Normalizer
+	// ListID is the list the subscribers are imported into.
+	ListID string
+	// Rejects receives a CSV of the rows that were not imported, with the
+	// line number and reason in front of the original columns.
+	Rejects   io.Writer
+	BatchSize int
+	// DryRun validates every row and checks for duplicates without storing
+	// anything.
+	DryRun bool
+	// Consent, if set, receives the history of every imported subscriber:
+	// when they subscribed and, as far as the file tells, confirmed and
+	// unsubscribed.
+	Consent interfaces.ConsentRepository
+}
+
+// Report counts what happened to the rows of an import.
+type Report struct {
+	Rows       int  `json:"rows"`
+	Imported   int  `json:"imported"`
+	Duplicates int  `json:"duplicates"`
+	Rejected   int  `json:"rejected"`
+	DryRun     bool `json:"dryRun"`
+}
+
+// Importer validates records from a Source and stores them in batches.
+type Importer struct {
+	repo      interfaces.ImportRepository
+	validator interfaces.MailingListValidator
+	rejects   *csv.Writer
+	// seen maps each normalized email in the file to its first line.
+	seen    map[string]int
+	batch   []*Record
+	options Options
+	report  Report
+}
+
+func NewImporter(repo interfaces.ImportRepository, validator interfaces.MailingListValidator, options Options) *Importer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.ListID == "" {
+		options.ListID = dto.DefaultListID
+	}
+	return &Importer{
+		repo:      repo,
+		validator: validator,
+		options:   options,
+	}
+}
+
+// Import reads source to the end. Invalid rows and duplicates are counted
+// and written to the reject file; an error is only returned when reading the
+// source or writing to the database fails, together with the report so far.
+func (i *Importer) Import(source Source) (*Report, error) {
+	i.report = Report{DryRun: i.options.DryRun}
+	i.seen = map[string]int{}
+	i.batch = nil
+	i.rejects = nil
+	if i.options.Rejects != nil {
+		i.rejects = csv.NewWriter(i.options.Rejects)
+		if err := i.rejects.Write(append([]string{"line", "reason"}, source.Header()...)); err != nil {
+			return &i.report, fmt.Errorf("failed to write rejects: %w", err)
+		}
+	}
+
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return &i.report, err
+		}
+		i.report.Rows++
+
+		if err := i.add(record); err != nil {
+			return &i.report, err
+		}
+		if len(i.batch) >= i.options.BatchSize {
+			if err := i.flush(); err != nil {
+				return &i.report, err
+			}
+		}
+	}
+
+	if err := i.flush(); err != nil {
+		return &i.report, err
+	}
+	if i.rejects != nil {
+		i.rejects.Flush()
+		if err := i.rejects.Error(); err != nil {
+			return &i.report, fmt.Errorf("failed to write rejects: %w", err)
+		}
+	}
+	return &i.report, nil
+}
+
+func (i *Importer) add(record *Record) error {
+	if record.Err != nil {
+		i.report.Rejected++
+		return i.reject(record, record.Err.Error())
+	}
+
+	subscriber := &record.Subscriber
+	subscriber.Email = normalize.Email(subscriber.Email)
+	if subscriber.Username == "" {
+		subscriber.Username, _, _ = strings.Cut(subscriber.Email, "@")
+	}
+	if fieldErrors := i.validator.Validate(subscriber); len(fieldErrors) > 0 {
+		reasons := make([]string, len(fieldErrors))
+		for n, fieldErr := range fieldErrors {
+			reasons[n] = fieldErr.Message
+		}
+		i.report.Rejected++
+		return i.reject(record, strings.Join(reasons, "; "))
+	}
+
+	subscriber.NormalizedEmail = i.options.Normalizer.Key(subscriber.Email)
+	if first, ok := i.seen[subscriber.NormalizedEmail]; ok {
+		i.report.Duplicates++
+		return i.reject(record, "duplicate of line "+strconv.Itoa(first))
+	}
+	i.seen[subscriber.NormalizedEmail] = record.Line
+
+	i.batch = append(i.batch, record)
+	return nil
+}
+
+func (i *Importer) flush() error {
+	if len(i.batch) == 0 {
+		return nil
+	}
+
+	subscribers := make([]dto.MailingList, len(i.batch))
+	for n, record := range i.batch {
+		subscribers[n] = record.Subscriber
+	}
+
+	inserted, err := i.repo.Import(i.options.ListID, subscribers, i.options.DryRun)
+	if err != nil {
+		return err
+	}
+	var events []*dto.ConsentEvent
+	for n, record := range i.batch {
+		if inserted[n] {
+			i.report.Imported++
+			events = append(events, consentHistory(i.options.ListID, record.Subscriber)...)
+			continue
+		}
+		i.report.Duplicates++
+		if err := i.reject(record, "already subscribed"); err != nil {
+			return err
+		}
+	}
+	if i.options.Consent != nil && !i.options.DryRun && len(events) > 0 {
+		if err := i.options.Consent.Record(events...); err != nil {
+			return err
+		}
+	}
+
+	i.batch = i.batch[:0]
+	return nil
+}
+
+// consentHistory returns the consent events of a subscriber imported into
+// the list with listID, dated as the file dates them.
+func consentHistory(listID string, subscriber dto.MailingList) []*dto.ConsentEvent {
+	event := func(kind string, at time.Time) *dto.ConsentEvent {
+		return &dto.ConsentEvent{
+			CreatedAt:       at,
+			ListID:          listID,
+			Email:           subscriber.Email,
+			NormalizedEmail: subscriber.NormalizedEmail,
+			Event:           kind,
+			Source:          dto.ConsentSourceImport,
+		}
+	}
+
+	events := []*dto.ConsentEvent{event(dto.ConsentSubscribe, subscriber.CreatedAt)}
+	if subscriber.ConfirmedAt != nil {
+		events = append(events, event(dto.ConsentConfirm, *subscriber.ConfirmedAt))
+	}
+	if subscriber.UnsubscribedAt != nil {
+		events = append(events, event(dto.ConsentUnsubscribe, *subscriber.UnsubscribedAt))
+	}
+	return events
+}
+
+// reject writes record to the reject file, if there is one.
+func (i *Importer) reject(record *Record, reason string) error {
+	if i.rejects == nil {
+		return nil
+	}
+	if err := i.rejects.Write(append([]string{strconv.Itoa(record.Line), reason}, record.Fields...)); err != nil {
+		return fmt.Errorf("failed to write rejects: %w", err)
+	}
+	return nil
+}
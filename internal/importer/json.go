@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JSONSource reads subscribers from a JSON array of objects, or from the
+// array under "results" of an object such as a page of Buttondown's API.
+// Object keys are matched like CSV column names. Records are numbered from 1
+// in place of line numbers.
+type JSONSource struct {
+	decoder *json.Decoder
+	format  *format
+	index   int
+	now     time.Time
+}
+
+func newJSONSource(r io.Reader, format *format) (*JSONSource, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	if token == json.Delim('{') {
+		if err := seekResults(decoder); err != nil {
+			return nil, err
+		}
+	} else if token != json.Delim('[') {
+		return nil, fmt.Errorf("JSON must be an array of subscribers")
+	}
+
+	return &JSONSource{decoder: decoder, format: format, now: time.Now().UTC()}, nil
+}
+
+// seekResults skips the object's other keys until the opening bracket of
+// its "results" array.
+func seekResults(decoder *json.Decoder) error {
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to read JSON: %w", err)
+		}
+		if token == "results" {
+			token, err := decoder.Token()
+			if err != nil {
+				return fmt.Errorf("failed to read JSON: %w", err)
+			}
+			if token != json.Delim('[') {
+				return fmt.Errorf("JSON results must be an array of subscribers")
+			}
+			return nil
+		}
+
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return fmt.Errorf("failed to read JSON: %w", err)
+		}
+	}
+	return fmt.Errorf("JSON object has no results array")
+}
+
+// Header names the single column the reject file copies records into.
+func (s *JSONSource) Header() []string {
+	return []string{"record"}
+}
+
+func (s *JSONSource) Next() (*Record, error) {
+	if !s.decoder.More() {
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := s.decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to read JSON record %d: %w", s.index+1, err)
+	}
+	s.index++
+	record := &Record{Line: s.index, Fields: []string{string(raw)}}
+
+	var object map[string]any
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		record.Err = errors.New("record is not an object")
+		return record, nil
+	}
+
+	values := map[string]string{}
+	for key, value := range object {
+		switch value := value.(type) {
+		case string:
+			values[columnKey(key)] = strings.TrimSpace(value)
+		case json.Number, bool:
+			values[columnKey(key)] = fmt.Sprint(value)
+		}
+	}
+	value := func(field string) string {
+		for _, name := range s.format.columns[field] {
+			if v, ok := values[name]; ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	record.Subscriber, record.Err = s.format.subscriber(value, s.now)
+	return record, nil
+}
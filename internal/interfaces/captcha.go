@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+// CaptchaVerifier checks a CAPTCHA response token solved in the browser.
+type CaptchaVerifier interface {
+	// Verify returns captcha.ErrFailed when the token is not a valid
+	// solution and another error when verification itself failed.
+	Verify(ctx context.Context, token, remoteIP string) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"backend-go/internal/dto"
+)
+
+type Mailer interface {
+	Send(email *dto.Email) error
+}
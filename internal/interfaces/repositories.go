@@ -2,8 +2,164 @@ package interfaces
 
 import (
 	"backend-go/internal/dto"
+	"errors"
+	"time"
 )
 
+var (
+	// ErrInvalidToken is returned when a token is unknown, forged or expired.
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrSubscriberNotFound is returned when no subscriber has the given email.
+	ErrSubscriberNotFound = errors.New("subscriber not found")
+	// ErrInvalidCursor is returned for a pagination cursor that was not
+	// issued by the repository.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrAPIKeyNotFound is returned when no API key matches.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrPrivacyRequestNotFound is returned when no privacy request has the
+	// given id.
+	ErrPrivacyRequestNotFound = errors.New("privacy request not found")
+	// ErrTopicNotFound is returned when no topic has the given slug.
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrTopicExists is returned when creating a topic whose slug is taken.
+	ErrTopicExists = errors.New("topic already exists")
+	// ErrListNotFound is returned when no mailing list has the given id.
+	ErrListNotFound = errors.New("list not found")
+	// ErrListExists is returned when creating a list whose id is taken.
+	ErrListExists = errors.New("list already exists")
+	// ErrListInUse is returned when deleting the default list or a list that
+	// still has subscribers.
+	ErrListInUse = errors.New("list is in use")
+)
+
+// MailingListRepository stores the subscribers of every list. Addresses are
+// unique within a list, so the same address can subscribe to several lists.
+// Methods return ErrListNotFound for an unknown listID.
 type MailingListRepository interface {
-	Save(newMailingList *dto.MailingList) error
+	Save(listID string, newMailingList *dto.MailingList) error
+	Confirm(listID, token string) (*dto.MailingList, error)
+	Unsubscribe(listID, email string) (*dto.MailingList, error)
+	ListActive(listID string) ([]dto.MailingList, error)
+	// List returns the list's subscribers matching query, newest first.
+	List(listID string, query dto.SubscriberQuery) (*dto.SubscriberPage, error)
+}
+
+// ListRepository stores the mailing lists served by the deployment.
+type ListRepository interface {
+	CreateList(list *dto.List) error
+	GetList(id string) (*dto.List, error)
+	// ListLists returns every list ordered by id.
+	ListLists() ([]dto.List, error)
+	// DeleteList deletes a list without subscribers. It returns ErrListInUse
+	// for the default list and for lists that still have subscribers.
+	DeleteList(id string) error
+}
+
+// ImportRepository stores subscribers read from an import file.
+type ImportRepository interface {
+	// Import inserts subscribers in a single transaction, keeping any stored
+	// subscriber with the same address untouched, and reports which ones were
+	// inserted. With dryRun the transaction is rolled back.
+	Import(listID string, subscribers []dto.MailingList, dryRun bool) ([]bool, error)
+}
+
+// DeliveryRepository records which subscribers already received a campaign
+// so an interrupted send can be resumed. Campaigns are identified by the
+// list they were sent to and their ID.
+type DeliveryRepository interface {
+	// Statuses returns the delivery status per recipient email for campaign
+	// of the list with listID.
+	Statuses(listID, campaign string) (map[string]string, error)
+	MarkSending(listID, campaign, email string) error
+	MarkSent(listID, campaign, email string) error
+	MarkFailed(listID, campaign, email string, sendErr error) error
+}
+
+// MailQueueRepository persists outbound emails until a worker delivers them.
+type MailQueueRepository interface {
+	Enqueue(email *dto.Email) error
+	// Claim marks the oldest due job as sending and returns it, or nil when
+	// nothing is due.
+	Claim(now time.Time) (*dto.MailJob, error)
+	MarkSent(id int64) error
+	Retry(id int64, nextAttempt time.Time, sendErr error) error
+	MarkDead(id int64, sendErr error) error
+	// Depth returns the number of jobs per state.
+	Depth() (map[string]int, error)
+	// PurgeSent deletes jobs sent before the given time and returns how many
+	// were deleted.
+	PurgeSent(before time.Time) (int64, error)
+}
+
+// PostRepository tracks which blog posts were already announced to
+// subscribers.
+type PostRepository interface {
+	// Initialized reports whether Initialize has run.
+	Initialized() (bool, error)
+	// Initialize records the posts the feed held when the watcher first ran
+	// as announced without emailing anyone.
+	Initialize(posts []dto.Post) error
+	AnnouncedGUIDs() (map[string]bool, error)
+	// Announce records post as announced and queues emails in one step, so a
+	// post is never announced twice or lost between the two. Posts that are
+	// already recorded are ignored.
+	Announce(post dto.Post, emails []*dto.Email) error
+}
+
+// APIKeyRepository stores admin API keys by the hash of their secret.
+type APIKeyRepository interface {
+	Create(key *dto.APIKey, hash string) error
+	// FindByHash returns the key with hash, including revoked keys.
+	FindByHash(hash string) (*dto.APIKey, error)
+	List() ([]dto.APIKey, error)
+	// Revoke revokes the key with the given prefix.
+	Revoke(prefix string) error
+	TouchLastUsed(id int64, at time.Time) error
+}
+
+// PrivacyRepository keeps the audit log of data subject requests and finds or
+// erases everything stored about an address. Addresses are matched on their
+// normalized form as well as on the stored email.
+type PrivacyRepository interface {
+	// CreateRequest stores request and sets its ID.
+	CreateRequest(request *dto.PrivacyRequest) error
+	FindRequest(id int64) (*dto.PrivacyRequest, error)
+	// HasData reports whether anything is stored about the address.
+	HasData(email, normalizedEmail string) (bool, error)
+	// Access collects what is stored about the address and completes request.
+	Access(request *dto.PrivacyRequest, email, normalizedEmail string) (*dto.SubjectData, error)
+	// Erase deletes the address's subscriptions, emails and consent events,
+	// anonymizes its campaign deliveries and completes request, in one
+	// transaction.
+	Erase(request *dto.PrivacyRequest, email, normalizedEmail string) (*dto.ErasureReport, error)
+	// ListRequests returns the audit log newest first, optionally only the
+	// requests about one email hash.
+	ListRequests(emailHash string) ([]dto.PrivacyRequest, error)
+}
+
+// ConsentRepository is the append-only ledger of subscribe, confirm and
+// unsubscribe events.
+type ConsentRepository interface {
+	// Record stores events in one transaction and sets their IDs.
+	Record(events ...*dto.ConsentEvent) error
+	// ListEvents returns the events about the address oldest first, matching
+	// its normalized form as well as the stored email.
+	ListEvents(email, normalizedEmail string) ([]dto.ConsentEvent, error)
+}
+
+// TopicRepository stores the topics subscribers can choose and the
+// preferences they change from the preference page. Topics are shared by
+// every list; subscribers are found by list and address like Unsubscribe
+// finds them.
+type TopicRepository interface {
+	// CreateTopic stores topic and sets its ID.
+	CreateTopic(topic *dto.Topic) error
+	ListTopics() ([]dto.Topic, error)
+	// DeleteTopic deletes the topic with slug and removes it from every
+	// subscriber.
+	DeleteTopic(slug string) error
+	Preferences(listID, email string) (*dto.Preferences, error)
+	// UpdatePreferences applies update in one transaction. It returns
+	// ErrTopicNotFound, wrapped with the slug, for an unknown topic.
+	UpdatePreferences(listID, email string, update dto.PreferencesUpdate) (*dto.Preferences, error)
 }
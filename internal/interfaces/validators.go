@@ -5,5 +5,7 @@ import (
 )
 
 type MailingListValidator interface {
-	Validate(mailingList *dto.MailingList) error
+	// Validate returns an error for every invalid field, or nil when the
+	// entry is valid.
+	Validate(mailingList *dto.MailingList) []dto.FieldError
 }
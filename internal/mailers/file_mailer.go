@@ -0,0 +1,43 @@
+package mailers
+
+import (
+	"backend-go/internal/dto"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+// FileMailer writes every email as an .eml file into a directory, which is
+// handy for local development: the files open in any mail client.
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+func (m *FileMailer) Send(email *dto.Email) error {
+	message, err := BuildMessage(email)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml",
+		time.Now().UTC().Format("20060102T150405.000000000"),
+		unsafeFilenameChars.ReplaceAllString(email.To, "_"))
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), message, 0600); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package mailers
+
+import (
+	"backend-go/internal/dto"
+	"log"
+)
+
+// LogMailer writes outgoing emails to the application log instead of
+// delivering them. It is the default when no transport is configured.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(email *dto.Email) error {
+	log.Printf("Email to %s: %s\n%s", email.To, email.Subject, email.TextBody)
+	return nil
+}
@@ -0,0 +1,42 @@
+package mailers
+
+import (
+	"backend-go/internal/config"
+	"backend-go/internal/interfaces"
+	"errors"
+	"fmt"
+)
+
+// NewMailer returns the transport selected by cfg.MailTransport.
+func NewMailer(cfg *config.Config) (interfaces.Mailer, error) {
+	switch cfg.MailTransport {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		return NewFileMailer(cfg.MailDir), nil
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, errors.New("SMTP_HOST is required for the smtp mail transport")
+		}
+		switch cfg.SMTPSecurity {
+		case SecurityStartTLS, SecurityTLS, SecurityNone:
+		default:
+			return nil, fmt.Errorf("unknown SMTP_SECURITY %q", cfg.SMTPSecurity)
+		}
+		switch cfg.SMTPAuth {
+		case AuthPlain, AuthLogin, AuthNone:
+		default:
+			return nil, fmt.Errorf("unknown SMTP_AUTH %q", cfg.SMTPAuth)
+		}
+		return NewSMTPMailer(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			Security: cfg.SMTPSecurity,
+			Auth:     cfg.SMTPAuth,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q", cfg.MailTransport)
+	}
+}
@@ -0,0 +1,41 @@
+package mailers
+
+import (
+	"backend-go/internal/dto"
+	"sync"
+)
+
+// MemoryMailer keeps sent emails in memory so tests can inspect them.
+type MemoryMailer struct {
+	sent []dto.Email
+	mu   sync.Mutex
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(email *dto.Email) error {
+	if _, err := BuildMessage(email); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, *email)
+	return nil
+}
+
+// Sent returns a copy of the emails sent so far, oldest first.
+func (m *MemoryMailer) Sent() []dto.Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]dto.Email(nil), m.sent...)
+}
+
+// Reset forgets all sent emails.
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
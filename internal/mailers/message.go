@@ -0,0 +1,141 @@
+package mailers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage wraps errors caused by the email itself, such as a
+// malformed address. Retrying such an email cannot succeed.
+var ErrInvalidMessage = errors.New("invalid message")
+
+// BuildMessage encodes email as an RFC 5322 message. Emails with both a text
+// and an HTML body become multipart/alternative with the text part first.
+func BuildMessage(email *dto.Email) ([]byte, error) {
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from address %q: %v", ErrInvalidMessage, email.From, err)
+	}
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to address %q: %v", ErrInvalidMessage, email.To, err)
+	}
+	// Servers without SMTPUTF8 only accept internationalized domains in
+	// ASCII form
+	to.Address = normalize.Email(to.Address)
+
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", email.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+	}
+
+	keys := make([]string, 0, len(email.Headers))
+	for key := range email.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		headers = append(headers, [2]string{textproto.CanonicalMIMEHeaderKey(key), email.Headers[key]})
+	}
+
+	for _, header := range headers {
+		if strings.ContainsAny(header[0]+header[1], "\r\n") {
+			return nil, fmt.Errorf("%w: header %s contains a line break", ErrInvalidMessage, header[0])
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+
+	switch {
+	case email.TextBody != "" && email.HTMLBody != "":
+		err = writeAlternative(&buf, email.TextBody, email.HTMLBody)
+	case email.HTMLBody != "":
+		err = writeSinglePart(&buf, "text/html", email.HTMLBody)
+	default:
+		err = writeSinglePart(&buf, "text/plain", email.TextBody)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeSinglePart(buf *bytes.Buffer, contentType, body string) error {
+	fmt.Fprintf(buf, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	return writeQuotedPrintable(buf, body)
+}
+
+func writeAlternative(buf *bytes.Buffer, textBody, htmlBody string) error {
+	writer := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+
+	parts := [][2]string{
+		{"text/plain", textBody},
+		{"text/html", htmlBody},
+	}
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part[0] + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+
+		qp := quotedprintable.NewWriter(partWriter)
+		if _, err := qp.Write([]byte(part[1])); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	qp := quotedprintable.NewWriter(buf)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func newMessageID(fromAddress string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">", nil
+}
@@ -0,0 +1,234 @@
+package mailers
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// Connection security modes for SMTPMailer.
+const (
+	SecurityStartTLS = "starttls"
+	SecurityTLS      = "tls"
+	SecurityNone     = "none"
+)
+
+// Authentication mechanisms for SMTPMailer.
+const (
+	AuthPlain = "plain"
+	AuthLogin = "login"
+	AuthNone  = "none"
+)
+
+type SMTPConfig struct {
+	// TLSConfig overrides the TLS settings, mainly for tests. ServerName
+	// defaults to Host.
+	TLSConfig *tls.Config
+	Host      string
+	Port      string
+	Username  string
+	Password  string
+	// Security is one of SecurityStartTLS, SecurityTLS (implicit TLS,
+	// usually port 465) or SecurityNone.
+	Security string
+	// Auth is one of AuthPlain, AuthLogin or AuthNone.
+	Auth    string
+	Timeout time.Duration
+}
+
+// SMTPMailer delivers emails through an SMTP relay, opening one connection
+// per message.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.Security == "" {
+		config.Security = SecurityStartTLS
+	}
+	if config.Auth == "" {
+		config.Auth = AuthPlain
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(email *dto.Email) error {
+	message, err := BuildMessage(email)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return fmt.Errorf("%w: from address %q: %v", ErrInvalidMessage, email.From, err)
+	}
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return fmt.Errorf("%w: to address %q: %v", ErrInvalidMessage, email.To, err)
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	quit := false
+	defer func() {
+		if quit {
+			return
+		}
+		if closeErr := client.Close(); closeErr != nil {
+			log.Printf("Error closing SMTP connection: %v", closeErr)
+		}
+	}()
+
+	if m.config.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(m.tlsConfig()); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if auth := m.auth(); auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(normalize.Email(to.Address)); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	// The message has been accepted at this point, so a failed QUIT is not
+	// reported as a delivery failure
+	quit = true
+	if err := client.Quit(); err != nil {
+		log.Printf("Error closing SMTP session: %v", err)
+	}
+
+	return nil
+}
+
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if m.config.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, m.tlsConfig())
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial %s: %w", addr, err)
+	}
+
+	// Bound the whole SMTP conversation, not just the dial
+	if err := conn.SetDeadline(time.Now().Add(m.config.Timeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+
+	return client, nil
+}
+
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	if m.config.TLSConfig != nil {
+		config := m.config.TLSConfig.Clone()
+		if config.ServerName == "" {
+			config.ServerName = m.config.Host
+		}
+		return config
+	}
+	return &tls.Config{
+		ServerName: m.config.Host,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+func (m *SMTPMailer) auth() smtp.Auth {
+	if m.config.Username == "" {
+		return nil
+	}
+
+	switch m.config.Auth {
+	case AuthLogin:
+		return &loginAuth{username: m.config.Username, password: m.config.Password, host: m.config.Host}
+	case AuthNone:
+		return nil
+	default:
+		return smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+}
+
+// loginAuth implements the non-standard but widely deployed LOGIN mechanism.
+// Like smtp.PlainAuth it refuses to send credentials over an unencrypted
+// connection unless the server is on localhost.
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch string(fromServer) {
+	case "Username:", "username:":
+		return []byte(a.username), nil
+	case "Password:", "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
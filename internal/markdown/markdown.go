@@ -0,0 +1,183 @@
+// Package markdown renders the subset of Markdown used in newsletters:
+// ATX headings, paragraphs, ordered and unordered lists, blockquotes, fenced
+// code blocks, horizontal rules, emphasis, inline code, links and images.
+// Raw HTML in the source is escaped.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedRe   = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedRe     = regexp.MustCompile(`^\s{0,3}\d+[.)]\s+(.*)$`)
+	ruleRe        = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	fenceRe       = regexp.MustCompile("^\\s{0,3}(```|~~~)\\s*([\\w+-]*)")
+	imageRe       = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	linkRe        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	autolinkRe    = regexp.MustCompile(`&lt;(https?://[^\s&]+)&gt;`)
+	strongRe      = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	emphasisRe    = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:.*?\S)?)[*_]([^\w*]|$)`)
+	codeSpanRe    = regexp.MustCompile("`([^`]+)`")
+	placeholderRe = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// ToHTML renders Markdown source as an HTML fragment.
+func ToHTML(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	var out strings.Builder
+	renderBlocks(&out, lines)
+	return out.String()
+}
+
+func renderBlocks(out *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case fenceRe.MatchString(line):
+			match := fenceRe.FindStringSubmatch(line)
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), match[1]) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			if match[2] != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(match[2]) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			out.WriteString("</code></pre>\n")
+
+		case headingRe.MatchString(trimmed):
+			match := headingRe.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(match[1])))
+			out.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">\n")
+			i++
+
+		case ruleRe.MatchString(line):
+			out.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				text := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(text, " "))
+				i++
+			}
+			out.WriteString("<blockquote>\n")
+			renderBlocks(out, quoted)
+			out.WriteString("</blockquote>\n")
+
+		case unorderedRe.MatchString(line):
+			i = renderList(out, lines, i, unorderedRe, "ul")
+
+		case orderedRe.MatchString(line):
+			i = renderList(out, lines, i, orderedRe, "ol")
+
+		default:
+			var paragraph []string
+			for i < len(lines) && isParagraphLine(lines[i]) {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+				i++
+			}
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+		}
+	}
+}
+
+func isParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" &&
+		!headingRe.MatchString(trimmed) &&
+		!fenceRe.MatchString(line) &&
+		!ruleRe.MatchString(line) &&
+		!strings.HasPrefix(trimmed, ">") &&
+		!unorderedRe.MatchString(line) &&
+		!orderedRe.MatchString(line)
+}
+
+// renderList renders consecutive list items starting at lines[start] and
+// returns the index of the first line after the list. Indented lines
+// continue the previous item.
+func renderList(out *strings.Builder, lines []string, start int, itemRe *regexp.Regexp, tag string) int {
+	var items []string
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if match := itemRe.FindStringSubmatch(line); match != nil {
+			items = append(items, match[1])
+		} else if len(items) > 0 && strings.TrimSpace(line) != "" && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			items[len(items)-1] += "\n" + strings.TrimSpace(line)
+		} else {
+			break
+		}
+		i++
+	}
+
+	out.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		out.WriteString("<li>" + renderInline(item) + "</li>\n")
+	}
+	out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderInline escapes text and applies inline formatting. Patterns match
+// the escaped text, so quotes appear as &#34;. Code spans are
+// swapped for placeholders first so their content is left untouched.
+func renderInline(text string) string {
+	var codeSpans []string
+	text = codeSpanRe.ReplaceAllStringFunc(text, func(span string) string {
+		codeSpans = append(codeSpans, "<code>"+html.EscapeString(codeSpanRe.FindStringSubmatch(span)[1])+"</code>")
+		return "\x00" + strconv.Itoa(len(codeSpans)-1) + "\x00"
+	})
+
+	text = html.EscapeString(text)
+	text = imageRe.ReplaceAllStringFunc(text, func(match string) string {
+		parts := imageRe.FindStringSubmatch(match)
+		return `<img src="` + safeURL(parts[2]) + `" alt="` + parts[1] + `"` + titleAttr(parts[3]) + `>`
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkRe.FindStringSubmatch(match)
+		return `<a href="` + safeURL(parts[2]) + `"` + titleAttr(parts[3]) + `>` + parts[1] + `</a>`
+	})
+	text = autolinkRe.ReplaceAllString(text, `<a href="$1">$1</a>`)
+	text = strongRe.ReplaceAllString(text, "<strong>$2</strong>")
+	text = emphasisRe.ReplaceAllString(text, "$1<em>$2</em>$3")
+	text = strings.ReplaceAll(text, "  \n", "<br>\n")
+
+	return placeholderRe.ReplaceAllStringFunc(text, func(match string) string {
+		index, _ := strconv.Atoi(placeholderRe.FindStringSubmatch(match)[1])
+		return codeSpans[index]
+	})
+}
+
+// safeURL drops javascript: and similar schemes. The URL is already HTML
+// escaped.
+func safeURL(rawURL string) string {
+	lower := strings.ToLower(strings.TrimSpace(rawURL))
+	if strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "vbscript:") || strings.HasPrefix(lower, "data:") {
+		return "#"
+	}
+	return rawURL
+}
+
+func titleAttr(title string) string {
+	if title == "" {
+		return ""
+	}
+	return ` title="` + title + `"`
+}
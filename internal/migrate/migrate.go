@@ -0,0 +1,289 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownMarker separates a migration file's up statements from the statements
+// that roll it back.
+const DownMarker = "-- migrate:down"
+
+// ErrDatabaseAhead is returned when the database has a migration applied
+// that this binary doesn't know about, usually because a newer release ran
+// against it.
+var ErrDatabaseAhead = errors.New("database schema is newer than this binary")
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// Migration is one numbered file from the migrations directory.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration together with when it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Runner applies migrations to a database and records each applied version in
+// the schema_migrations table.
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewRunner loads the NNN_name.sql files in the root of source. Everything
+// after a DownMarker line is the migration's down section.
+func NewRunner(db *sql.DB, source fs.FS) (*Runner, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s, expected NNN_name.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		contents, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		up, down, _ := strings.Cut(string(contents), DownMarker+"\n")
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    match[2],
+			Up:      strings.TrimSpace(up),
+			Down:    strings.TrimSpace(down),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Migrations returns every known migration in version order.
+func (r *Runner) Migrations() []Migration {
+	return r.migrations
+}
+
+// Latest is the highest known version, or 0 when there are no migrations.
+func (r *Runner) Latest() int {
+	if len(r.migrations) == 0 {
+		return 0
+	}
+	return r.migrations[len(r.migrations)-1].Version
+}
+
+func (r *Runner) ensureTable() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// Initialized reports whether the database has a schema_migrations table.
+func (r *Runner) Initialized() (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect database: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *Runner) applied() (map[int]time.Time, error) {
+	if err := r.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var (
+			version   int
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+
+	for version := range applied {
+		if version > r.Latest() {
+			return nil, fmt.Errorf("%w: version %d is applied, this binary knows up to %d", ErrDatabaseAhead, version, r.Latest())
+		}
+	}
+
+	return applied, nil
+}
+
+// Status lists every known migration with the time it was applied.
+func (r *Runner) Status() ([]Status, error) {
+	applied, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, migration := range r.migrations {
+		status := Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in a single transaction and returns the
+// ones it applied. It returns ErrDatabaseAhead without changing anything when
+// the database has a version this binary doesn't know.
+func (r *Runner) Up() ([]Migration, error) {
+	applied, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range r.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	for _, migration := range pending {
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return nil, fmt.Errorf("failed to apply migration %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, migration.Version, migration.Name, now); err != nil {
+			return nil, fmt.Errorf("failed to record migration %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return pending, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, in a
+// single transaction and returns the ones it rolled back.
+func (r *Runner) Down(steps int) ([]Migration, error) {
+	applied, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var rollback []Migration
+	for i := len(r.migrations) - 1; i >= 0 && len(rollback) < steps; i-- {
+		if _, ok := applied[r.migrations[i].Version]; ok {
+			rollback = append(rollback, r.migrations[i])
+		}
+	}
+	if len(rollback) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, migration := range rollback {
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s cannot be rolled back", migration.Version, migration.Name)
+		}
+		if _, err := tx.Exec(migration.Down); err != nil {
+			return nil, fmt.Errorf("failed to roll back migration %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version); err != nil {
+			return nil, fmt.Errorf("failed to record rollback of %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rollback: %w", err)
+	}
+	return rollback, nil
+}
+
+// MarkApplied records versions as applied without running them, for
+// databases whose schema was created before migrations were tracked.
+func (r *Runner) MarkApplied(versions ...int) error {
+	if err := r.ensureTable(); err != nil {
+		return err
+	}
+
+	names := map[int]string{}
+	for _, migration := range r.migrations {
+		names[migration.Version] = migration.Name
+	}
+
+	now := time.Now().UTC()
+	for _, version := range versions {
+		name, ok := names[version]
+		if !ok {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+		if _, err := r.db.Exec(`INSERT OR IGNORE INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, version, name, now); err != nil {
+			return fmt.Errorf("failed to record migration %03d_%s: %w", version, name, err)
+		}
+	}
+	return nil
+}
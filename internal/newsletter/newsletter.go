@@ -0,0 +1,96 @@
+package newsletter
+
+import (
+	"backend-go/internal/markdown"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Source formats accepted by Parse.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var titleTagRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Newsletter is a rendered issue ready to be sent.
+type Newsletter struct {
+	Title string
+	HTML  string
+	Text  string
+}
+
+// Load reads a newsletter file, picking the format from its extension.
+func Load(path string) (*Newsletter, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read newsletter: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return Parse(string(source), FormatMarkdown)
+	case ".html", ".htm":
+		return Parse(string(source), FormatHTML)
+	default:
+		return nil, fmt.Errorf("unsupported newsletter format %q, expected .md or .html", filepath.Ext(path))
+	}
+}
+
+// Parse renders source into HTML and plain text. Markdown may start with a
+// Hugo-style front matter block whose title is used as the subject.
+func Parse(source, format string) (*Newsletter, error) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	switch format {
+	case FormatMarkdown:
+		frontMatter, body := splitFrontMatter(source)
+		return &Newsletter{
+			Title: frontMatter["title"],
+			HTML:  markdown.ToHTML(body),
+			Text:  strings.TrimSpace(body) + "\n",
+		}, nil
+	case FormatHTML:
+		newsletter := &Newsletter{
+			HTML: source,
+			Text: HTMLToText(source),
+		}
+		if match := titleTagRe.FindStringSubmatch(source); match != nil {
+			newsletter.Title = strings.TrimSpace(match[1])
+		}
+		return newsletter, nil
+	default:
+		return nil, fmt.Errorf("unsupported newsletter format %q", format)
+	}
+}
+
+// splitFrontMatter separates a leading "---" delimited block of "key: value"
+// lines from the document body.
+func splitFrontMatter(source string) (map[string]string, string) {
+	values := map[string]string{}
+	if !strings.HasPrefix(source, "---\n") {
+		return values, source
+	}
+
+	end := strings.Index(source[4:], "\n---")
+	if end < 0 {
+		return values, source
+	}
+
+	for _, line := range strings.Split(source[4:4+end], "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		value = strings.Trim(value, `"'`)
+		values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	body := source[4+end+len("\n---"):]
+	return values, strings.TrimPrefix(body, "\n")
+}
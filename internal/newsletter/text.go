@@ -0,0 +1,46 @@
+package newsletter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	invisibleRe  = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	anchorRe     = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']+)["'][^>]*>(.*?)</a>`)
+	listItemRe   = regexp.MustCompile(`(?i)<li[^>]*>`)
+	lineBreakRe  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	blockEndRe   = regexp.MustCompile(`(?i)</(p|div|h[1-6]|ul|ol|blockquote|pre|tr|table)>|<hr\s*/?>`)
+	tagRe        = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacesRe     = regexp.MustCompile(`[ \t]+`)
+	blankLinesRe = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText produces a readable plain-text alternative for an HTML email.
+// Links are kept as "text (url)".
+func HTMLToText(source string) string {
+	text := invisibleRe.ReplaceAllString(source, "")
+	text = anchorRe.ReplaceAllStringFunc(text, func(match string) string {
+		parts := anchorRe.FindStringSubmatch(match)
+		label := strings.TrimSpace(tagRe.ReplaceAllString(parts[2], ""))
+		if label == "" || label == parts[1] {
+			return parts[1]
+		}
+		return label + " (" + parts[1] + ")"
+	})
+	text = listItemRe.ReplaceAllString(text, "\n- ")
+	text = lineBreakRe.ReplaceAllString(text, "\n")
+	text = blockEndRe.ReplaceAllString(text, "\n\n")
+	text = tagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = spacesRe.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = blankLinesRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+
+	return strings.TrimSpace(text) + "\n"
+}
@@ -0,0 +1,84 @@
+package normalize
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// providerRule describes how a mailbox provider treats variations of an
+// address: ignoring dots in the local part, delivering "user+tag" to "user"
+// and accepting mail on several domains.
+type providerRule struct {
+	domain     string
+	ignoreDots bool
+	plusTags   bool
+}
+
+var providerRules = map[string]providerRule{
+	"gmail.com":      {domain: "gmail.com", ignoreDots: true, plusTags: true},
+	"googlemail.com": {domain: "gmail.com", ignoreDots: true, plusTags: true},
+	"outlook.com":    {domain: "outlook.com", plusTags: true},
+	"hotmail.com":    {domain: "hotmail.com", plusTags: true},
+	"live.com":       {domain: "live.com", plusTags: true},
+	"icloud.com":     {domain: "icloud.com", plusTags: true},
+	"fastmail.com":   {domain: "fastmail.com", plusTags: true},
+	"proton.me":      {domain: "proton.me", plusTags: true},
+	"protonmail.com": {domain: "protonmail.com", plusTags: true},
+}
+
+// Normalizer derives the key that identifies the mailbox behind an email
+// address, so the same subscriber can't sign up twice with different
+// spellings of their address.
+type Normalizer struct {
+	// FoldAliases applies provider-specific rules, such as Gmail ignoring
+	// dots and "+tag" suffixes, so "j.doe+news@gmail.com" and
+	// "jdoe@gmail.com" get the same key.
+	FoldAliases bool
+}
+
+// Email trims surrounding whitespace, lowercases the domain, which is
+// case-insensitive, and converts an internationalized domain to its ASCII
+// "xn--" form, which mail servers without SMTPUTF8 accept. The local part is
+// kept as typed; this is the form that is stored and mailed to. Domains
+// that can't be converted are left for the validator to reject.
+func Email(email string) string {
+	email = strings.TrimSpace(email)
+
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return email
+	}
+	domain := strings.ToLower(email[at+1:])
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	return email[:at+1] + domain
+}
+
+// Key returns the uniqueness key of email: the normalized address in lower
+// case, with provider rules applied when FoldAliases is set.
+func (n Normalizer) Key(email string) string {
+	email = strings.ToLower(Email(email))
+
+	at := strings.LastIndexByte(email, '@')
+	if !n.FoldAliases || at < 0 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	rule, ok := providerRules[domain]
+	if !ok {
+		return email
+	}
+
+	if rule.plusTags {
+		if i := strings.IndexByte(local, '+'); i > 0 {
+			local = local[:i]
+		}
+	}
+	if rule.ignoreDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + rule.domain
+}
@@ -0,0 +1,199 @@
+package queue
+
+import (
+	"backend-go/internal/interfaces"
+	"backend-go/internal/mailers"
+	"context"
+	"errors"
+	"log"
+	"net/textproto"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	Workers      int
+	MaxAttempts  int
+	PollInterval time.Duration
+	// BaseBackoff is the delay before the first retry; it doubles with every
+	// further attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Retention is how long sent jobs are kept before they are deleted.
+	Retention time.Duration
+}
+
+// purgeInterval is how often sent jobs older than the retention are deleted.
+const purgeInterval = time.Hour
+
+// DefaultOptions retries for roughly a day before giving up on a message.
+func DefaultOptions() Options {
+	return Options{
+		Workers:      2,
+		MaxAttempts:  10,
+		PollInterval: 2 * time.Second,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   4 * time.Hour,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// Dispatcher runs worker goroutines that deliver queued emails through a
+// transport, retrying transient failures with exponential backoff and moving
+// permanent failures to the dead state.
+type Dispatcher struct {
+	repo      interfaces.MailQueueRepository
+	transport interfaces.Mailer
+	options   Options
+}
+
+func NewDispatcher(repo interfaces.MailQueueRepository, transport interfaces.Mailer, options Options) *Dispatcher {
+	defaults := DefaultOptions()
+	if options.Workers <= 0 {
+		options.Workers = defaults.Workers
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = defaults.BaseBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	if options.Retention <= 0 {
+		options.Retention = defaults.Retention
+	}
+
+	return &Dispatcher{
+		repo:      repo,
+		transport: transport,
+		options:   options,
+	}
+}
+
+// Run starts the workers and the purge of old sent jobs, and blocks until
+// ctx is cancelled and every worker has finished its current job.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.purge(ctx)
+	}()
+	wg.Wait()
+}
+
+func (d *Dispatcher) purge(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := d.Purge(time.Now()); err != nil {
+			log.Printf("Mail queue error: %v", err)
+		} else if purged > 0 {
+			log.Printf("Deleted %d sent emails older than %s", purged, d.options.Retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the jobs sent longer than the retention before now.
+func (d *Dispatcher) Purge(now time.Time) (int64, error) {
+	return d.repo.PurgeSent(now.Add(-d.options.Retention))
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	ticker := time.NewTicker(d.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain every due job before waiting for the next tick
+		for ctx.Err() == nil {
+			processed, err := d.ProcessNext()
+			if err != nil {
+				log.Printf("Mail queue error: %v", err)
+				break
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext delivers a single due job. It reports whether a job was found.
+func (d *Dispatcher) ProcessNext() (bool, error) {
+	job, err := d.repo.Claim(time.Now())
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	sendErr := d.transport.Send(&job.Email)
+	switch {
+	case sendErr == nil:
+		return true, d.repo.MarkSent(job.ID)
+	case IsPermanent(sendErr) || job.Attempts >= d.options.MaxAttempts:
+		log.Printf("Mail job %d to %s failed permanently after %d attempts: %v", job.ID, job.Email.To, job.Attempts, sendErr)
+		return true, d.repo.MarkDead(job.ID, sendErr)
+	default:
+		next := time.Now().Add(d.Backoff(job.Attempts))
+		log.Printf("Mail job %d to %s failed, retrying at %s: %v", job.ID, job.Email.To, next.Format(time.RFC3339), sendErr)
+		return true, d.repo.Retry(job.ID, next, sendErr)
+	}
+}
+
+// Backoff returns the delay before retrying a job that has failed attempts
+// times.
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	delay := d.options.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.options.MaxBackoff {
+			return d.options.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// IsPermanent reports whether retrying err cannot succeed: the message is
+// malformed or the SMTP server rejected it with a 5xx reply. Authentication
+// failures are 5xx as well but are a configuration problem, so they are
+// retried until the credentials are fixed.
+func IsPermanent(err error) bool {
+	if errors.Is(err, mailers.ErrInvalidMessage) {
+		return true
+	}
+
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		switch smtpErr.Code {
+		case 530, 534, 535, 538:
+			return false
+		}
+		return smtpErr.Code >= 500
+	}
+
+	return false
+}
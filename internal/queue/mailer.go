@@ -0,0 +1,26 @@
+package queue
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/mailers"
+)
+
+// Mailer implements interfaces.Mailer by adding emails to the persistent
+// queue. A Dispatcher delivers them in the background.
+type Mailer struct {
+	repo interfaces.MailQueueRepository
+}
+
+func NewMailer(repo interfaces.MailQueueRepository) *Mailer {
+	return &Mailer{repo: repo}
+}
+
+// Send validates and enqueues email so callers still get an immediate error
+// for messages that could never be delivered.
+func (m *Mailer) Send(email *dto.Email) error {
+	if _, err := mailers.BuildMessage(email); err != nil {
+		return err
+	}
+	return m.repo.Enqueue(email)
+}
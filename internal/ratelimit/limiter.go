@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a set of token buckets, one per key. Each bucket holds up to
+// limit tokens and refills at limit tokens per period, so a key can burst
+// limit requests and then sustain limit requests per period.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	limit     float64
+	per       time.Duration
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(limit int, per time.Duration) *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		limit:   float64(limit),
+		per:     per,
+	}
+}
+
+// Allow takes a token from the bucket for key. When the bucket is empty it
+// returns false and how long until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	return l.AllowAt(key, time.Now())
+}
+
+// AllowAt is Allow at the given time.
+func (l *Limiter) AllowAt(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.limit, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration(math.Ceil((1 - b.tokens) / l.limit * float64(l.per)))
+	return false, wait
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(l.limit, b.tokens+l.limit*float64(elapsed)/float64(l.per))
+}
+
+// sweep drops full buckets at most once per period so keys that stopped
+// sending requests don't accumulate forever. A full bucket behaves exactly
+// like a missing one.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.per {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.limit {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of tracked keys.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -2,12 +2,17 @@ package repositories
 
 import (
 	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
 	"encoding/csv"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
+// CsvMailingListRepository keeps the subscribers of the default list in a
+// CSV file. Other lists return interfaces.ErrListNotFound.
 type CsvMailingListRepository struct {
 	filepath string
 }
@@ -18,7 +23,20 @@ func NewCsvMailingListRepository(filepath string) *CsvMailingListRepository {
 	}
 }
 
-func (r *CsvMailingListRepository) Save(mailingList *dto.MailingList) error {
+// Save appends a subscriber to the file unless the address is already in
+// it. The CSV format has no pending state, so every row is active: Status is
+// set to active and ConfirmationToken cleared, which tells callers there is
+// no confirmation email to send.
+func (r *CsvMailingListRepository) Save(listID string, mailingList *dto.MailingList) error {
+	if err := requireDefaultList(listID); err != nil {
+		return err
+	}
+
+	mailingList.ListID = dto.DefaultListID
+	mailingList.Status = dto.StatusActive
+	mailingList.ConfirmationToken = ""
+	mailingList.ConfirmationExpiresAt = time.Time{}
+
 	exists, err := r.emailExists(mailingList.Email)
 	if err != nil {
 		return err
@@ -95,10 +113,183 @@ func (r *CsvMailingListRepository) emailExists(email string) (bool, error) {
 		if i == 0 {
 			continue
 		}
-		if record[1] == email {
+		if strings.EqualFold(record[1], email) {
 			return true, nil
 		}
 	}
 
 	return false, nil
 }
+
+// Confirm always fails: the CSV format has no pending state and every saved
+// row is already active.
+func (r *CsvMailingListRepository) Confirm(listID, token string) (*dto.MailingList, error) {
+	if err := requireDefaultList(listID); err != nil {
+		return nil, err
+	}
+	return nil, interfaces.ErrInvalidToken
+}
+
+// Unsubscribe removes the row for email from the CSV file, since the format
+// has no column to record the subscription state.
+func (r *CsvMailingListRepository) Unsubscribe(listID, email string) (*dto.MailingList, error) {
+	if err := requireDefaultList(listID); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(r.filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, interfaces.ErrSubscriberNotFound
+		}
+		return nil, err
+	}
+
+	records, err := csv.NewReader(file).ReadAll()
+	if closeErr := file.Close(); closeErr != nil {
+		log.Printf("Error closing file: %v", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var removed *dto.MailingList
+	kept := make([][]string, 0, len(records))
+	for i, record := range records {
+		if i > 0 && removed == nil && strings.EqualFold(record[1], email) {
+			createdAt, _ := time.Parse(time.RFC3339, record[2])
+			now := time.Now()
+			removed = &dto.MailingList{
+				Username:       record[0],
+				Email:          record[1],
+				CreatedAt:      createdAt,
+				Status:         dto.StatusUnsubscribed,
+				UnsubscribedAt: &now,
+			}
+			continue
+		}
+		kept = append(kept, record)
+	}
+	if removed == nil {
+		return nil, interfaces.ErrSubscriberNotFound
+	}
+
+	// Write to a temporary file first so a failure cannot truncate the list
+	tmpPath := r.filepath + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(tmp)
+	if err := writer.WriteAll(kept); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, r.filepath); err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// ListActive returns every row in the file; CSV subscribers are always active.
+func (r *CsvMailingListRepository) ListActive(listID string) ([]dto.MailingList, error) {
+	if err := requireDefaultList(listID); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(r.filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Printf("Error closing file: %v", closeErr)
+		}
+	}()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribers []dto.MailingList
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		createdAt, _ := time.Parse(time.RFC3339, record[2])
+		subscribers = append(subscribers, dto.MailingList{
+			ListID:    dto.DefaultListID,
+			Username:  record[0],
+			Email:     record[1],
+			CreatedAt: createdAt,
+			Status:    dto.StatusActive,
+		})
+	}
+
+	return subscribers, nil
+}
+
+// List filters the file in memory. The row number stands in for the id in
+// pagination cursors.
+func (r *CsvMailingListRepository) List(listID string, query dto.SubscriberQuery) (*dto.SubscriberPage, error) {
+	subscribers, err := r.ListActive(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *subscriberCursor
+	if query.Cursor != "" {
+		decoded, err := decodeSubscriberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &decoded
+	}
+
+	search := strings.ToLower(query.Search)
+	page := &dto.SubscriberPage{Subscribers: []dto.MailingList{}}
+	var lastID int64
+	// Rows are appended in signup order, so walk the file backwards
+	for i := len(subscribers) - 1; i >= 0; i-- {
+		subscriber := subscribers[i]
+		position := subscriberCursor{createdAt: subscriber.CreatedAt, id: int64(i + 1)}
+
+		switch {
+		case query.Status != "" && subscriber.Status != query.Status,
+			!query.CreatedAfter.IsZero() && subscriber.CreatedAt.Before(query.CreatedAfter),
+			!query.CreatedBefore.IsZero() && !subscriber.CreatedAt.Before(query.CreatedBefore),
+			search != "" && !strings.Contains(strings.ToLower(subscriber.Email), search) && !strings.Contains(strings.ToLower(subscriber.Username), search),
+			cursor != nil && position.id >= cursor.id:
+			continue
+		}
+
+		if len(page.Subscribers) == query.Limit {
+			last := page.Subscribers[len(page.Subscribers)-1]
+			page.NextCursor = subscriberCursor{createdAt: last.CreatedAt, id: lastID}.encode()
+			break
+		}
+		page.Subscribers = append(page.Subscribers, subscriber)
+		lastID = position.id
+	}
+
+	return page, nil
+}
+
+func requireDefaultList(listID string) error {
+	if listID != dto.DefaultListID {
+		return fmt.Errorf("%w: %s", interfaces.ErrListNotFound, listID)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"backend-go/internal/interfaces"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// subscriberCursor marks the last row of a page. Rows are ordered by
+// created_at and then id, both descending, so the pair is unique.
+type subscriberCursor struct {
+	createdAt time.Time
+	id        int64
+}
+
+func (c subscriberCursor) encode() string {
+	raw := c.createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(c.id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSubscriberCursor(cursor string) (subscriberCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return subscriberCursor{}, interfaces.ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return subscriberCursor{}, interfaces.ErrInvalidCursor
+	}
+
+	var c subscriberCursor
+	if c.createdAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return subscriberCursor{}, interfaces.ErrInvalidCursor
+	}
+	if c.id, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return subscriberCursor{}, interfaces.ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"backend-go/internal/migrate"
+	"backend-go/migrations"
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSqlite opens the database at dbPath without touching its schema.
+func OpenSqlite(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer, and every connection to ":memory:" is a
+	// separate database, so keep the pool to one connection
+	db.SetMaxOpenConns(1)
+
+	// Enable foreign keys
+	if _, err := db.Exec("PRAGMA foreign_keys=ON"); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewMigrationRunner returns a runner for the embedded migrations. Databases
+// created before migrations were tracked are adopted first, so only the
+// migrations their schema is missing will run.
+func NewMigrationRunner(db *sql.DB) (*migrate.Runner, error) {
+	runner, err := migrate.NewRunner(db, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	if err := adoptLegacySchema(db, runner); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+// Migrate applies every pending migration. It fails with
+// migrate.ErrDatabaseAhead when the database was migrated by a newer binary.
+func Migrate(db *sql.DB) error {
+	runner, err := NewMigrationRunner(db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := runner.Up()
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		log.Printf("Applied %d migrations, database schema is at version %d", len(applied), runner.Latest())
+	}
+	return nil
+}
+
+// adoptLegacySchema marks the first migration as applied on databases
+// created by the baseline initSchema, which made the mailing_list table
+// without recording a schema version.
+func adoptLegacySchema(db *sql.DB, runner *migrate.Runner) error {
+	initialized, err := runner.Initialized()
+	if err != nil || initialized {
+		return err
+	}
+	if exists, err := tableExists(db, "mailing_list"); err != nil || !exists {
+		return err
+	}
+
+	log.Printf("Adopting existing database schema as version 1")
+	return runner.MarkApplied(1)
+}
+
+func tableExists(db *sql.DB, table string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	return count > 0, nil
+}
+
+// requireTable is used by repositories sharing a database opened by
+// NewSqliteMailingListRepository to fail early when it hasn't been migrated.
+func requireTable(db *sql.DB, table string) error {
+	exists, err := tableExists(db, table)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("table %s does not exist, run the database migrations", table)
+	}
+	return nil
+}
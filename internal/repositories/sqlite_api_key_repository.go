@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// SqliteAPIKeyRepository stores admin API keys in the api_keys table. Scopes
+// are kept as a space-separated list.
+type SqliteAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteAPIKeyRepository(db *sql.DB) (*SqliteAPIKeyRepository, error) {
+	repo := &SqliteAPIKeyRepository{db: db}
+
+	if err := requireTable(db, "api_keys"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SqliteAPIKeyRepository) Create(key *dto.APIKey, hash string) error {
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := r.db.Exec(`
+	INSERT INTO api_keys (name, prefix, key_hash, scopes, created_at)
+	VALUES (?, ?, ?, ?, ?)`, key.Name, key.Prefix, hash, strings.Join(key.Scopes, " "), key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	key.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteAPIKeyRepository) FindByHash(hash string) (*dto.APIKey, error) {
+	row := r.db.QueryRow(`
+	SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+	FROM api_keys
+	WHERE key_hash = ?`, hash)
+
+	key, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *SqliteAPIKeyRepository) List() ([]dto.APIKey, error) {
+	rows, err := r.db.Query(`
+	SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+	FROM api_keys
+	ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var keys []dto.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke is idempotent; revoking an already revoked key keeps the original
+// revocation time.
+func (r *SqliteAPIKeyRepository) Revoke(prefix string) error {
+	result, err := r.db.Exec(`
+	UPDATE api_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE prefix = ?`, time.Now().UTC(), prefix)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if affected == 0 {
+		return interfaces.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *SqliteAPIKeyRepository) TouchLastUsed(id int64, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, at.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*dto.APIKey, error) {
+	var (
+		key        dto.APIKey
+		scopes     string
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SqliteConsentRepository appends to the consent_events table. A trigger
+// rejects updates, so recorded events can only be deleted by an erasure.
+type SqliteConsentRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteConsentRepository(db *sql.DB) (*SqliteConsentRepository, error) {
+	repo := &SqliteConsentRepository{db: db}
+
+	if err := requireTable(db, "consent_events"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Record stores events, defaulting their time to now, their list to the
+// default list and their normalized email to the lowercased address.
+func (r *SqliteConsentRepository) Record(events ...*dto.ConsentEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	statement, err := tx.Prepare(`
+	INSERT INTO consent_events (list_id, email, normalized_email, event, source, ip_hash, user_agent, page_url, policy_version, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare consent event: %w", err)
+	}
+	defer func() {
+		if closeErr := statement.Close(); closeErr != nil {
+			log.Printf("Error closing statement: %v", closeErr)
+		}
+	}()
+
+	now := time.Now().UTC()
+	for _, event := range events {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = now
+		}
+		if event.ListID == "" {
+			event.ListID = dto.DefaultListID
+		}
+		if event.NormalizedEmail == "" {
+			event.NormalizedEmail = normalize.Normalizer{}.Key(event.Email)
+		}
+
+		result, err := statement.Exec(event.ListID, event.Email, event.NormalizedEmail, event.Event, event.Source,
+			nullString(event.IPHash), nullString(event.UserAgent), nullString(event.PageURL), nullString(event.PolicyVersion), event.CreatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to record consent event: %w", err)
+		}
+		if event.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to record consent event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *SqliteConsentRepository) ListEvents(email, normalizedEmail string) ([]dto.ConsentEvent, error) {
+	return listConsentEvents(r.db, email, normalizedEmail)
+}
+
+func listConsentEvents(db querier, email, normalizedEmail string) ([]dto.ConsentEvent, error) {
+	events := []dto.ConsentEvent{}
+	err := queryRows(db, func(rows *sql.Rows) error {
+		var (
+			event                                     dto.ConsentEvent
+			ipHash, userAgent, pageURL, policyVersion sql.NullString
+		)
+		if err := rows.Scan(&event.ID, &event.ListID, &event.Email, &event.NormalizedEmail, &event.Event, &event.Source, &ipHash, &userAgent, &pageURL, &policyVersion, &event.CreatedAt); err != nil {
+			return err
+		}
+		event.IPHash = ipHash.String
+		event.UserAgent = userAgent.String
+		event.PageURL = pageURL.String
+		event.PolicyVersion = policyVersion.String
+		events = append(events, event)
+		return nil
+	}, `
+	SELECT id, list_id, email, normalized_email, event, source, ip_hash, user_agent, page_url, policy_version, created_at
+	FROM consent_events
+	WHERE normalized_email = ? OR email = ?
+	ORDER BY created_at, id`, normalizedEmail, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consent events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SqliteDeliveryRepository stores per-recipient campaign delivery status in
+// the campaign_deliveries table. Campaigns are kept apart per list, so two
+// lists can send campaigns with the same ID.
+type SqliteDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteDeliveryRepository(db *sql.DB) (*SqliteDeliveryRepository, error) {
+	repo := &SqliteDeliveryRepository{db: db}
+
+	if err := requireTable(db, "campaign_deliveries"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SqliteDeliveryRepository) Statuses(listID, campaign string) (map[string]string, error) {
+	rows, err := r.db.Query(`SELECT email, status FROM campaign_deliveries WHERE list_id = ? AND campaign = ?`, listID, campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deliveries: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	statuses := map[string]string{}
+	for rows.Next() {
+		var email, status string
+		if err := rows.Scan(&email, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		statuses[email] = status
+	}
+
+	return statuses, rows.Err()
+}
+
+// MarkSending records that a message is about to be handed to the mailer.
+// A row left in this state means the process stopped mid-send and the
+// recipient may or may not have received the message.
+func (r *SqliteDeliveryRepository) MarkSending(listID, campaign, email string) error {
+	return r.upsert(listID, campaign, email, dto.DeliverySending, nil, sql.NullTime{})
+}
+
+func (r *SqliteDeliveryRepository) MarkSent(listID, campaign, email string) error {
+	now := time.Now().UTC()
+	return r.upsert(listID, campaign, email, dto.DeliverySent, nil, sql.NullTime{Time: now, Valid: true})
+}
+
+func (r *SqliteDeliveryRepository) MarkFailed(listID, campaign, email string, sendErr error) error {
+	message := sendErr.Error()
+	return r.upsert(listID, campaign, email, dto.DeliveryFailed, &message, sql.NullTime{})
+}
+
+func (r *SqliteDeliveryRepository) upsert(listID, campaign, email, status string, message *string, sentAt sql.NullTime) error {
+	query := `
+	INSERT INTO campaign_deliveries (list_id, campaign, email, status, error, updated_at, sent_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(list_id, campaign, email) DO UPDATE SET
+		status = excluded.status,
+		error = excluded.error,
+		updated_at = excluded.updated_at,
+		sent_at = excluded.sent_at`
+
+	_, err := r.db.Exec(query, listID, campaign, email, status, message, time.Now().UTC(), sentAt)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SqliteListRepository stores mailing lists in the lists table. Allowed
+// origins are kept as a comma-separated column.
+type SqliteListRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteListRepository(db *sql.DB) (*SqliteListRepository, error) {
+	repo := &SqliteListRepository{db: db}
+
+	if err := requireTable(db, "lists"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// CreateList returns interfaces.ErrListExists when the id is taken.
+func (r *SqliteListRepository) CreateList(list *dto.List) error {
+	if list.CreatedAt.IsZero() {
+		list.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := r.db.Exec(`
+	INSERT INTO lists (id, name, mail_from, allowed_origins, template_dir, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)`, list.ID, list.Name, nullString(list.MailFrom),
+		nullString(strings.Join(list.AllowedOrigins, ",")), nullString(list.TemplateDir), list.CreatedAt.UTC())
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return fmt.Errorf("%w: %s", interfaces.ErrListExists, list.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create list: %w", err)
+	}
+
+	return nil
+}
+
+// GetList returns interfaces.ErrListNotFound when there is no list with id.
+func (r *SqliteListRepository) GetList(id string) (*dto.List, error) {
+	list, err := scanList(r.db.QueryRow(listColumns+`
+	WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrListNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load list: %w", err)
+	}
+	return list, nil
+}
+
+func (r *SqliteListRepository) ListLists() ([]dto.List, error) {
+	lists := []dto.List{}
+	err := queryRows(r.db, func(rows *sql.Rows) error {
+		list, err := scanList(rows)
+		if err != nil {
+			return err
+		}
+		lists = append(lists, *list)
+		return nil
+	}, listColumns+`
+	ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lists: %w", err)
+	}
+	return lists, nil
+}
+
+func (r *SqliteListRepository) DeleteList(id string) error {
+	if id == dto.DefaultListID {
+		return fmt.Errorf("%w: the default list can't be deleted", interfaces.ErrListInUse)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := requireList(tx, id); err != nil {
+		return err
+	}
+
+	var subscribers int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM mailing_list WHERE list_id = ?`, id).Scan(&subscribers); err != nil {
+		return fmt.Errorf("failed to count subscribers: %w", err)
+	}
+	if subscribers > 0 {
+		return fmt.Errorf("%w: %s has %d subscribers", interfaces.ErrListInUse, id, subscribers)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM lists WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+const listColumns = `
+	SELECT id, name, mail_from, allowed_origins, template_dir, created_at
+	FROM lists`
+
+func scanList(row rowScanner) (*dto.List, error) {
+	var (
+		list                                  dto.List
+		mailFrom, allowedOrigins, templateDir sql.NullString
+	)
+	if err := row.Scan(&list.ID, &list.Name, &mailFrom, &allowedOrigins, &templateDir, &list.CreatedAt); err != nil {
+		return nil, err
+	}
+	list.MailFrom = mailFrom.String
+	list.TemplateDir = templateDir.String
+	if allowedOrigins.String != "" {
+		list.AllowedOrigins = strings.Split(allowedOrigins.String, ",")
+	}
+	return &list, nil
+}
+
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// requireList returns interfaces.ErrListNotFound when there is no list with
+// id.
+func requireList(db rowQuerier, id string) error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM lists WHERE id = ?`, id).Scan(&count); err != nil {
+		return fmt.Errorf("failed to load list: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrListNotFound, id)
+	}
+	return nil
+}
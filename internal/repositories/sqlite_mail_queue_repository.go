@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SqliteMailQueueRepository stores the outbound mail queue in the mail_queue
+// table. next_attempt_at is kept as Unix seconds so due jobs can be selected
+// with a plain integer comparison.
+type SqliteMailQueueRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteMailQueueRepository(db *sql.DB) (*SqliteMailQueueRepository, error) {
+	repo := &SqliteMailQueueRepository{db: db}
+
+	if err := requireTable(db, "mail_queue"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SqliteMailQueueRepository) Enqueue(email *dto.Email) error {
+	return enqueueMail(r.db, email)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, so other repositories can
+// enqueue emails in the same transaction as their own writes.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func enqueueMail(db execer, email *dto.Email) error {
+	payload, err := json.Marshal(email)
+	if err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	now := time.Now().UTC()
+	query := `
+	INSERT INTO mail_queue (recipient, payload, status, next_attempt_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = db.Exec(query, email.To, string(payload), dto.JobPending, now.Unix(), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteMailQueueRepository) Claim(now time.Time) (*dto.MailJob, error) {
+	query := `
+	UPDATE mail_queue
+	SET status = ?, attempts = attempts + 1, updated_at = ?
+	WHERE id = (
+		SELECT id FROM mail_queue
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT 1
+	)
+	RETURNING id, payload, attempts, created_at`
+
+	var (
+		job     dto.MailJob
+		payload string
+	)
+	err := r.db.QueryRow(query, dto.JobSending, now.UTC(), dto.JobPending, now.Unix()).
+		Scan(&job.ID, &payload, &job.Attempts, &job.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim mail job: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(payload), &job.Email); err != nil {
+		return nil, fmt.Errorf("failed to decode mail job %d: %w", job.ID, err)
+	}
+
+	return &job, nil
+}
+
+func (r *SqliteMailQueueRepository) MarkSent(id int64) error {
+	return r.update(id, dto.JobSent, nil, nil)
+}
+
+func (r *SqliteMailQueueRepository) Retry(id int64, nextAttempt time.Time, sendErr error) error {
+	next := nextAttempt.Unix()
+	return r.update(id, dto.JobPending, &next, sendErr)
+}
+
+func (r *SqliteMailQueueRepository) MarkDead(id int64, sendErr error) error {
+	return r.update(id, dto.JobDead, nil, sendErr)
+}
+
+func (r *SqliteMailQueueRepository) update(id int64, status string, nextAttempt *int64, sendErr error) error {
+	var lastError *string
+	if sendErr != nil {
+		message := sendErr.Error()
+		lastError = &message
+	}
+
+	query := `
+	UPDATE mail_queue
+	SET status = ?, next_attempt_at = COALESCE(?, next_attempt_at), last_error = COALESCE(?, last_error), updated_at = ?
+	WHERE id = ?`
+
+	_, err := r.db.Exec(query, status, nextAttempt, lastError, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update mail job %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// RequeueInterrupted returns jobs left in the sending state by a previous
+// process to the queue. Those emails may be delivered twice, which is
+// preferred over losing them.
+func (r *SqliteMailQueueRepository) RequeueInterrupted() (int64, error) {
+	result, err := r.db.Exec(`UPDATE mail_queue SET status = ?, updated_at = ? WHERE status = ?`,
+		dto.JobPending, time.Now().UTC(), dto.JobSending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue interrupted mail jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// PurgeSent deletes jobs that were sent before the given time, so rendered
+// messages with addresses and personal links are not kept forever. Dead jobs
+// are kept for inspection.
+func (r *SqliteMailQueueRepository) PurgeSent(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM mail_queue WHERE status = ? AND updated_at < ?`, dto.JobSent, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sent mail jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *SqliteMailQueueRepository) Depth() (map[string]int, error) {
+	rows, err := r.db.Query(`SELECT status, COUNT(*) FROM mail_queue GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count mail jobs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	depth := map[string]int{
+		dto.JobPending: 0,
+		dto.JobSending: 0,
+		dto.JobSent:    0,
+		dto.JobDead:    0,
+	}
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan mail job count: %w", err)
+		}
+		depth[status] = count
+	}
+
+	return depth, rows.Err()
+}
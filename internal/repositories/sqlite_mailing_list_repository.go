@@ -2,12 +2,15 @@ package repositories
 
 import (
 	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
 type SqliteMailingListRepository struct {
@@ -15,68 +18,643 @@ type SqliteMailingListRepository struct {
 }
 
 func NewSqliteMailingListRepository(dbPath string) (*SqliteMailingListRepository, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := OpenSqlite(dbPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
 
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys=ON"); err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+	if err := Migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
 	}
 
 	repo := &SqliteMailingListRepository{db: db}
-
-	// Initialize schema
-	if err := repo.initSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
+	repo.reportUnnormalized()
 
 	return repo, nil
 }
 
-func (r *SqliteMailingListRepository) initSchema() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS mailing_list (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		username TEXT NOT NULL,
-		email TEXT NOT NULL UNIQUE,
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
-	);
+// reportUnnormalized logs rows left without a normalized_email because
+// another row already had their key when the column was backfilled. They
+// stay that way until Dedupe merges them.
+func (r *SqliteMailingListRepository) reportUnnormalized() {
+	var duplicates int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM mailing_list WHERE normalized_email IS NULL`).Scan(&duplicates); err != nil {
+		log.Printf("Failed to check for duplicate subscribers: %v", err)
+		return
+	}
+	if duplicates > 0 {
+		log.Printf("Found %d subscribers whose email differs from another one only in case, merge them with the dedupe command", duplicates)
+	}
+}
+
+// Save inserts a subscriber into the list with listID. Subscribers are unique
+// per list on NormalizedEmail, which defaults to the lowercased address.
+// Saving an address that is already active is a no-op; saving a pending
+// signup over a pending or unsubscribed row replaces its confirmation token,
+// frequency and topics so a fresh confirmation email can be sent, but keeps
+// its username, which Username is set to. It returns
+// interfaces.ErrTopicNotFound when one of Topics doesn't exist and
+// interfaces.ErrListNotFound for an unknown list.
+// After Save, Status reflects the stored row and ConfirmationToken is cleared
+// when the token was not persisted.
+func (r *SqliteMailingListRepository) Save(listID string, mailingList *dto.MailingList) error {
+	// Stored in UTC so created_at sorts and compares as text
+	createdAt := mailingList.CreatedAt.UTC()
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+
+	status := mailingList.Status
+	if status == "" {
+		status = dto.StatusActive
+	}
 
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_email ON mailing_list(email);
-	CREATE INDEX IF NOT EXISTS idx_mailing_list_created_at ON mailing_list(created_at);
-	`
+	frequency := mailingList.Frequency
+	if frequency == "" {
+		frequency = dto.FrequencyImmediate
+	}
 
-	_, err := r.db.Exec(schema)
+	var (
+		token     sql.NullString
+		expiresAt sql.NullTime
+	)
+	if mailingList.ConfirmationToken != "" {
+		token = sql.NullString{String: mailingList.ConfirmationToken, Valid: true}
+		expiresAt = sql.NullTime{Time: mailingList.ConfirmationExpiresAt.UTC(), Valid: true}
+	}
+
+	normalized := mailingList.NormalizedEmail
+	if normalized == "" {
+		normalized = normalize.Normalizer{}.Key(mailingList.Email)
+	}
+
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := requireList(tx, listID); err != nil {
+		return err
 	}
 
+	topicIDs, err := findTopicIDs(tx, mailingList.Topics)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO mailing_list (list_id, username, email, normalized_email, status, frequency, confirmation_token, confirmation_expires_at, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(list_id, normalized_email) DO UPDATE SET
+		email = excluded.email,
+		status = excluded.status,
+		frequency = excluded.frequency,
+		confirmation_token = excluded.confirmation_token,
+		confirmation_expires_at = excluded.confirmation_expires_at
+	WHERE mailing_list.status != 'active' AND excluded.status = 'pending'
+	ON CONFLICT(list_id, email) DO NOTHING`
+
+	result, err := tx.Exec(query, listID, mailingList.Username, mailingList.Email, normalized, status, frequency, token, expiresAt, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to save mailing list entry: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to save mailing list entry: %w", err)
+	}
+	mailingList.ListID = listID
+	mailingList.NormalizedEmail = normalized
+	if affected > 0 {
+		var id int64
+		if err := tx.QueryRow(`SELECT id, username FROM mailing_list WHERE list_id = ? AND normalized_email = ?`, listID, normalized).Scan(&id, &mailingList.Username); err != nil {
+			return fmt.Errorf("failed to load mailing list entry: %w", err)
+		}
+		if err := setSubscriberTopics(tx, id, topicIDs); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		mailingList.Status = status
+		mailingList.Frequency = frequency
+		return nil
+	}
+
+	log.Printf("Email already subscribed: %s", mailingList.Email)
+	err = tx.QueryRow(`
+	SELECT status FROM mailing_list
+	WHERE list_id = ? AND (normalized_email = ? OR email = ?)
+	LIMIT 1`, listID, normalized, mailingList.Email).Scan(&mailingList.Status)
+	if err != nil {
+		return fmt.Errorf("failed to load mailing list entry: %w", err)
+	}
+	mailingList.ConfirmationToken = ""
+	mailingList.ConfirmationExpiresAt = time.Time{}
+
 	return nil
 }
 
-func (r *SqliteMailingListRepository) Save(mailingList *dto.MailingList) error {
-	createdAt := mailingList.CreatedAt
-	if createdAt.IsZero() {
-		createdAt = time.Now()
+// Import inserts subscribers into the list with listID as they are,
+// including their status and confirmation and unsubscribe times, in one
+// transaction. Addresses already on the list are left alone and reported as
+// not inserted.
+func (r *SqliteMailingListRepository) Import(listID string, subscribers []dto.MailingList, dryRun bool) ([]bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	query := `INSERT INTO mailing_list (username, email, created_at) VALUES (?, ?, ?)`
+	if err := requireList(tx, listID); err != nil {
+		return nil, err
+	}
 
-	_, err := r.db.Exec(query, mailingList.Username, mailingList.Email, createdAt)
+	statement, err := tx.Prepare(`
+	INSERT INTO mailing_list (list_id, username, email, normalized_email, status, created_at, confirmed_at, unsubscribed_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT DO NOTHING`)
 	if err != nil {
-		// Check if it's a unique constraint violation
-		if err.Error() == "UNIQUE constraint failed: mailing_list.email" {
-			log.Printf("Email already subscribed: %s", mailingList.Email)
-			return nil // Same behavior as CSV implementation
+		return nil, fmt.Errorf("failed to prepare import: %w", err)
+	}
+	defer func() {
+		if closeErr := statement.Close(); closeErr != nil {
+			log.Printf("Error closing statement: %v", closeErr)
 		}
-		return fmt.Errorf("failed to save mailing list entry: %w", err)
+	}()
+
+	inserted := make([]bool, len(subscribers))
+	for i, subscriber := range subscribers {
+		normalized := subscriber.NormalizedEmail
+		if normalized == "" {
+			normalized = normalize.Normalizer{}.Key(subscriber.Email)
+		}
+		status := subscriber.Status
+		if status == "" {
+			status = dto.StatusActive
+		}
+		createdAt := subscriber.CreatedAt.UTC()
+		if createdAt.IsZero() {
+			createdAt = time.Now().UTC()
+		}
+
+		result, err := statement.Exec(listID, subscriber.Username, subscriber.Email, normalized, status, createdAt, utcOrNil(subscriber.ConfirmedAt), utcOrNil(subscriber.UnsubscribedAt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", subscriber.Email, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", subscriber.Email, err)
+		}
+		inserted[i] = affected > 0
+	}
+
+	if dryRun {
+		return inserted, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return inserted, nil
+}
+
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// Confirm activates the pending subscriber of the list with listID owning
+// token. It returns interfaces.ErrInvalidToken when the token is unknown or
+// has expired.
+func (r *SqliteMailingListRepository) Confirm(listID, token string) (*dto.MailingList, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id         int64
+		normalized sql.NullString
+		expiresAt  sql.NullTime
+	)
+	if err := requireList(tx, listID); err != nil {
+		return nil, err
+	}
+
+	mailingList := &dto.MailingList{ListID: listID}
+	err = tx.QueryRow(`
+	SELECT id, username, email, normalized_email, created_at, confirmation_expires_at
+	FROM mailing_list
+	WHERE list_id = ? AND confirmation_token = ? AND status = ?`, listID, token, dto.StatusPending).
+		Scan(&id, &mailingList.Username, &mailingList.Email, &normalized, &mailingList.CreatedAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mailing list entry: %w", err)
+	}
+	mailingList.NormalizedEmail = normalized.String
+
+	now := time.Now().UTC()
+	if !expiresAt.Valid || now.After(expiresAt.Time) {
+		return nil, interfaces.ErrInvalidToken
+	}
+
+	_, err = tx.Exec(`
+	UPDATE mailing_list
+	SET status = ?, confirmed_at = ?, confirmation_token = NULL, confirmation_expires_at = NULL
+	WHERE id = ?`, dto.StatusActive, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm mailing list entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	mailingList.Status = dto.StatusActive
+	mailingList.ConfirmedAt = &now
+
+	return mailingList, nil
+}
+
+// Unsubscribe marks the subscriber with email as unsubscribed from the list
+// with listID, matching the address case-insensitively. Unsubscribing twice
+// is not an error. It returns interfaces.ErrSubscriberNotFound when the
+// address was never subscribed to the list.
+func (r *SqliteMailingListRepository) Unsubscribe(listID, email string) (*dto.MailingList, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id             int64
+		normalized     sql.NullString
+		confirmedAt    sql.NullTime
+		unsubscribedAt sql.NullTime
+	)
+	if err := requireList(tx, listID); err != nil {
+		return nil, err
+	}
+
+	mailingList := &dto.MailingList{ListID: listID}
+	err = tx.QueryRow(`
+	SELECT id, username, email, normalized_email, status, created_at, confirmed_at, unsubscribed_at
+	FROM mailing_list
+	WHERE list_id = ? AND (normalized_email = ? OR email = ?)
+	ORDER BY normalized_email IS NULL
+	LIMIT 1`, listID, normalize.Normalizer{}.Key(email), email).
+		Scan(&id, &mailingList.Username, &mailingList.Email, &normalized, &mailingList.Status, &mailingList.CreatedAt, &confirmedAt, &unsubscribedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrSubscriberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mailing list entry: %w", err)
+	}
+	mailingList.NormalizedEmail = normalized.String
+	if confirmedAt.Valid {
+		mailingList.ConfirmedAt = &confirmedAt.Time
+	}
+
+	if mailingList.Status == dto.StatusUnsubscribed {
+		if unsubscribedAt.Valid {
+			mailingList.UnsubscribedAt = &unsubscribedAt.Time
+		}
+		return mailingList, nil
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(`
+	UPDATE mailing_list
+	SET status = ?, unsubscribed_at = ?, confirmation_token = NULL, confirmation_expires_at = NULL
+	WHERE id = ?`, dto.StatusUnsubscribed, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unsubscribe mailing list entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	mailingList.Status = dto.StatusUnsubscribed
+	mailingList.UnsubscribedAt = &now
+
+	return mailingList, nil
+}
+
+// Delete removes the subscriber with email from the list with listID,
+// matching the address case-insensitively. It returns
+// interfaces.ErrSubscriberNotFound when there is no such subscriber.
+func (r *SqliteMailingListRepository) Delete(listID, email string) error {
+	result, err := r.db.Exec(`
+	DELETE FROM mailing_list
+	WHERE id = (
+		SELECT id FROM mailing_list
+		WHERE list_id = ? AND (normalized_email = ? OR email = ?)
+		ORDER BY normalized_email IS NULL
+		LIMIT 1
+	)`, listID, normalize.Normalizer{}.Key(email), email)
+	if err != nil {
+		return fmt.Errorf("failed to delete mailing list entry: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete mailing list entry: %w", err)
+	}
+	if affected == 0 {
+		return interfaces.ErrSubscriberNotFound
 	}
 
 	return nil
 }
 
+// List pages through the subscribers of the list with listID newest first
+// using keyset pagination on (created_at, id), which is served by
+// idx_mailing_list_created_at. query.Limit must be positive.
+func (r *SqliteMailingListRepository) List(listID string, query dto.SubscriberQuery) (*dto.SubscriberPage, error) {
+	if err := requireList(r.db, listID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"list_id = ?"}
+	args := []any{listID}
+	if query.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, query.Status)
+	}
+	if !query.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.CreatedAfter.UTC())
+	}
+	if !query.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.CreatedBefore.UTC())
+	}
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		conditions = append(conditions, `(email LIKE ? ESCAPE '\' OR username LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeSubscriberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, cursor.createdAt, cursor.createdAt, cursor.id)
+	}
+
+	statement := `
+	SELECT id, username, email, status, created_at, confirmed_at, unsubscribed_at
+	FROM mailing_list
+	WHERE ` + strings.Join(conditions, " AND ")
+	statement += "\n\tORDER BY created_at DESC, id DESC\n\tLIMIT ?"
+	// Fetch one extra row to know whether there is a next page
+	args = append(args, query.Limit+1)
+
+	rows, err := r.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	page := &dto.SubscriberPage{Subscribers: []dto.MailingList{}}
+	var last subscriberCursor
+	for rows.Next() {
+		if len(page.Subscribers) == query.Limit {
+			page.NextCursor = last.encode()
+			break
+		}
+
+		var (
+			id             int64
+			subscriber     = dto.MailingList{ListID: listID}
+			confirmedAt    sql.NullTime
+			unsubscribedAt sql.NullTime
+		)
+		if err := rows.Scan(&id, &subscriber.Username, &subscriber.Email, &subscriber.Status, &subscriber.CreatedAt, &confirmedAt, &unsubscribedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		if confirmedAt.Valid {
+			subscriber.ConfirmedAt = &confirmedAt.Time
+		}
+		if unsubscribedAt.Valid {
+			subscriber.UnsubscribedAt = &unsubscribedAt.Time
+		}
+
+		page.Subscribers = append(page.Subscribers, subscriber)
+		last = subscriberCursor{createdAt: subscriber.CreatedAt, id: id}
+	}
+
+	return page, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s for use with ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListActive returns the confirmed subscribers of the list with listID in
+// signup order, with their frequency and topics.
+func (r *SqliteMailingListRepository) ListActive(listID string) ([]dto.MailingList, error) {
+	if err := requireList(r.db, listID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+	SELECT username, email, status, frequency, created_at, confirmed_at,
+		(SELECT group_concat(topics.slug, ',')
+		FROM subscriber_topics
+		JOIN topics ON topics.id = subscriber_topics.topic_id
+		WHERE subscriber_topics.subscriber_id = mailing_list.id)
+	FROM mailing_list
+	WHERE list_id = ? AND status = ?
+	ORDER BY id`, listID, dto.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var subscribers []dto.MailingList
+	for rows.Next() {
+		var (
+			subscriber  = dto.MailingList{ListID: listID}
+			confirmedAt sql.NullTime
+			topics      sql.NullString
+		)
+		if err := rows.Scan(&subscriber.Username, &subscriber.Email, &subscriber.Status, &subscriber.Frequency, &subscriber.CreatedAt, &confirmedAt, &topics); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		if confirmedAt.Valid {
+			subscriber.ConfirmedAt = &confirmedAt.Time
+		}
+		subscriber.Topics = splitTopics(topics)
+		subscribers = append(subscribers, subscriber)
+	}
+
+	return subscribers, rows.Err()
+}
+
+// statusRank orders statuses by which one survives a merge of duplicate
+// subscribers: someone who confirmed any of their addresses stays subscribed.
+var statusRank = map[string]int{
+	dto.StatusActive:       0,
+	dto.StatusPending:      1,
+	dto.StatusUnsubscribed: 2,
+}
+
+// Dedupe recomputes normalized_email for every subscriber of the list with
+// listID with normalizer and merges subscribers sharing one. The subscriber
+// with the strongest status is kept, oldest first on ties, and takes the
+// earliest signup and confirmation times of the group; the others are
+// deleted. With dryRun nothing is changed and only the groups that would be
+// merged are returned.
+func (r *SqliteMailingListRepository) Dedupe(listID string, normalizer normalize.Normalizer, dryRun bool) ([]dto.DuplicateGroup, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	type subscriber struct {
+		createdAt   time.Time
+		confirmedAt sql.NullTime
+		email       string
+		status      string
+		normalized  sql.NullString
+		id          int64
+	}
+
+	if err := requireList(tx, listID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+	SELECT id, email, status, created_at, confirmed_at, normalized_email
+	FROM mailing_list
+	WHERE list_id = ?
+	ORDER BY id`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	groups := make(map[string][]subscriber)
+	var keys []string
+	for rows.Next() {
+		var current subscriber
+		if err := rows.Scan(&current.id, &current.email, &current.status, &current.createdAt, &current.confirmedAt, &current.normalized); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		key := normalizer.Key(current.email)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], current)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	slices.Sort(keys)
+
+	var (
+		merged  []dto.DuplicateGroup
+		deletes []int64
+		updates = make(map[int64]subscriber)
+	)
+	for _, key := range keys {
+		group := groups[key]
+		kept := slices.MinFunc(group, func(a, b subscriber) int {
+			if rank := statusRank[a.status] - statusRank[b.status]; rank != 0 {
+				return rank
+			}
+			return int(a.id - b.id)
+		})
+
+		update, changed := kept, kept.normalized.String != key
+		for _, other := range group {
+			if other.createdAt.Before(update.createdAt) {
+				update.createdAt = other.createdAt
+				changed = true
+			}
+			earlierConfirmation := other.confirmedAt.Valid &&
+				(!update.confirmedAt.Valid || other.confirmedAt.Time.Before(update.confirmedAt.Time))
+			if kept.status == dto.StatusActive && earlierConfirmation {
+				update.confirmedAt = other.confirmedAt
+				changed = true
+			}
+		}
+		if changed {
+			update.normalized = sql.NullString{String: key, Valid: true}
+			updates[kept.id] = update
+		}
+
+		if len(group) == 1 {
+			continue
+		}
+		duplicate := dto.DuplicateGroup{NormalizedEmail: key, Kept: kept.email, Status: kept.status}
+		for _, other := range group {
+			if other.id != kept.id {
+				duplicate.Merged = append(duplicate.Merged, other.email)
+				deletes = append(deletes, other.id)
+			}
+		}
+		merged = append(merged, duplicate)
+	}
+
+	if dryRun {
+		return merged, nil
+	}
+
+	for _, id := range deletes {
+		if _, err := tx.Exec(`DELETE FROM mailing_list WHERE id = ?`, id); err != nil {
+			return nil, fmt.Errorf("failed to delete duplicate subscriber: %w", err)
+		}
+	}
+	// Clear the keys first so rows can swap keys without tripping the
+	// unique index midway.
+	for id := range updates {
+		if _, err := tx.Exec(`UPDATE mailing_list SET normalized_email = NULL WHERE id = ?`, id); err != nil {
+			return nil, fmt.Errorf("failed to update subscriber: %w", err)
+		}
+	}
+	for id, update := range updates {
+		_, err := tx.Exec(`
+		UPDATE mailing_list SET normalized_email = ?, created_at = ?, confirmed_at = ?
+		WHERE id = ?`, update.normalized, update.createdAt.UTC(), update.confirmedAt, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update subscriber: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return merged, nil
+}
+
+// DB exposes the underlying connection so repositories for other tables can
+// share it.
+func (r *SqliteMailingListRepository) DB() *sql.DB {
+	return r.db
+}
+
 func (r *SqliteMailingListRepository) Close() error {
 	if r.db != nil {
 		return r.db.Close()
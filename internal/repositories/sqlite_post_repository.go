@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SqlitePostRepository records announced blog posts in the announced_posts
+// table. Announcement emails are written to the mail_queue table in the same
+// transaction.
+type SqlitePostRepository struct {
+	db *sql.DB
+}
+
+func NewSqlitePostRepository(db *sql.DB) (*SqlitePostRepository, error) {
+	repo := &SqlitePostRepository{db: db}
+
+	if err := requireTable(db, "announced_posts"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SqlitePostRepository) Initialized() (bool, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM feed_watcher`).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to load feed watcher state: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *SqlitePostRepository) Initialize(posts []dto.Post) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, post := range posts {
+		if _, err := recordPost(tx, post, 0); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`INSERT INTO feed_watcher (id, initialized_at) VALUES (1, ?) ON CONFLICT(id) DO NOTHING`, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record feed watcher state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *SqlitePostRepository) AnnouncedGUIDs() (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT guid FROM announced_posts`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load announced posts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	guids := map[string]bool{}
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, fmt.Errorf("failed to scan announced post: %w", err)
+		}
+		guids[guid] = true
+	}
+
+	return guids, rows.Err()
+}
+
+func (r *SqlitePostRepository) Announce(post dto.Post, emails []*dto.Email) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	recorded, err := recordPost(tx, post, len(emails))
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		log.Printf("Post already announced: %s", post.GUID)
+		return nil
+	}
+
+	for _, email := range emails {
+		if err := enqueueMail(tx, email); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// recordPost records post as announced to recipients subscribers and reports
+// whether it was new.
+func recordPost(tx *sql.Tx, post dto.Post, recipients int) (bool, error) {
+	var publishedAt sql.NullTime
+	if !post.Published.IsZero() {
+		publishedAt = sql.NullTime{Time: post.Published.UTC(), Valid: true}
+	}
+
+	result, err := tx.Exec(`
+	INSERT INTO announced_posts (guid, title, link, published_at, recipients, announced_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT(guid) DO NOTHING`, post.GUID, post.Title, post.Link, publishedAt, recipients, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to record announced post: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record announced post: %w", err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,364 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// SqlitePrivacyRepository answers data subject requests across the tables
+// holding personal data, and keeps their audit log in privacy_requests.
+type SqlitePrivacyRepository struct {
+	db *sql.DB
+}
+
+func NewSqlitePrivacyRepository(db *sql.DB) (*SqlitePrivacyRepository, error) {
+	repo := &SqlitePrivacyRepository{db: db}
+
+	if err := requireTable(db, "privacy_requests"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SqlitePrivacyRepository) CreateRequest(request *dto.PrivacyRequest) error {
+	if request.RequestedAt.IsZero() {
+		request.RequestedAt = time.Now().UTC()
+	}
+
+	result, err := r.db.Exec(`
+	INSERT INTO privacy_requests (kind, email_hash, status, details, requested_at)
+	VALUES (?, ?, ?, ?, ?)`, request.Kind, request.EmailHash, request.Status, nullString(request.Details), request.RequestedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to create privacy request: %w", err)
+	}
+
+	request.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to create privacy request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqlitePrivacyRepository) FindRequest(id int64) (*dto.PrivacyRequest, error) {
+	row := r.db.QueryRow(`
+	SELECT id, kind, email_hash, status, details, requested_at, completed_at
+	FROM privacy_requests
+	WHERE id = ?`, id)
+
+	request, err := scanPrivacyRequest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrPrivacyRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load privacy request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (r *SqlitePrivacyRepository) ListRequests(emailHash string) ([]dto.PrivacyRequest, error) {
+	return listPrivacyRequests(r.db, emailHash)
+}
+
+func (r *SqlitePrivacyRepository) HasData(email, normalizedEmail string) (bool, error) {
+	var found bool
+	err := r.db.QueryRow(`
+	SELECT EXISTS (SELECT 1 FROM mailing_list WHERE normalized_email = ? OR email = ?)
+		OR EXISTS (SELECT 1 FROM campaign_deliveries WHERE email = ?)
+		OR EXISTS (SELECT 1 FROM mail_queue WHERE recipient = ?)
+		OR EXISTS (SELECT 1 FROM consent_events WHERE normalized_email = ? OR email = ?)`,
+		normalizedEmail, email, email, email, normalizedEmail, email).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up personal data: %w", err)
+	}
+	return found, nil
+}
+
+// Access collects the subscriptions and consent events matching the address,
+// and the deliveries and emails of every address those subscriptions were
+// stored with.
+func (r *SqlitePrivacyRepository) Access(request *dto.PrivacyRequest, email, normalizedEmail string) (*dto.SubjectData, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	data := &dto.SubjectData{
+		GeneratedAt:     time.Now().UTC(),
+		Email:           email,
+		Subscriptions:   []dto.MailingList{},
+		Deliveries:      []dto.CampaignDelivery{},
+		Emails:          []dto.SentEmail{},
+		ConsentEvents:   []dto.ConsentEvent{},
+		PrivacyRequests: []dto.PrivacyRequest{},
+	}
+
+	addresses := []string{email}
+	err = queryRows(tx, func(rows *sql.Rows) error {
+		var (
+			subscriber     dto.MailingList
+			confirmedAt    sql.NullTime
+			unsubscribedAt sql.NullTime
+			topics         sql.NullString
+		)
+		if err := rows.Scan(&subscriber.ListID, &subscriber.Username, &subscriber.Email, &subscriber.Status, &subscriber.Frequency, &subscriber.CreatedAt, &confirmedAt, &unsubscribedAt, &topics); err != nil {
+			return err
+		}
+		subscriber.Topics = splitTopics(topics)
+		if confirmedAt.Valid {
+			subscriber.ConfirmedAt = &confirmedAt.Time
+		}
+		if unsubscribedAt.Valid {
+			subscriber.UnsubscribedAt = &unsubscribedAt.Time
+		}
+		data.Subscriptions = append(data.Subscriptions, subscriber)
+		addresses = append(addresses, subscriber.Email)
+		return nil
+	}, `
+	SELECT list_id, username, email, status, frequency, created_at, confirmed_at, unsubscribed_at,
+		(SELECT group_concat(topics.slug, ',')
+		FROM subscriber_topics
+		JOIN topics ON topics.id = subscriber_topics.topic_id
+		WHERE subscriber_topics.subscriber_id = mailing_list.id)
+	FROM mailing_list
+	WHERE normalized_email = ? OR email = ?
+	ORDER BY id`, normalizedEmail, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect subscriptions: %w", err)
+	}
+
+	placeholders, args := inList(addresses)
+	err = queryRows(tx, func(rows *sql.Rows) error {
+		var (
+			delivery  dto.CampaignDelivery
+			sendError sql.NullString
+			sentAt    sql.NullTime
+		)
+		if err := rows.Scan(&delivery.ListID, &delivery.Campaign, &delivery.Status, &sendError, &delivery.UpdatedAt, &sentAt); err != nil {
+			return err
+		}
+		delivery.Error = sendError.String
+		if sentAt.Valid {
+			delivery.SentAt = &sentAt.Time
+		}
+		data.Deliveries = append(data.Deliveries, delivery)
+		return nil
+	}, `
+	SELECT list_id, campaign, status, error, updated_at, sent_at
+	FROM campaign_deliveries
+	WHERE email IN (`+placeholders+`)
+	ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect deliveries: %w", err)
+	}
+
+	err = queryRows(tx, func(rows *sql.Rows) error {
+		var (
+			sent    dto.SentEmail
+			payload string
+			message dto.Email
+		)
+		if err := rows.Scan(&payload, &sent.Status, &sent.CreatedAt); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(payload), &message); err == nil {
+			sent.Subject = message.Subject
+		}
+		data.Emails = append(data.Emails, sent)
+		return nil
+	}, `
+	SELECT payload, status, created_at
+	FROM mail_queue
+	WHERE recipient IN (`+placeholders+`)
+	ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect emails: %w", err)
+	}
+
+	if data.ConsentEvents, err = listConsentEvents(tx, email, normalizedEmail); err != nil {
+		return nil, err
+	}
+
+	if err := completeRequest(tx, request, ""); err != nil {
+		return nil, err
+	}
+	if data.PrivacyRequests, err = listPrivacyRequests(tx, request.EmailHash); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return data, nil
+}
+
+// Erase deletes the subscriptions and consent events matching the address,
+// and every email queued for the addresses they were stored with. Campaign
+// deliveries are kept for the campaign's statistics, with the address
+// replaced. The audit log only holds hashes and is kept.
+func (r *SqlitePrivacyRepository) Erase(request *dto.PrivacyRequest, email, normalizedEmail string) (*dto.ErasureReport, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	addresses := []string{email}
+	err = queryRows(tx, func(rows *sql.Rows) error {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return err
+		}
+		addresses = append(addresses, address)
+		return nil
+	}, `SELECT email FROM mailing_list WHERE normalized_email = ? OR email = ?`, normalizedEmail, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscriptions: %w", err)
+	}
+	placeholders, args := inList(addresses)
+
+	report := &dto.ErasureReport{}
+	steps := []struct {
+		count     *int64
+		statement string
+		args      []any
+	}{
+		{&report.Subscribers, `DELETE FROM mailing_list WHERE normalized_email = ? OR email = ?`, []any{normalizedEmail, email}},
+		{&report.Deliveries, `UPDATE campaign_deliveries SET email = 'erased-' || id, error = NULL WHERE email IN (` + placeholders + `)`, args},
+		{&report.Emails, `DELETE FROM mail_queue WHERE recipient IN (` + placeholders + `)`, args},
+		{&report.ConsentEvents, `DELETE FROM consent_events WHERE normalized_email = ? OR email IN (` + placeholders + `)`, append([]any{normalizedEmail}, args...)},
+	}
+	for _, step := range steps {
+		result, err := tx.Exec(step.statement, step.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to erase personal data: %w", err)
+		}
+		if *step.count, err = result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to erase personal data: %w", err)
+		}
+	}
+
+	details := fmt.Sprintf("deleted %d subscriptions, %d emails and %d consent events, anonymized %d deliveries", report.Subscribers, report.Emails, report.ConsentEvents, report.Deliveries)
+	if err := completeRequest(tx, request, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return report, nil
+}
+
+// completeRequest marks request completed, keeping the time of a previous
+// completion such as an access link that was opened twice.
+func completeRequest(tx *sql.Tx, request *dto.PrivacyRequest, details string) error {
+	now := time.Now().UTC()
+	if request.CompletedAt == nil {
+		request.CompletedAt = &now
+	}
+	request.Status = dto.PrivacyCompleted
+	if details != "" {
+		request.Details = details
+	}
+
+	_, err := tx.Exec(`
+	UPDATE privacy_requests SET status = ?, details = ?, completed_at = ? WHERE id = ?`,
+		request.Status, nullString(request.Details), request.CompletedAt.UTC(), request.ID)
+	if err != nil {
+		return fmt.Errorf("failed to complete privacy request: %w", err)
+	}
+	return nil
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryRows calls scan for every row of query.
+func queryRows(db querier, scan func(rows *sql.Rows) error, query string, args ...any) error {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func listPrivacyRequests(db querier, emailHash string) ([]dto.PrivacyRequest, error) {
+	statement := `
+	SELECT id, kind, email_hash, status, details, requested_at, completed_at
+	FROM privacy_requests`
+	var args []any
+	if emailHash != "" {
+		statement += "\n\tWHERE email_hash = ?"
+		args = append(args, emailHash)
+	}
+	statement += "\n\tORDER BY id DESC"
+
+	requests := []dto.PrivacyRequest{}
+	err := queryRows(db, func(rows *sql.Rows) error {
+		request, err := scanPrivacyRequest(rows)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, *request)
+		return nil
+	}, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list privacy requests: %w", err)
+	}
+	return requests, nil
+}
+
+func scanPrivacyRequest(row rowScanner) (*dto.PrivacyRequest, error) {
+	var (
+		request     dto.PrivacyRequest
+		details     sql.NullString
+		completedAt sql.NullTime
+	)
+	if err := row.Scan(&request.ID, &request.Kind, &request.EmailHash, &request.Status, &details, &request.RequestedAt, &completedAt); err != nil {
+		return nil, err
+	}
+	request.Details = details.String
+	if completedAt.Valid {
+		request.CompletedAt = &completedAt.Time
+	}
+	return &request, nil
+}
+
+// inList returns the placeholders and arguments of an IN clause for values.
+func inList(values []string) (string, []any) {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), args
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
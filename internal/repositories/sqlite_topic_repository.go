@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SqliteTopicRepository stores topics in the topics table and which ones each
+// subscriber chose in subscriber_topics.
+type SqliteTopicRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteTopicRepository(db *sql.DB) (*SqliteTopicRepository, error) {
+	repo := &SqliteTopicRepository{db: db}
+
+	if err := requireTable(db, "topics"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// CreateTopic returns interfaces.ErrTopicExists when the slug is taken.
+func (r *SqliteTopicRepository) CreateTopic(topic *dto.Topic) error {
+	if topic.CreatedAt.IsZero() {
+		topic.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := r.db.Exec(`
+	INSERT INTO topics (slug, name, description, created_at)
+	VALUES (?, ?, ?, ?)`, topic.Slug, topic.Name, nullString(topic.Description), topic.CreatedAt.UTC())
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return fmt.Errorf("%w: %s", interfaces.ErrTopicExists, topic.Slug)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create topic: %w", err)
+	}
+
+	topic.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to create topic: %w", err)
+	}
+
+	return nil
+}
+
+// ListTopics returns every topic ordered by slug.
+func (r *SqliteTopicRepository) ListTopics() ([]dto.Topic, error) {
+	return listTopics(r.db)
+}
+
+// DeleteTopic returns interfaces.ErrTopicNotFound when there is no topic with
+// slug. Subscribers left without topics receive every topic again.
+func (r *SqliteTopicRepository) DeleteTopic(slug string) error {
+	result, err := r.db.Exec(`DELETE FROM topics WHERE slug = ?`, slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrTopicNotFound, slug)
+	}
+
+	return nil
+}
+
+// Preferences returns interfaces.ErrSubscriberNotFound when the address was
+// never subscribed to the list with listID.
+func (r *SqliteTopicRepository) Preferences(listID, email string) (*dto.Preferences, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, preferences, err := loadPreferences(tx, listID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return preferences, nil
+}
+
+func (r *SqliteTopicRepository) UpdatePreferences(listID, email string, update dto.PreferencesUpdate) (*dto.Preferences, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	id, _, err := loadPreferences(tx, listID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Username != nil {
+		if _, err := tx.Exec(`UPDATE mailing_list SET username = ? WHERE id = ?`, *update.Username, id); err != nil {
+			return nil, fmt.Errorf("failed to update preferences: %w", err)
+		}
+	}
+	if update.Frequency != nil {
+		if _, err := tx.Exec(`UPDATE mailing_list SET frequency = ? WHERE id = ?`, *update.Frequency, id); err != nil {
+			return nil, fmt.Errorf("failed to update preferences: %w", err)
+		}
+	}
+	if update.Topics != nil {
+		topicIDs, err := findTopicIDs(tx, *update.Topics)
+		if err != nil {
+			return nil, err
+		}
+		if err := setSubscriberTopics(tx, id, topicIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	_, preferences, err := loadPreferences(tx, listID, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return preferences, nil
+}
+
+// loadPreferences finds the subscriber with email on the list with listID
+// and returns their id and preferences.
+func loadPreferences(tx *sql.Tx, listID, email string) (int64, *dto.Preferences, error) {
+	if err := requireList(tx, listID); err != nil {
+		return 0, nil, err
+	}
+
+	var id int64
+	preferences := &dto.Preferences{}
+	err := tx.QueryRow(`
+	SELECT id, username, email, frequency
+	FROM mailing_list
+	WHERE list_id = ? AND (normalized_email = ? OR email = ?)
+	ORDER BY normalized_email IS NULL
+	LIMIT 1`, listID, normalize.Normalizer{}.Key(email), email).
+		Scan(&id, &preferences.Username, &preferences.Email, &preferences.Frequency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, interfaces.ErrSubscriberNotFound
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load mailing list entry: %w", err)
+	}
+
+	preferences.Topics = []string{}
+	err = queryRows(tx, func(rows *sql.Rows) error {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return err
+		}
+		preferences.Topics = append(preferences.Topics, slug)
+		return nil
+	}, `
+	SELECT topics.slug
+	FROM subscriber_topics
+	JOIN topics ON topics.id = subscriber_topics.topic_id
+	WHERE subscriber_topics.subscriber_id = ?
+	ORDER BY topics.slug`, id)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load topics: %w", err)
+	}
+
+	if preferences.AvailableTopics, err = listTopics(tx); err != nil {
+		return 0, nil, err
+	}
+	return id, preferences, nil
+}
+
+func listTopics(db querier) ([]dto.Topic, error) {
+	topics := []dto.Topic{}
+	err := queryRows(db, func(rows *sql.Rows) error {
+		var (
+			topic       dto.Topic
+			description sql.NullString
+		)
+		if err := rows.Scan(&topic.ID, &topic.Slug, &topic.Name, &description, &topic.CreatedAt); err != nil {
+			return err
+		}
+		topic.Description = description.String
+		topics = append(topics, topic)
+		return nil
+	}, `
+	SELECT id, slug, name, description, created_at
+	FROM topics
+	ORDER BY slug`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	return topics, nil
+}
+
+// findTopicIDs returns the ids of the topics with slugs, or
+// interfaces.ErrTopicNotFound for the first slug that doesn't exist.
+func findTopicIDs(tx *sql.Tx, slugs []string) ([]int64, error) {
+	ids := make([]int64, 0, len(slugs))
+	for _, slug := range slugs {
+		var id int64
+		err := tx.QueryRow(`SELECT id FROM topics WHERE slug = ?`, slug).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", interfaces.ErrTopicNotFound, slug)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load topic: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// setSubscriberTopics replaces the topics of the subscriber with id.
+func setSubscriberTopics(tx *sql.Tx, id int64, topicIDs []int64) error {
+	if _, err := tx.Exec(`DELETE FROM subscriber_topics WHERE subscriber_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to update topics: %w", err)
+	}
+	for _, topicID := range topicIDs {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO subscriber_topics (subscriber_id, topic_id) VALUES (?, ?)`, id, topicID)
+		if err != nil {
+			return fmt.Errorf("failed to update topics: %w", err)
+		}
+	}
+	return nil
+}
+
+// splitTopics parses the comma-separated slugs of a GROUP_CONCAT.
+func splitTopics(slugs sql.NullString) []string {
+	if !slugs.Valid || slugs.String == "" {
+		return nil
+	}
+	return strings.Split(slugs.String, ",")
+}
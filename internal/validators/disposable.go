@@ -0,0 +1,79 @@
+package validators
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+//go:embed disposable_domains.txt
+var defaultDisposableDomains string
+
+// DomainSet is a set of domains. A domain matches when it or any of its
+// parent domains is in the set.
+type DomainSet map[string]struct{}
+
+// DefaultDisposableDomains returns the built-in list of disposable email
+// providers.
+var DefaultDisposableDomains = sync.OnceValue(func() DomainSet {
+	domains, err := ParseDomainSet(strings.NewReader(defaultDisposableDomains))
+	if err != nil {
+		panic(fmt.Sprintf("validators: invalid built-in disposable domains: %v", err))
+	}
+	return domains
+})
+
+// LoadDomainSet reads a domain list from path.
+func LoadDomainSet(path string) (DomainSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open domain list: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return ParseDomainSet(file)
+}
+
+// ParseDomainSet reads one domain per line. Blank lines and lines starting
+// with # are skipped.
+func ParseDomainSet(r io.Reader) (DomainSet, error) {
+	domains := make(DomainSet)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domain, err := toASCII(strings.TrimSuffix(line, "."))
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain %q: %w", line, err)
+		}
+		domains[domain] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domain list: %w", err)
+	}
+
+	return domains, nil
+}
+
+// Contains reports whether domain, given in ASCII form, or one of its parent
+// domains is in the set.
+func (s DomainSet) Contains(domain string) bool {
+	for {
+		if _, ok := s[domain]; ok {
+			return true
+		}
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			return false
+		}
+		domain = parent
+	}
+}
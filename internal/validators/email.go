@@ -0,0 +1,167 @@
+package validators
+
+import (
+	"backend-go/internal/dto"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Codes of the reasons an email address is rejected.
+const (
+	EmailRequired      = "email.required"
+	EmailInvalid       = "email.invalid"
+	EmailTypo          = "email.typo"
+	EmailDisposable    = "email.disposable"
+	EmailUndeliverable = "email.undeliverable"
+)
+
+// DefaultLookupTimeout bounds the DNS lookups of one validation.
+const DefaultLookupTimeout = 3 * time.Second
+
+// Resolver looks up the DNS records that show a domain accepts email.
+// *net.Resolver implements it.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// EmailValidator checks email addresses in stages: the syntax per RFC 5322
+// with RFC 6531 internationalized addresses, a disposable-domain blocklist,
+// likely typos of common domains and, when a resolver is set, whether the
+// domain has MX or address records.
+type EmailValidator struct {
+	disposable DomainSet
+	resolver   Resolver
+	timeout    time.Duration
+}
+
+// EmailValidatorOption customizes an EmailValidator.
+type EmailValidatorOption func(*EmailValidator)
+
+// WithDisposableDomains replaces the built-in disposable-domain blocklist.
+func WithDisposableDomains(domains DomainSet) EmailValidatorOption {
+	return func(v *EmailValidator) {
+		v.disposable = domains
+	}
+}
+
+// WithResolver enables the DNS check. Lookups that fail for reasons other
+// than the domain not existing let the address through, so a DNS outage
+// doesn't block signups.
+func WithResolver(resolver Resolver, timeout time.Duration) EmailValidatorOption {
+	return func(v *EmailValidator) {
+		v.resolver = resolver
+		v.timeout = timeout
+	}
+}
+
+func NewEmailValidator(opts ...EmailValidatorOption) *EmailValidator {
+	v := &EmailValidator{
+		disposable: DefaultDisposableDomains(),
+		timeout:    DefaultLookupTimeout,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate returns why email should not be subscribed, or nil when it can
+// be. The error suggests a corrected address when the domain looks like a
+// typo.
+func (v *EmailValidator) Validate(ctx context.Context, email string) *dto.FieldError {
+	if email == "" {
+		return emailError(EmailRequired, "email is required", "")
+	}
+
+	local, domain, err := parseEmail(email)
+	if err != nil {
+		return emailError(EmailInvalid, "invalid email format", "")
+	}
+
+	if v.disposable.Contains(domain) {
+		return emailError(EmailDisposable, "disposable email addresses are not accepted", "")
+	}
+
+	if suggestion := suggestDomain(domain); suggestion != "" {
+		corrected := local + "@" + suggestion
+		return emailError(EmailTypo, "email domain looks misspelled, did you mean "+corrected+"?", corrected)
+	}
+
+	if v.resolver != nil && !v.acceptsMail(ctx, domain) {
+		return emailError(EmailUndeliverable, "email domain does not accept mail", "")
+	}
+
+	return nil
+}
+
+func emailError(code, message, suggestion string) *dto.FieldError {
+	return &dto.FieldError{Field: "email", Code: code, Message: message, Suggestion: suggestion}
+}
+
+// parseEmail checks email is a bare addr-spec and returns its local part and
+// the ASCII form of its domain. Quoted local parts and domain literals are
+// valid RFC 5322 but not accepted, as no real subscriber uses them.
+func parseEmail(email string) (string, string, error) {
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", "", err
+	}
+	if address.Name != "" || address.Address != email {
+		return "", "", errors.New("not a bare address")
+	}
+
+	at := strings.LastIndexByte(email, '@')
+	local, domain := email[:at], email[at+1:]
+	if strings.HasPrefix(domain, "[") || !strings.Contains(domain, ".") {
+		return "", "", errInvalidDomain
+	}
+
+	ascii, err := toASCII(domain)
+	if err != nil {
+		return "", "", err
+	}
+
+	// A top-level domain is never numeric, and "user@1.2.3.4" is not a
+	// domain at all.
+	_, tld := splitTLD(ascii)
+	if len(tld) < 2 || strings.Trim(tld, "0123456789") == "" {
+		return "", "", errInvalidDomain
+	}
+
+	return local, ascii, nil
+}
+
+// acceptsMail reports whether domain publishes MX records or, lacking them,
+// address records, as SMTP falls back to those. A null MX (RFC 7505) means
+// the domain explicitly accepts no mail.
+func (v *EmailValidator) acceptsMail(ctx context.Context, domain string) bool {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	records, err := v.resolver.LookupMX(ctx, domain+".")
+	if err == nil && len(records) > 0 {
+		return !(len(records) == 1 && (records[0].Host == "." || records[0].Host == ""))
+	}
+	if err != nil && !isNotFound(err) {
+		log.Printf("Error looking up MX records of %s: %v", domain, err)
+		return true
+	}
+
+	hosts, err := v.resolver.LookupHost(ctx, domain+".")
+	if err != nil && !isNotFound(err) {
+		log.Printf("Error looking up address records of %s: %v", domain, err)
+		return true
+	}
+	return len(hosts) > 0
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
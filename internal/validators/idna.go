@@ -0,0 +1,28 @@
+package validators
+
+import (
+	"errors"
+
+	"golang.org/x/net/idna"
+)
+
+var errInvalidDomain = errors.New("invalid domain")
+
+// domainProfile maps internationalized domains the way browsers and mail
+// clients look them up under IDNA2008 (UTS #46), and checks the result is a
+// valid host name.
+var domainProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// toASCII converts an internationalized domain to its ASCII form, encoding
+// every label with non-ASCII characters as "xn--" punycode.
+func toASCII(domain string) (string, error) {
+	ascii, err := domainProfile.ToASCII(domain)
+	if err != nil {
+		return "", errInvalidDomain
+	}
+	return ascii, nil
+}
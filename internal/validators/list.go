@@ -0,0 +1,11 @@
+package validators
+
+import "regexp"
+
+var listID = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ValidListID reports whether id is lowercase letters and digits separated
+// by single dashes, so it can be used in /lists/{listID} URLs as is.
+func ValidListID(id string) bool {
+	return listID.MatchString(id)
+}
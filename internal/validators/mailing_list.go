@@ -2,45 +2,74 @@ package validators
 
 import (
 	"backend-go/internal/dto"
-	"errors"
-	"regexp"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
-type MailingListValidator struct{}
+// MaxUsernameLength is the longest username accepted, in characters.
+const MaxUsernameLength = 64
 
-func NewMailingListValidator() *MailingListValidator {
-	return &MailingListValidator{}
+// Codes of the reasons a username is rejected.
+const (
+	UsernameRequired = "username.required"
+	UsernameTooLong  = "username.too_long"
+)
+
+type MailingListValidator struct {
+	emails *EmailValidator
 }
 
-func (m *MailingListValidator) Validate(mailingList *dto.MailingList) error {
-	if err := m.validateEmail(mailingList.Email); err != nil {
-		return err
-	}
+// MailingListValidatorOption customizes a MailingListValidator.
+type MailingListValidatorOption func(*MailingListValidator)
 
-	if err := m.validateUsername(mailingList.Username); err != nil {
-		return err
+// WithEmailValidator sets the validator for email addresses. The default
+// one does no DNS lookups.
+func WithEmailValidator(emails *EmailValidator) MailingListValidatorOption {
+	return func(m *MailingListValidator) {
+		m.emails = emails
 	}
+}
 
-	return nil
+func NewMailingListValidator(opts ...MailingListValidatorOption) *MailingListValidator {
+	m := &MailingListValidator{emails: NewEmailValidator()}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
-func (m *MailingListValidator) validateEmail(email string) error {
-	if email == "" {
-		return errors.New("email is required")
+// Validate checks every field and returns all errors found, in field order.
+func (m *MailingListValidator) Validate(mailingList *dto.MailingList) []dto.FieldError {
+	var fieldErrors []dto.FieldError
+
+	if err := m.emails.Validate(context.Background(), mailingList.Email); err != nil {
+		fieldErrors = append(fieldErrors, *err)
 	}
 
-	re := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	if err := validateUsername(mailingList.Username); err != nil {
+		fieldErrors = append(fieldErrors, *err)
+	}
 
-	if !re.MatchString(email) {
-		return errors.New("invalid email format")
+	if err := ValidateTopics(mailingList.Topics); err != nil {
+		fieldErrors = append(fieldErrors, *err)
 	}
 
-	return nil
+	return fieldErrors
 }
 
-func (m *MailingListValidator) validateUsername(username string) error {
-	if username == "" {
-		return errors.New("username is required")
+func validateUsername(username string) *dto.FieldError {
+	if strings.TrimSpace(username) == "" {
+		return &dto.FieldError{Field: "username", Code: UsernameRequired, Message: "username is required"}
+	}
+
+	if utf8.RuneCountInString(username) > MaxUsernameLength {
+		return &dto.FieldError{
+			Field:   "username",
+			Code:    UsernameTooLong,
+			Message: fmt.Sprintf("username must be at most %d characters", MaxUsernameLength),
+		}
 	}
 
 	return nil
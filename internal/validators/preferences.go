@@ -0,0 +1,71 @@
+package validators
+
+import (
+	"backend-go/internal/dto"
+	"fmt"
+	"regexp"
+)
+
+// Codes of the reasons a preferences update is rejected.
+const (
+	FrequencyInvalid = "frequency.invalid"
+	TopicsInvalid    = "topics.invalid"
+	TopicsUnknown    = "topics.unknown"
+)
+
+var topicSlug = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ValidTopicSlug reports whether slug is lowercase letters and digits
+// separated by single dashes, such as "go" or "self-hosting".
+func ValidTopicSlug(slug string) bool {
+	return topicSlug.MatchString(slug)
+}
+
+// ValidFrequency reports whether frequency is one of the dto.Frequency
+// values.
+func ValidFrequency(frequency string) bool {
+	switch frequency {
+	case dto.FrequencyImmediate, dto.FrequencyWeekly, dto.FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// ValidateTopics checks that every slug is well-formed. Whether the topics
+// exist is left to the repository.
+func ValidateTopics(slugs []string) *dto.FieldError {
+	for _, slug := range slugs {
+		if !ValidTopicSlug(slug) {
+			return &dto.FieldError{Field: "topics", Code: TopicsInvalid, Message: fmt.Sprintf("invalid topic %q", slug)}
+		}
+	}
+	return nil
+}
+
+// ValidatePreferences checks the fields set in update and returns all errors
+// found, in field order.
+func ValidatePreferences(update dto.PreferencesUpdate) []dto.FieldError {
+	var fieldErrors []dto.FieldError
+
+	if update.Username != nil {
+		if err := validateUsername(*update.Username); err != nil {
+			fieldErrors = append(fieldErrors, *err)
+		}
+	}
+
+	if update.Frequency != nil && !ValidFrequency(*update.Frequency) {
+		fieldErrors = append(fieldErrors, dto.FieldError{
+			Field:   "frequency",
+			Code:    FrequencyInvalid,
+			Message: "frequency must be immediate, weekly or monthly",
+		})
+	}
+
+	if update.Topics != nil {
+		if err := ValidateTopics(*update.Topics); err != nil {
+			fieldErrors = append(fieldErrors, *err)
+		}
+	}
+
+	return fieldErrors
+}
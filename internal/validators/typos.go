@@ -0,0 +1,122 @@
+package validators
+
+import "strings"
+
+// commonDomains are the mailbox providers most subscribers use. Domains
+// that are a typo away from one of them get a suggestion.
+var commonDomains = []string{
+	"aol.com",
+	"comcast.net",
+	"fastmail.com",
+	"gmail.com",
+	"gmx.com",
+	"gmx.de",
+	"googlemail.com",
+	"hey.com",
+	"hotmail.co.uk",
+	"hotmail.com",
+	"icloud.com",
+	"live.com",
+	"mac.com",
+	"mail.com",
+	"mail.ru",
+	"me.com",
+	"msn.com",
+	"outlook.com",
+	"pm.me",
+	"proton.me",
+	"protonmail.com",
+	"web.de",
+	"yahoo.co.uk",
+	"yahoo.com",
+	"yandex.ru",
+	"zoho.com",
+}
+
+// knownDomains are real mailbox providers that are a typo away from one of
+// commonDomains, such as Yahoo's "ymail.com" from "gmail.com". They are
+// never flagged.
+var knownDomains = map[string]bool{
+	"email.com": true,
+	"ymail.com": true,
+}
+
+// suggestDomain returns the common domain that domain, given in ASCII form,
+// is most likely a typo of, or "" when it doesn't look like one. Either the
+// name is misspelled with the right TLD ("gmial.com") or the name is right
+// and the TLD is misspelled ("gmail.con"). Short names are only compared
+// exactly, since "me.com" and "mac.com" are both real, and knownDomains are
+// never flagged.
+func suggestDomain(domain string) string {
+	if knownDomains[domain] {
+		return ""
+	}
+	name, tld := splitTLD(domain)
+
+	best, bestDistance := "", 3
+	for _, common := range commonDomains {
+		if domain == common {
+			return ""
+		}
+
+		commonName, commonTLD := splitTLD(common)
+		distance := bestDistance
+		switch {
+		case name == commonName:
+			if d := editDistance(tld, commonTLD); d == 1 {
+				distance = d
+			}
+		case tld == commonTLD && len(commonName) >= 5:
+			maxDistance := 1
+			if len(commonName) >= 6 {
+				maxDistance = 2
+			}
+			if d := editDistance(name, commonName); d <= maxDistance {
+				distance = d
+			}
+		}
+
+		if distance < bestDistance {
+			best, bestDistance = common, distance
+		}
+	}
+
+	return best
+}
+
+func splitTLD(domain string) (string, string) {
+	i := strings.LastIndexByte(domain, '.')
+	if i < 0 {
+		return domain, ""
+	}
+	return domain[:i], domain[i+1:]
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn one into the other.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
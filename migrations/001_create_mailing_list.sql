@@ -11,3 +11,6 @@ CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_email ON mailing_list(email);
 
 -- Create index on created_at for sorting
 CREATE INDEX IF NOT EXISTS idx_mailing_list_created_at ON mailing_list(created_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS mailing_list;
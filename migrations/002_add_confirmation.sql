@@ -0,0 +1,16 @@
+-- Add double opt-in state to mailing_list.
+-- Existing rows predate confirmation and are treated as active.
+ALTER TABLE mailing_list ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE mailing_list ADD COLUMN confirmation_token TEXT;
+ALTER TABLE mailing_list ADD COLUMN confirmation_expires_at DATETIME;
+ALTER TABLE mailing_list ADD COLUMN confirmed_at DATETIME;
+
+-- Create index on confirmation_token for confirmation lookups
+CREATE INDEX IF NOT EXISTS idx_mailing_list_confirmation_token ON mailing_list(confirmation_token);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_mailing_list_confirmation_token;
+ALTER TABLE mailing_list DROP COLUMN confirmed_at;
+ALTER TABLE mailing_list DROP COLUMN confirmation_expires_at;
+ALTER TABLE mailing_list DROP COLUMN confirmation_token;
+ALTER TABLE mailing_list DROP COLUMN status;
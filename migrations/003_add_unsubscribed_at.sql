@@ -0,0 +1,5 @@
+-- Record when a subscriber left the mailing list
+ALTER TABLE mailing_list ADD COLUMN unsubscribed_at DATETIME;
+
+-- migrate:down
+ALTER TABLE mailing_list DROP COLUMN unsubscribed_at;
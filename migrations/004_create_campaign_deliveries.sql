@@ -0,0 +1,14 @@
+-- Per-recipient delivery log for newsletter campaigns sent with blogctl send
+CREATE TABLE IF NOT EXISTS campaign_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign TEXT NOT NULL,
+    email TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    updated_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    UNIQUE (campaign, email)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS campaign_deliveries;
@@ -0,0 +1,20 @@
+-- Blog posts from the Hugo feed that were already announced to subscribers
+CREATE TABLE IF NOT EXISTS announced_posts (
+    guid TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    link TEXT NOT NULL,
+    published_at DATETIME,
+    recipients INTEGER NOT NULL DEFAULT 0,
+    announced_at DATETIME NOT NULL
+);
+
+-- Holds a single row once the watcher has recorded the posts the feed held
+-- when it first ran, which are never announced
+CREATE TABLE IF NOT EXISTS feed_watcher (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    initialized_at DATETIME NOT NULL
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS feed_watcher;
+DROP TABLE IF EXISTS announced_posts;
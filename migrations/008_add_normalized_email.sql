@@ -0,0 +1,13 @@
+-- Case-insensitive key deciding whether two emails are the same subscriber.
+-- Rows whose key collides stay NULL until `blogctl subscribers dedupe`
+-- merges them.
+ALTER TABLE mailing_list ADD COLUMN normalized_email TEXT;
+
+UPDATE mailing_list SET normalized_email = lower(trim(email))
+WHERE id IN (SELECT min(id) FROM mailing_list GROUP BY lower(trim(email)));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_normalized_email ON mailing_list(normalized_email);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_mailing_list_normalized_email;
+ALTER TABLE mailing_list DROP COLUMN normalized_email;
@@ -0,0 +1,16 @@
+-- Audit log of data subject access and erasure requests. Addresses are kept
+-- only as a SHA-256 hash, so the log outlives the data it was about.
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    email_hash TEXT NOT NULL,
+    status TEXT NOT NULL,
+    details TEXT,
+    requested_at DATETIME NOT NULL,
+    completed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_email_hash ON privacy_requests(email_hash);
+
+-- migrate:down
+DROP TABLE IF EXISTS privacy_requests;
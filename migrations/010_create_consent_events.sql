@@ -0,0 +1,27 @@
+-- Append-only ledger of how and when subscribers opted in and out, kept as
+-- evidence of consent. Rows are never updated; they are only deleted when the
+-- subscriber's data is erased.
+CREATE TABLE IF NOT EXISTS consent_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL,
+    normalized_email TEXT NOT NULL,
+    event TEXT NOT NULL,
+    source TEXT NOT NULL,
+    ip_hash TEXT,
+    user_agent TEXT,
+    page_url TEXT,
+    policy_version TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_consent_events_normalized_email ON consent_events(normalized_email);
+CREATE INDEX IF NOT EXISTS idx_consent_events_email ON consent_events(email);
+
+CREATE TRIGGER IF NOT EXISTS consent_events_append_only
+BEFORE UPDATE ON consent_events
+BEGIN
+    SELECT RAISE(ABORT, 'consent events are append-only');
+END;
+
+-- migrate:down
+DROP TABLE IF EXISTS consent_events;
@@ -0,0 +1,25 @@
+-- Topics subscribers can choose, such as one per blog category. Subscribers
+-- without any topic receive everything.
+CREATE TABLE IF NOT EXISTS topics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subscriber_topics (
+    subscriber_id INTEGER NOT NULL REFERENCES mailing_list(id) ON DELETE CASCADE,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    PRIMARY KEY (subscriber_id, topic_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriber_topics_topic_id ON subscriber_topics(topic_id);
+
+-- How often a subscriber wants to hear from the blog
+ALTER TABLE mailing_list ADD COLUMN frequency TEXT NOT NULL DEFAULT 'immediate';
+
+-- migrate:down
+DROP TABLE IF EXISTS subscriber_topics;
+DROP TABLE IF EXISTS topics;
+ALTER TABLE mailing_list DROP COLUMN frequency;
@@ -0,0 +1,142 @@
+-- Mailing lists served by one deployment, each with its own sender, allowed
+-- origins and templates. Empty columns fall back to MAIL_FROM, the built-in
+-- origins and TEMPLATE_DIR. Existing subscribers move to the default list.
+CREATE TABLE IF NOT EXISTS lists (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    mail_from TEXT,
+    allowed_origins TEXT,
+    template_dir TEXT,
+    created_at DATETIME NOT NULL
+);
+
+INSERT INTO lists (id, name, created_at) VALUES ('default', 'zhisme.com', CURRENT_TIMESTAMP);
+
+-- SQLite can't drop the UNIQUE constraint on email, so mailing_list is
+-- rebuilt with addresses unique per list. Dropping the old table cascades to
+-- subscriber_topics, which is restored afterwards.
+CREATE TEMP TABLE subscriber_topics_backup AS SELECT * FROM subscriber_topics;
+
+CREATE TABLE mailing_list_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    list_id TEXT NOT NULL DEFAULT 'default' REFERENCES lists(id),
+    username TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status TEXT NOT NULL DEFAULT 'active',
+    confirmation_token TEXT,
+    confirmation_expires_at DATETIME,
+    confirmed_at DATETIME,
+    unsubscribed_at DATETIME,
+    normalized_email TEXT,
+    frequency TEXT NOT NULL DEFAULT 'immediate'
+);
+
+INSERT INTO mailing_list_new (id, username, email, created_at, status, confirmation_token, confirmation_expires_at, confirmed_at, unsubscribed_at, normalized_email, frequency)
+SELECT id, username, email, created_at, status, confirmation_token, confirmation_expires_at, confirmed_at, unsubscribed_at, normalized_email, frequency
+FROM mailing_list;
+
+DROP TABLE mailing_list;
+ALTER TABLE mailing_list_new RENAME TO mailing_list;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_email ON mailing_list(list_id, email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_normalized_email ON mailing_list(list_id, normalized_email);
+CREATE INDEX IF NOT EXISTS idx_mailing_list_created_at ON mailing_list(list_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_mailing_list_confirmation_token ON mailing_list(confirmation_token);
+
+INSERT INTO subscriber_topics SELECT * FROM subscriber_topics_backup;
+DROP TABLE subscriber_topics_backup;
+
+-- Which list a consent event was given for
+ALTER TABLE consent_events ADD COLUMN list_id TEXT NOT NULL DEFAULT 'default';
+
+-- Campaigns are sent to one list, and an address on several lists gets each
+-- list's issue, so deliveries are unique per list, campaign and address.
+-- The UNIQUE constraint can't be changed in place either.
+CREATE TABLE campaign_deliveries_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    list_id TEXT NOT NULL DEFAULT 'default',
+    campaign TEXT NOT NULL,
+    email TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    updated_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    UNIQUE (list_id, campaign, email)
+);
+
+INSERT INTO campaign_deliveries_new (id, campaign, email, status, error, updated_at, sent_at)
+SELECT id, campaign, email, status, error, updated_at, sent_at
+FROM campaign_deliveries;
+
+DROP TABLE campaign_deliveries;
+ALTER TABLE campaign_deliveries_new RENAME TO campaign_deliveries;
+
+-- migrate:down
+-- Only the default list fits the old schema. Rolling back while other lists
+-- have subscribers would delete them, so it is refused until they are
+-- removed or exported.
+CREATE TEMP TABLE rollback_guard (checked INTEGER);
+CREATE TEMP TRIGGER rollback_guard_other_lists
+BEFORE INSERT ON rollback_guard
+WHEN EXISTS (SELECT 1 FROM mailing_list WHERE list_id != 'default')
+BEGIN
+    SELECT RAISE(ABORT, 'lists other than the default one still have subscribers; remove them before rolling back');
+END;
+INSERT INTO rollback_guard VALUES (1);
+DROP TABLE rollback_guard;
+
+ALTER TABLE consent_events DROP COLUMN list_id;
+
+-- Deliveries of other lists keep their list in the campaign name, so they
+-- can't collide with the default list's
+CREATE TABLE campaign_deliveries_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign TEXT NOT NULL,
+    email TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    updated_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    UNIQUE (campaign, email)
+);
+
+INSERT INTO campaign_deliveries_old (id, campaign, email, status, error, updated_at, sent_at)
+SELECT id, CASE WHEN list_id = 'default' THEN campaign ELSE list_id || ':' || campaign END, email, status, error, updated_at, sent_at
+FROM campaign_deliveries;
+
+DROP TABLE campaign_deliveries;
+ALTER TABLE campaign_deliveries_old RENAME TO campaign_deliveries;
+
+CREATE TEMP TABLE subscriber_topics_backup AS SELECT * FROM subscriber_topics;
+
+CREATE TABLE mailing_list_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL,
+    email TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status TEXT NOT NULL DEFAULT 'active',
+    confirmation_token TEXT,
+    confirmation_expires_at DATETIME,
+    confirmed_at DATETIME,
+    unsubscribed_at DATETIME,
+    normalized_email TEXT,
+    frequency TEXT NOT NULL DEFAULT 'immediate'
+);
+
+INSERT INTO mailing_list_old (id, username, email, created_at, status, confirmation_token, confirmation_expires_at, confirmed_at, unsubscribed_at, normalized_email, frequency)
+SELECT id, username, email, created_at, status, confirmation_token, confirmation_expires_at, confirmed_at, unsubscribed_at, normalized_email, frequency
+FROM mailing_list;
+
+DROP TABLE mailing_list;
+ALTER TABLE mailing_list_old RENAME TO mailing_list;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_email ON mailing_list(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mailing_list_normalized_email ON mailing_list(normalized_email);
+CREATE INDEX IF NOT EXISTS idx_mailing_list_created_at ON mailing_list(created_at);
+CREATE INDEX IF NOT EXISTS idx_mailing_list_confirmation_token ON mailing_list(confirmation_token);
+
+INSERT INTO subscriber_topics SELECT * FROM subscriber_topics_backup;
+DROP TABLE subscriber_topics_backup;
+
+DROP TABLE IF EXISTS lists;
@@ -3,7 +3,12 @@ package main_test
 import (
 	"backend-go/internal/api"
 	"backend-go/internal/repositories"
+	"errors"
 	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -48,3 +53,26 @@ func TestMainServerIntegration(t *testing.T) {
 		t.Errorf("Expected status %d for GET on POST endpoint, got %d", http.StatusMethodNotAllowed, resp.StatusCode)
 	}
 }
+
+func TestMainRequiresSigningSecret(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "api")
+	if output, err := exec.Command("go", "build", "-o", binary, "backend-go/cmd/api").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build the API: %v\n%s", err, output)
+	}
+
+	cmd := exec.Command(binary)
+	cmd.Env = append(os.Environ(), "SIGNING_SECRET=", "MAIL_TRANSPORT=smtp", "DB_PATH="+filepath.Join(dir, "blog.db"))
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit code 1, got %v. Output: %s", err, output)
+	}
+	if !strings.Contains(string(output), "SIGNING_SECRET must be set") {
+		t.Errorf("Expected the missing secret to be reported, got %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "blog.db")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected startup to fail before opening the database, got %v", err)
+	}
+}
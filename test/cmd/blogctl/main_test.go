@@ -0,0 +1,81 @@
+package main_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// blogctlPath is the binary built once for every test.
+var blogctlPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "blogctl")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create build directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	blogctlPath = filepath.Join(dir, "blogctl")
+	build := exec.Command("go", "build", "-o", blogctlPath, "backend-go/cmd/blogctl")
+	if output, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build blogctl: %v\n%s", err, output)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type result struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// blogctl runs the binary against the database at dbPath.
+func blogctl(t *testing.T, dbPath string, args ...string) result {
+	t.Helper()
+
+	cmd := exec.Command(blogctlPath, append([]string{"-db", dbPath}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("Failed to run blogctl: %v", err)
+	}
+	return result{stdout: stdout.String(), stderr: stderr.String(), code: cmd.ProcessState.ExitCode()}
+}
+
+func TestBlogctl(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "blog.db")
+
+	t.Run("Unknown commands are usage errors", func(t *testing.T) {
+		res := blogctl(t, dbPath, "frobnicate")
+		if res.code != 2 {
+			t.Errorf("Expected exit code 2, got %d", res.code)
+		}
+		if !strings.Contains(res.stderr, `unknown command "frobnicate"`) || !strings.Contains(res.stderr, "Usage: blogctl") {
+			t.Errorf("Expected the error and the usage, got %q", res.stderr)
+		}
+	})
+
+	t.Run("Errors are written as JSON with -json", func(t *testing.T) {
+		res := blogctl(t, dbPath, "-json", "subscribers")
+		if res.code != 2 {
+			t.Errorf("Expected exit code 2, got %d", res.code)
+		}
+		if !strings.HasPrefix(res.stderr, `{"error":{"message":`) {
+			t.Errorf("Expected a JSON error, got %q", res.stderr)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package main_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubscribersList(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "blog.db")
+
+	t.Run("Invalid flags are usage errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"subscribers", "list", "-status", "gone"},
+			{"subscribers", "list", "-limit", "-1"},
+			{"subscribers", "list", "-nope"},
+		} {
+			if res := blogctl(t, dbPath, args...); res.code != 2 {
+				t.Errorf("%v: expected exit code 2, got %d. Stderr: %s", args, res.code, res.stderr)
+			}
+		}
+	})
+
+	t.Run("Lists subscribers newest first up to the limit", func(t *testing.T) {
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+			if res := blogctl(t, dbPath, "subscribers", "add", email); res.code != 0 {
+				t.Fatalf("Failed to add %s: %s", email, res.stderr)
+			}
+		}
+
+		res := blogctl(t, dbPath, "-json", "subscribers", "list", "-limit", "2")
+		if res.code != 0 {
+			t.Fatalf("Expected exit code 0, got %d. Stderr: %s", res.code, res.stderr)
+		}
+		var subscribers []dto.MailingList
+		if err := json.Unmarshal([]byte(res.stdout), &subscribers); err != nil {
+			t.Fatalf("Failed to parse output %q: %v", res.stdout, err)
+		}
+		if len(subscribers) != 2 {
+			t.Errorf("Expected 2 subscribers, got %+v", subscribers)
+		}
+	})
+}
+
+func TestSubscribersAdd(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "blog.db")
+
+	res := blogctl(t, dbPath, "subscribers", "add", "-username", "Jane", "Jane@Example.com")
+	if res.code != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", res.code, res.stderr)
+	}
+	if res.stdout != "Subscribed Jane@example.com\n" {
+		t.Errorf("Unexpected output %q", res.stdout)
+	}
+
+	repo, err := repositories.NewSqliteMailingListRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	subscribers, err := repo.ListActive(dto.DefaultListID)
+	if err != nil {
+		t.Fatalf("Failed to list subscribers: %v", err)
+	}
+	if len(subscribers) != 1 || subscribers[0].Username != "Jane" {
+		t.Errorf("Expected Jane to be active, got %+v", subscribers)
+	}
+
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to open consent ledger: %v", err)
+	}
+	events, err := ledger.ListEvents("Jane@example.com", "jane@example.com")
+	if err != nil {
+		t.Fatalf("Failed to list consent events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected one consent event, got %+v", events)
+	}
+	if events[0].Event != dto.ConsentSubscribe || events[0].Source != dto.ConsentSourceAdmin || events[0].ListID != dto.DefaultListID {
+		t.Errorf("Expected an admin subscribe to the default list, got %+v", events[0])
+	}
+
+	t.Run("Invalid addresses are rejected", func(t *testing.T) {
+		if res := blogctl(t, dbPath, "subscribers", "add", "not-an-email"); res.code != 1 {
+			t.Errorf("Expected exit code 1, got %d", res.code)
+		}
+	})
+}
+
+func TestSubscribersDedupe(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "blog.db")
+
+	// Without folding the two Gmail spellings are different subscribers
+	for _, email := range []string{"jane.doe@gmail.com", "janedoe+news@gmail.com"} {
+		if res := blogctl(t, dbPath, "subscribers", "add", "-fold-aliases=false", email); res.code != 0 {
+			t.Fatalf("Failed to add %s: %s", email, res.stderr)
+		}
+	}
+
+	countSubscribers := func(t *testing.T) int {
+		t.Helper()
+		repo, err := repositories.NewSqliteMailingListRepository(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer func() { _ = repo.Close() }()
+
+		subscribers, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		return len(subscribers)
+	}
+
+	t.Run("Dry run reports duplicates without merging them", func(t *testing.T) {
+		res := blogctl(t, dbPath, "-json", "subscribers", "dedupe", "-dry-run", "-fold-aliases")
+		if res.code != 0 {
+			t.Fatalf("Expected exit code 0, got %d. Stderr: %s", res.code, res.stderr)
+		}
+		var groups []dto.DuplicateGroup
+		if err := json.Unmarshal([]byte(res.stdout), &groups); err != nil {
+			t.Fatalf("Failed to parse output %q: %v", res.stdout, err)
+		}
+		if len(groups) != 1 || groups[0].Kept != "jane.doe@gmail.com" || len(groups[0].Merged) != 1 {
+			t.Errorf("Expected one group keeping the oldest address, got %+v", groups)
+		}
+
+		if count := countSubscribers(t); count != 2 {
+			t.Errorf("Expected both subscribers to be kept, got %d", count)
+		}
+	})
+
+	t.Run("Duplicates are merged without -dry-run", func(t *testing.T) {
+		if res := blogctl(t, dbPath, "subscribers", "dedupe", "-fold-aliases"); res.code != 0 {
+			t.Fatalf("Expected exit code 0, got %d. Stderr: %s", res.code, res.stderr)
+		}
+		if count := countSubscribers(t); count != 1 {
+			t.Errorf("Expected the duplicates to be merged, got %d subscribers", count)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package antispam_test
+
+import (
+	"backend-go/internal/antispam"
+	"backend-go/internal/tokens"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecks(t *testing.T) {
+	forms := antispam.NewForms(tokens.NewSigner([]byte("secret")), 3*time.Second, time.Hour, 8)
+	issued := forms.Issue(time.Now())
+
+	t.Run("Honeypot rejects filled in fields", func(t *testing.T) {
+		check := antispam.Honeypot()
+		if err := check(antispam.Submission{}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if err := check(antispam.Submission{Honeypot: "https://spam.example"}); !errors.Is(err, antispam.ErrTrapped) || !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrTrapped, got %v", err)
+		}
+	})
+
+	t.Run("MinimumAge rejects submissions that arrive too fast", func(t *testing.T) {
+		check := antispam.MinimumAge(forms)
+
+		err := check(antispam.Submission{FormToken: issued.Token, ReceivedAt: time.Now().Add(time.Second)})
+		if !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrRejected, got %v", err)
+		}
+
+		err = check(antispam.Submission{FormToken: issued.Token, ReceivedAt: time.Now().Add(5 * time.Second)})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("MinimumAge rejects missing and forged tokens", func(t *testing.T) {
+		check := antispam.MinimumAge(forms)
+		other := antispam.NewForms(tokens.NewSigner([]byte("other")), 0, time.Hour, 0).Issue(time.Now().Add(-time.Minute))
+
+		for _, token := range []string{"", "garbage", other.Token} {
+			err := check(antispam.Submission{FormToken: token, ReceivedAt: time.Now()})
+			if !errors.Is(err, antispam.ErrRejected) {
+				t.Errorf("%q: expected ErrRejected, got %v", token, err)
+			}
+		}
+	})
+
+	t.Run("ProofOfWork requires a solved challenge", func(t *testing.T) {
+		check := antispam.ProofOfWork(forms)
+		if issued.Difficulty != 8 {
+			t.Fatalf("Expected difficulty 8, got %d", issued.Difficulty)
+		}
+
+		nonce := antispam.Solve(issued.Token, issued.Difficulty)
+		if err := check(antispam.Submission{FormToken: issued.Token, Nonce: nonce}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+
+		bad := "x"
+		for antispam.Solves(issued.Token, bad, issued.Difficulty) {
+			bad += "x"
+		}
+		if err := check(antispam.Submission{FormToken: issued.Token, Nonce: bad}); !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrRejected, got %v", err)
+		}
+	})
+
+	t.Run("SingleUse rejects tokens that were already submitted", func(t *testing.T) {
+		check := antispam.SingleUse(forms)
+		token := forms.Issue(time.Now())
+		if other := forms.Issue(time.UnixMilli(time.Now().UnixMilli())); other.Token == token.Token {
+			t.Fatal("Expected every issued token to be different")
+		}
+
+		if err := check(antispam.Submission{FormToken: token.Token, ReceivedAt: time.Now()}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := check(antispam.Submission{FormToken: token.Token, ReceivedAt: time.Now()}); !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrRejected for a replayed token, got %v", err)
+		}
+		if err := check(antispam.Submission{FormToken: "garbage", ReceivedAt: time.Now()}); !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrRejected for a forged token, got %v", err)
+		}
+	})
+
+	t.Run("Difficulty is capped", func(t *testing.T) {
+		forms := antispam.NewForms(tokens.NewSigner([]byte("secret")), 0, time.Hour, 100)
+		if forms.Difficulty != antispam.MaxDifficulty {
+			t.Errorf("Expected difficulty %d, got %d", antispam.MaxDifficulty, forms.Difficulty)
+		}
+	})
+
+	t.Run("Run stops at the first rejection", func(t *testing.T) {
+		var ran []string
+		step := func(name string, err error) antispam.Check {
+			return func(antispam.Submission) error {
+				ran = append(ran, name)
+				return err
+			}
+		}
+
+		err := antispam.Run([]antispam.Check{step("a", nil), step("b", antispam.ErrRejected), step("c", nil)}, antispam.Submission{})
+		if !errors.Is(err, antispam.ErrRejected) {
+			t.Errorf("Expected ErrRejected, got %v", err)
+		}
+		if len(ran) != 2 {
+			t.Errorf("Expected 2 checks to run, got %v", ran)
+		}
+	})
+}
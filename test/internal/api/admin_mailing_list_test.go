@@ -0,0 +1,179 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/api"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+)
+
+func TestAdminListMailingList(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		ml := &dto.MailingList{
+			Username:  fmt.Sprintf("reader%d", i),
+			Email:     fmt.Sprintf("reader%d@example.com", i),
+			CreatedAt: time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+	}
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+	manager := apikeys.NewManager(keys)
+	token, _, err := manager.Create("test", []string{dto.ScopeSubscribersRead})
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+
+	srv := api.NewApiServer(repo, api.WithAPIKeys(manager))
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Requests without a valid api key are rejected", func(t *testing.T) {
+		for _, token := range []string{"", "wrong-token", token + "x"} {
+			w := get("/admin/mailing_list", token)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+			}
+			if w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header")
+			}
+		}
+	})
+
+	t.Run("Lists subscribers page by page", func(t *testing.T) {
+		w := get("/admin/mailing_list?limit=2", token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var page dto.SubscriberPage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(page.Subscribers) != 2 || page.Subscribers[0].Email != "reader2@example.com" {
+			t.Fatalf("Unexpected first page: %+v", page.Subscribers)
+		}
+		if page.NextCursor == "" {
+			t.Fatal("Expected a next cursor")
+		}
+
+		w = get("/admin/mailing_list?limit=2&cursor="+page.NextCursor, token)
+		var next dto.SubscriberPage
+		if err := json.Unmarshal(w.Body.Bytes(), &next); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(next.Subscribers) != 1 || next.Subscribers[0].Email != "reader0@example.com" || next.NextCursor != "" {
+			t.Errorf("Unexpected last page: %+v", next)
+		}
+	})
+
+	t.Run("Invalid parameters return 400 Bad Request", func(t *testing.T) {
+		for _, path := range []string{"/admin/mailing_list?status=gone", "/admin/mailing_list?cursor=bogus"} {
+			w := get(path, token)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+
+	t.Run("Exports matching subscribers as a download", func(t *testing.T) {
+		w := get("/admin/mailing_list/export?created_after=2026-01-02", token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+			t.Errorf("Expected CSV, got %s", contentType)
+		}
+		if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="subscribers.csv"` {
+			t.Errorf("Unexpected Content-Disposition: %s", disposition)
+		}
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[1], "reader2,reader2@example.com,") {
+			t.Errorf("Expected a header and 2 subscribers, got %q", lines)
+		}
+
+		w = get("/admin/mailing_list/export?format=ndjson", token)
+		if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 3 {
+			t.Errorf("Expected 3 JSON lines, got %q", lines)
+		}
+	})
+
+	t.Run("Unknown export formats return 400 Bad Request", func(t *testing.T) {
+		w := get("/admin/mailing_list/export?format=xml", token)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Keys without the subscribers:read scope are forbidden", func(t *testing.T) {
+		sender, _, err := manager.Create("sender", []string{dto.ScopeCampaignsSend})
+		if err != nil {
+			t.Fatalf("Failed to create api key: %v", err)
+		}
+
+		for _, path := range []string{"/admin/mailing_list", "/admin/mailing_list/export"} {
+			w := get(path, sender)
+			if w.Code != http.StatusForbidden {
+				t.Errorf("%s: expected status %d, got %d", path, http.StatusForbidden, w.Code)
+			}
+		}
+	})
+
+	t.Run("Revoked keys are rejected", func(t *testing.T) {
+		revoked, key, err := manager.Create("revoked", []string{dto.ScopeSubscribersRead})
+		if err != nil {
+			t.Fatalf("Failed to create api key: %v", err)
+		}
+		if err := manager.Revoke(key.Prefix); err != nil {
+			t.Fatalf("Failed to revoke api key: %v", err)
+		}
+
+		w := get("/admin/mailing_list", revoked)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("Admin endpoints reject everything without api keys configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/mailing_list", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		api.NewApiServer(repo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+}
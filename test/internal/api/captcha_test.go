@@ -0,0 +1,88 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/captcha"
+	"backend-go/internal/repositories"
+)
+
+func TestSignupCaptcha(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	// Fake siteverify API accepting only the "solved" token.
+	siteverify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		success := r.PostFormValue("secret") == "secret" && r.PostFormValue("response") == "solved"
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]bool{"success": success}); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer siteverify.Close()
+
+	subscribe := func(srv *api.Server, payload map[string]string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	verifier := captcha.NewSiteVerifier(siteverify.URL, "secret", nil)
+
+	t.Run("Signups with a valid captcha token are created", func(t *testing.T) {
+		srv := api.NewApiServer(repo, api.WithCaptcha(verifier, true))
+		w := subscribe(srv, map[string]string{"email": "solved@example.com", "username": "human", "captchaToken": "solved"})
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Invalid captcha tokens return 400 Bad Request", func(t *testing.T) {
+		srv := api.NewApiServer(repo, api.WithCaptcha(verifier, false))
+		w := subscribe(srv, map[string]string{"email": "bot@example.com", "username": "bot", "captchaToken": "guess"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Missing tokens are only rejected when required", func(t *testing.T) {
+		payload := map[string]string{"email": "notoken@example.com", "username": "reader"}
+
+		required := api.NewApiServer(repo, api.WithCaptcha(verifier, true))
+		if w := subscribe(required, payload); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+
+		optional := api.NewApiServer(repo, api.WithCaptcha(verifier, false))
+		if w := subscribe(optional, payload); w.Code != http.StatusCreated {
+			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("Provider outages return 503 Service Unavailable", func(t *testing.T) {
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "down", http.StatusInternalServerError)
+		}))
+		defer down.Close()
+
+		srv := api.NewApiServer(repo, api.WithCaptcha(captcha.NewSiteVerifier(down.URL, "secret", nil), true))
+		w := subscribe(srv, map[string]string{"email": "outage@example.com", "username": "reader", "captchaToken": "solved"})
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+// linkFromEmail returns the first URL in the email body that starts with prefix.
+func linkFromEmail(t *testing.T, email dto.Email, prefix string) *url.URL {
+	t.Helper()
+	for _, field := range strings.Fields(email.TextBody) {
+		if strings.HasPrefix(field, prefix) {
+			link, err := url.Parse(field)
+			if err != nil {
+				t.Fatalf("Failed to parse link %q: %v", field, err)
+			}
+			return link
+		}
+	}
+	t.Fatalf("No link with prefix %q in email body: %s", prefix, email.TextBody)
+	return nil
+}
+
+func TestConfirmMailingList(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	mailer := mailers.NewMemoryMailer()
+	signer := tokens.NewSigner([]byte("test-secret"))
+	srv := api.NewApiServer(repo,
+		api.WithMailer(mailer),
+		api.WithSigner(signer),
+		api.WithEmailBuilder(emails.NewBuilder("blog@example.com", "https://api.example.com", signer)),
+	)
+
+	t.Run("Signup sends confirmation email and link activates subscriber", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"email":"confirm@example.com","username":"confirm"}`)
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", body)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+
+		var created map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if created["status"] != dto.StatusPending {
+			t.Errorf("Expected status %s, got %v", dto.StatusPending, created["status"])
+		}
+		if strings.Contains(w.Body.String(), "token") {
+			t.Errorf("Response must not leak the confirmation token: %s", w.Body.String())
+		}
+
+		sent := mailer.Sent()
+		if len(sent) != 1 {
+			t.Fatalf("Expected 1 confirmation email, got %d", len(sent))
+		}
+		email := sent[0]
+		if email.To != "confirm@example.com" {
+			t.Errorf("Expected email to confirm@example.com, got %s", email.To)
+		}
+
+		link := linkFromEmail(t, email, "https://api.example.com/mailing_list/confirm")
+		req = httptest.NewRequest(http.MethodGet, link.RequestURI(), nil)
+		w = httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var confirmed map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &confirmed); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if confirmed["status"] != dto.StatusActive {
+			t.Errorf("Expected status %s, got %v", dto.StatusActive, confirmed["status"])
+		}
+
+		sent = mailer.Sent()
+		if len(sent) != 2 {
+			t.Fatalf("Expected a welcome email after confirmation, got %d emails", len(sent))
+		}
+		if sent[1].To != "confirm@example.com" || !strings.Contains(sent[1].Subject, "Welcome") {
+			t.Errorf("Expected welcome email to confirm@example.com, got %q to %s", sent[1].Subject, sent[1].To)
+		}
+	})
+
+	t.Run("Signup of a subscribed address is answered like a new signup", func(t *testing.T) {
+		signup := func(email string) map[string]interface{} {
+			body := bytes.NewBufferString(`{"email":"` + email + `","username":"someone else"}`)
+			req := httptest.NewRequest(http.MethodPost, "/mailing_list", body)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			var response map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			return response
+		}
+
+		fresh := signup("fresh@example.com")
+		subscribed := signup("confirm@example.com")
+
+		if subscribed["status"] != dto.StatusPending {
+			t.Errorf("Expected status %s, got %v", dto.StatusPending, subscribed["status"])
+		}
+		if len(subscribed) != len(fresh) {
+			t.Errorf("Expected the same fields as a new signup, got %v and %v", subscribed, fresh)
+		}
+		for field, value := range fresh {
+			if field == "email" || field == "createdAt" {
+				continue
+			}
+			if subscribed[field] != value {
+				t.Errorf("Expected %s %v like a new signup, got %v", field, value, subscribed[field])
+			}
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Failed to list active subscribers: %v", err)
+		}
+		if len(active) != 1 || active[0].Username != "confirm" {
+			t.Errorf("Expected the subscriber to be left alone, got %+v", active)
+		}
+	})
+
+	t.Run("Missing token returns 400 Bad Request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/mailing_list/confirm", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Unknown token returns 404 Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/mailing_list/confirm?token=unknown", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+
+		var response map[string]map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse error response: %v", err)
+		}
+		if response["error"]["message"] != "invalid or expired token" {
+			t.Errorf("Expected 'invalid or expired token' message, got %s", response["error"]["message"])
+		}
+	})
+}
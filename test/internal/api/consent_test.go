@@ -0,0 +1,142 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-go/internal/api"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+func TestConsentLedger(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create consent repository: %v", err)
+	}
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+	manager := apikeys.NewManager(keys)
+	apiKey, _, err := manager.Create("test", []string{dto.ScopeSubscribersRead})
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+
+	mailer := mailers.NewMemoryMailer()
+	signer := tokens.NewSigner([]byte("test-secret"))
+	srv := api.NewApiServer(repo,
+		api.WithMailer(mailer),
+		api.WithSigner(signer),
+		api.WithEmailBuilder(emails.NewBuilder("blog@example.com", "https://api.example.com", signer)),
+		api.WithAPIKeys(manager),
+		api.WithConsentLedger(ledger, "2026-01-01"),
+	)
+
+	signup := func(body, referer string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBufferString(body))
+		req.Header.Set("User-Agent", "Mozilla/5.0 (test)")
+		if referer != "" {
+			req.Header.Set("Referer", referer)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+	consentEvents := func(email string) []dto.ConsentEvent {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/admin/mailing_list/consent?email="+email, nil)
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var events []dto.ConsentEvent
+		if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return events
+	}
+
+	t.Run("Signup, confirmation and unsubscribe are recorded", func(t *testing.T) {
+		signup(`{"email":"reader@example.com","username":"reader","pageUrl":"https://zhisme.com/posts/hello/"}`, "https://zhisme.com/")
+
+		sent := mailer.Sent()
+		if len(sent) != 1 {
+			t.Fatalf("Expected a confirmation email, got %d emails", len(sent))
+		}
+		confirm := linkFromEmail(t, sent[0], "https://api.example.com/mailing_list/confirm")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, confirm.RequestURI(), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+		w = httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/mailing_list/unsubscribe?token="+token, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		events := consentEvents("reader@example.com")
+		if len(events) != 3 {
+			t.Fatalf("Expected 3 events, got %+v", events)
+		}
+		subscribe := events[0]
+		if subscribe.Event != dto.ConsentSubscribe || subscribe.Source != dto.ConsentSourceForm {
+			t.Errorf("Unexpected signup event: %+v", subscribe)
+		}
+		if subscribe.PageURL != "https://zhisme.com/posts/hello/" || subscribe.UserAgent != "Mozilla/5.0 (test)" || subscribe.PolicyVersion != "2026-01-01" {
+			t.Errorf("Expected the signup's page, user agent and policy version, got %+v", subscribe)
+		}
+		if subscribe.IPHash == "" || subscribe.IPHash == "192.0.2.1" {
+			t.Errorf("Expected a hashed client IP, got %q", subscribe.IPHash)
+		}
+		if events[1].Event != dto.ConsentConfirm || events[2].Event != dto.ConsentUnsubscribe || events[2].Source != dto.ConsentSourceEmail {
+			t.Errorf("Unexpected events: %+v", events[1:])
+		}
+	})
+
+	t.Run("Referer is recorded when the form sends no page", func(t *testing.T) {
+		signup(`{"email":"referred@example.com","username":"referred"}`, "https://zhisme.com/about/")
+
+		events := consentEvents("referred@example.com")
+		if len(events) != 1 || events[0].PageURL != "https://zhisme.com/about/" {
+			t.Errorf("Expected the referer as page, got %+v", events)
+		}
+	})
+
+	t.Run("Listing requires an email", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/mailing_list/consent", nil)
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -10,7 +10,9 @@ import (
 	"testing"
 
 	"backend-go/internal/api"
+	"backend-go/internal/dto"
 	"backend-go/internal/repositories"
+	"backend-go/internal/validators"
 )
 
 func TestCreateMailingList(t *testing.T) {
@@ -112,13 +114,16 @@ func TestCreateMailingList(t *testing.T) {
 			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 		}
 
-		var response map[string]map[string]string
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		var problem dto.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
 			t.Fatalf("Failed to parse error response: %v", err)
 		}
 
-		if response["error"]["message"] != "email is required" {
-			t.Errorf("Expected 'email is required' message, got %s", response["error"]["message"])
+		if len(problem.Errors) != 1 || problem.Errors[0].Code != validators.EmailRequired {
+			t.Fatalf("Expected a single %s error, got %+v", validators.EmailRequired, problem.Errors)
+		}
+		if problem.Errors[0].Message != "email is required" {
+			t.Errorf("Expected 'email is required' message, got %s", problem.Errors[0].Message)
 		}
 	})
 
@@ -138,13 +143,16 @@ func TestCreateMailingList(t *testing.T) {
 			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 		}
 
-		var response map[string]map[string]string
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		var problem dto.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
 			t.Fatalf("Failed to parse error response: %v", err)
 		}
 
-		if response["error"]["message"] != "invalid email format" {
-			t.Errorf("Expected 'invalid email format' message, got %s", response["error"]["message"])
+		if len(problem.Errors) != 1 || problem.Errors[0].Code != validators.EmailInvalid {
+			t.Fatalf("Expected a single %s error, got %+v", validators.EmailInvalid, problem.Errors)
+		}
+		if problem.Errors[0].Message != "invalid email format" {
+			t.Errorf("Expected 'invalid email format' message, got %s", problem.Errors[0].Message)
 		}
 	})
 
@@ -163,13 +171,16 @@ func TestCreateMailingList(t *testing.T) {
 			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 		}
 
-		var response map[string]map[string]string
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		var problem dto.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
 			t.Fatalf("Failed to parse error response: %v", err)
 		}
 
-		if response["error"]["message"] != "username is required" {
-			t.Errorf("Expected 'username is required' message, got %s", response["error"]["message"])
+		if len(problem.Errors) != 1 || problem.Errors[0].Code != validators.UsernameRequired {
+			t.Fatalf("Expected a single %s error, got %+v", validators.UsernameRequired, problem.Errors)
+		}
+		if problem.Errors[0].Message != "username is required" {
+			t.Errorf("Expected 'username is required' message, got %s", problem.Errors[0].Message)
 		}
 	})
 
@@ -218,3 +229,79 @@ func TestCreateMailingList(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateMailingListEmailSuggestion(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	srv := api.NewApiServer(repo)
+
+	body, _ := json.Marshal(map[string]string{"email": "reader@gmial.com", "username": "reader"})
+	req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected Content-Type 'application/problem+json', got '%s'", contentType)
+	}
+
+	var problem dto.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to parse error response: %v", err)
+	}
+	if problem.Status != http.StatusBadRequest || problem.Title != "Bad Request" || problem.Type != "about:blank" {
+		t.Errorf("Expected problem details, got %+v", problem)
+	}
+	if len(problem.Errors) != 1 {
+		t.Fatalf("Expected 1 field error, got %+v", problem.Errors)
+	}
+	fieldErr := problem.Errors[0]
+	if fieldErr.Field != "email" || fieldErr.Code != validators.EmailTypo || fieldErr.Suggestion != "reader@gmail.com" {
+		t.Errorf("Expected email.typo with suggestion reader@gmail.com, got %+v", fieldErr)
+	}
+}
+
+func TestCreateMailingListFieldErrors(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	srv := api.NewApiServer(repo)
+
+	body, _ := json.Marshal(map[string]string{"email": "not an email", "username": strings.Repeat("a", 100)})
+	req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var problem dto.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to parse error response: %v", err)
+	}
+	if len(problem.Errors) != 2 {
+		t.Fatalf("Expected every invalid field to be reported, got %+v", problem.Errors)
+	}
+	if problem.Errors[0].Code != validators.EmailInvalid || problem.Errors[1].Code != validators.UsernameTooLong {
+		t.Errorf("Expected email.invalid and username.too_long, got %+v", problem.Errors)
+	}
+}
@@ -0,0 +1,149 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-go/internal/antispam"
+	"backend-go/internal/api"
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+func TestSignupBotProtection(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	signer := tokens.NewSigner([]byte("secret"))
+	forms := antispam.NewForms(signer, 0, time.Hour, 4)
+	srv := api.NewApiServer(repo,
+		api.WithSigner(signer),
+		api.WithForms(forms),
+		api.WithSignupChecks(antispam.Honeypot(), antispam.MinimumAge(forms), antispam.ProofOfWork(forms), antispam.SingleUse(forms)),
+	)
+
+	fetchToken := func(t *testing.T) antispam.FormToken {
+		req := httptest.NewRequest(http.MethodGet, "/mailing_list/form-token", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var token antispam.FormToken
+		if err := json.Unmarshal(w.Body.Bytes(), &token); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return token
+	}
+
+	subscribe := func(payload map[string]string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Form token endpoint returns a token and difficulty", func(t *testing.T) {
+		token := fetchToken(t)
+		if token.Token == "" || token.Difficulty != 4 {
+			t.Errorf("Unexpected form token: %+v", token)
+		}
+	})
+
+	t.Run("Signups passing every check are created", func(t *testing.T) {
+		token := fetchToken(t)
+		w := subscribe(map[string]string{
+			"email":     "human@example.com",
+			"username":  "human",
+			"formToken": token.Token,
+			"nonce":     antispam.Solve(token.Token, token.Difficulty),
+		})
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Replayed form tokens are rejected", func(t *testing.T) {
+		token := fetchToken(t)
+		nonce := antispam.Solve(token.Token, token.Difficulty)
+
+		w := subscribe(map[string]string{"email": "first@example.com", "username": "first", "formToken": token.Token, "nonce": nonce})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		w = subscribe(map[string]string{"email": "second@example.com", "username": "second", "formToken": token.Token, "nonce": nonce})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Honeypot submissions look accepted but are dropped", func(t *testing.T) {
+		token := fetchToken(t)
+		w := subscribe(map[string]string{
+			"email":     "trapped@example.com",
+			"username":  "trapped",
+			"formToken": token.Token,
+			"nonce":     antispam.Solve(token.Token, token.Difficulty),
+			"website":   "https://spam.example",
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var response dto.MailingList
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.Email != "trapped@example.com" || response.Status != dto.StatusPending {
+			t.Errorf("Expected a pending signup, got %+v", response)
+		}
+
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "trapped"})
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(page.Subscribers) != 0 {
+			t.Errorf("Expected the signup not to be stored, got %+v", page.Subscribers)
+		}
+	})
+
+	t.Run("Failing checks return 400 Bad Request", func(t *testing.T) {
+		// An empty nonce solves one token in 2^difficulty
+		token := fetchToken(t)
+		for antispam.Solves(token.Token, "", token.Difficulty) {
+			token = fetchToken(t)
+		}
+
+		tests := map[string]map[string]string{
+			"missing token": {"email": "bot2@example.com", "username": "bot"},
+			"missing nonce": {"email": "bot3@example.com", "username": "bot", "formToken": token.Token},
+		}
+		for name, payload := range tests {
+			w := subscribe(payload)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status %d, got %d", name, http.StatusBadRequest, w.Code)
+			}
+		}
+
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "bot"})
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(page.Subscribers) != 0 {
+			t.Errorf("Expected rejected signups not to be stored, got %+v", page.Subscribers)
+		}
+	})
+}
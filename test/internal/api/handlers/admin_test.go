@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseSubscriberQuery(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		query, err := handlers.ParseSubscriberQuery(url.Values{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if query.Limit != handlers.DefaultPageSize {
+			t.Errorf("Expected limit %d, got %d", handlers.DefaultPageSize, query.Limit)
+		}
+	})
+
+	t.Run("All filters", func(t *testing.T) {
+		values := url.Values{
+			"status":         {dto.StatusActive},
+			"created_after":  {"2026-01-01"},
+			"created_before": {"2026-02-01T10:00:00Z"},
+			"q":              {"gmail"},
+			"cursor":         {"abc"},
+			"limit":          {"10"},
+		}
+		query, err := handlers.ParseSubscriberQuery(values)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if query.Status != dto.StatusActive || query.Search != "gmail" || query.Cursor != "abc" || query.Limit != 10 {
+			t.Errorf("Unexpected query: %+v", query)
+		}
+		if !query.CreatedAfter.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected created_after: %v", query.CreatedAfter)
+		}
+		if !query.CreatedBefore.Equal(time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected created_before: %v", query.CreatedBefore)
+		}
+	})
+
+	invalid := []url.Values{
+		{"status": {"deleted"}},
+		{"created_after": {"yesterday"}},
+		{"limit": {"0"}},
+		{"limit": {"1000"}},
+		{"limit": {"ten"}},
+	}
+	for _, values := range invalid {
+		t.Run("Rejects "+values.Encode(), func(t *testing.T) {
+			if _, err := handlers.ParseSubscriberQuery(values); !errors.Is(err, handlers.ErrInvalidQuery) {
+				t.Errorf("Expected ErrInvalidQuery, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/normalize"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConsentLedger(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create consent repository: %v", err)
+	}
+	signer := tokens.NewSigner([]byte("test-secret"))
+	validator := validators.NewMailingListValidator()
+	form := handlers.Consent{Ledger: ledger, Event: dto.ConsentEvent{Source: dto.ConsentSourceForm, PolicyVersion: "v2"}}
+	link := handlers.Consent{Ledger: ledger, Event: dto.ConsentEvent{Source: dto.ConsentSourceEmail}}
+
+	events := func(t *testing.T) []dto.ConsentEvent {
+		t.Helper()
+		events, err := handlers.HandleConsentEvents("Reader@Example.com", ledger, normalize.Normalizer{})
+		if err != nil {
+			t.Fatalf("Failed to list consent events: %v", err)
+		}
+		return events
+	}
+
+	t.Run("Every state change is recorded", func(t *testing.T) {
+		created, err := handlers.HandleCreate(dto.DefaultListID, dto.MailingList{Username: "reader", Email: "reader@example.com"}, repo, validator, normalize.Normalizer{}, form)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := handlers.HandleConfirm(dto.DefaultListID, created.ConfirmationToken, repo, link); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+		if _, err := handlers.HandleUnsubscribe(dto.DefaultListID, token, signer, repo, link); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		recorded := events(t)
+		expected := []string{dto.ConsentSubscribe, dto.ConsentConfirm, dto.ConsentUnsubscribe}
+		if len(recorded) != len(expected) {
+			t.Fatalf("Expected %d events, got %+v", len(expected), recorded)
+		}
+		for i, event := range recorded {
+			if event.Event != expected[i] {
+				t.Errorf("Event %d: expected %s, got %s", i, expected[i], event.Event)
+			}
+		}
+		if recorded[0].Source != dto.ConsentSourceForm || recorded[0].PolicyVersion != "v2" {
+			t.Errorf("Expected the signup's details, got %+v", recorded[0])
+		}
+		if recorded[2].Source != dto.ConsentSourceEmail {
+			t.Errorf("Expected the unsubscribe to come from the email link, got %+v", recorded[2])
+		}
+	})
+
+	t.Run("Signups that change nothing are not recorded", func(t *testing.T) {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "active", Email: "active@example.com"}); err != nil {
+			t.Fatalf("Failed to save subscriber: %v", err)
+		}
+		if _, err := handlers.HandleCreate(dto.DefaultListID, dto.MailingList{Username: "active", Email: "active@example.com"}, repo, validator, normalize.Normalizer{}, form); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		recorded, err := handlers.HandleConsentEvents("active@example.com", ledger, normalize.Normalizer{})
+		if err != nil {
+			t.Fatalf("Failed to list consent events: %v", err)
+		}
+		if len(recorded) != 0 {
+			t.Errorf("Expected no events, got %+v", recorded)
+		}
+	})
+
+	t.Run("Listing requires an email", func(t *testing.T) {
+		if _, err := handlers.HandleConsentEvents(" ", ledger, normalize.Normalizer{}); !errors.Is(err, handlers.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery, got %v", err)
+		}
+	})
+}
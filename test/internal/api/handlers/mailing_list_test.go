@@ -3,7 +3,11 @@ package handlers_test
 import (
 	"backend-go/internal/api/handlers"
 	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
 	"backend-go/internal/repositories"
+	"backend-go/internal/validators"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -21,13 +25,15 @@ func TestHandleCreate(t *testing.T) {
 		}
 	}()
 
+	validator := validators.NewMailingListValidator()
+
 	t.Run("Valid mailing list entry is created successfully", func(t *testing.T) {
 		input := dto.MailingList{
 			Username: "testuser",
 			Email:    "test@example.com",
 		}
 
-		result, err := handlers.HandleCreate(input, repo)
+		result, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -44,6 +50,15 @@ func TestHandleCreate(t *testing.T) {
 		if time.Since(result.CreatedAt) > time.Second {
 			t.Error("Expected CreatedAt to be recent")
 		}
+		if result.Status != dto.StatusPending {
+			t.Errorf("Expected status %s, got %s", dto.StatusPending, result.Status)
+		}
+		if result.ConfirmationToken == "" {
+			t.Error("Expected a confirmation token to be issued")
+		}
+		if !result.ConfirmationExpiresAt.After(time.Now()) {
+			t.Error("Expected confirmation to expire in the future")
+		}
 	})
 
 	t.Run("Invalid email returns validation error", func(t *testing.T) {
@@ -52,7 +67,7 @@ func TestHandleCreate(t *testing.T) {
 			Email:    "notanemail",
 		}
 
-		_, err := handlers.HandleCreate(input, repo)
+		_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err == nil {
 			t.Fatal("Expected validation error, got nil")
 		}
@@ -68,7 +83,7 @@ func TestHandleCreate(t *testing.T) {
 			Email:    "",
 		}
 
-		_, err := handlers.HandleCreate(input, repo)
+		_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err == nil {
 			t.Fatal("Expected validation error, got nil")
 		}
@@ -84,7 +99,7 @@ func TestHandleCreate(t *testing.T) {
 			Email:    "test@example.com",
 		}
 
-		_, err := handlers.HandleCreate(input, repo)
+		_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err == nil {
 			t.Fatal("Expected validation error, got nil")
 		}
@@ -100,7 +115,7 @@ func TestHandleCreate(t *testing.T) {
 			Email:    "duplicate@example.com",
 		}
 
-		_, err := handlers.HandleCreate(input, repo)
+		_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err != nil {
 			t.Fatalf("Expected no error on first create, got %v", err)
 		}
@@ -111,7 +126,7 @@ func TestHandleCreate(t *testing.T) {
 			Email:    "duplicate@example.com",
 		}
 
-		result, err := handlers.HandleCreate(input2, repo)
+		result, err := handlers.HandleCreate(dto.DefaultListID, input2, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		if err != nil {
 			t.Fatalf("Expected no error on duplicate (should be handled gracefully), got %v", err)
 		}
@@ -129,7 +144,7 @@ func TestHandleCreate(t *testing.T) {
 		}
 
 		before := time.Now()
-		result, err := handlers.HandleCreate(input, repo)
+		result, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 		after := time.Now()
 
 		if err != nil {
@@ -141,6 +156,41 @@ func TestHandleCreate(t *testing.T) {
 		}
 	})
 
+	t.Run("Email is trimmed and its domain lowercased", func(t *testing.T) {
+		input := dto.MailingList{
+			Username: "casetest",
+			Email:    "  CaseTest@Example.COM ",
+		}
+
+		result, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Email != "CaseTest@example.com" {
+			t.Errorf("Expected email CaseTest@example.com, got %s", result.Email)
+		}
+		if result.NormalizedEmail != "casetest@example.com" {
+			t.Errorf("Expected normalized email casetest@example.com, got %s", result.NormalizedEmail)
+		}
+	})
+
+	t.Run("Internationalized domains are stored in ASCII form", func(t *testing.T) {
+		input := dto.MailingList{
+			Username: "jörg",
+			Email:    "jörg@BÜCHER.de",
+		}
+
+		result, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Email != "jörg@xn--bcher-kva.de" {
+			t.Errorf("Expected email jörg@xn--bcher-kva.de, got %s", result.Email)
+		}
+	})
+
 	t.Run("Various valid email formats are accepted", func(t *testing.T) {
 		validEmails := []string{
 			"simple@example.com",
@@ -157,7 +207,7 @@ func TestHandleCreate(t *testing.T) {
 					Email:    email,
 				}
 
-				result, err := handlers.HandleCreate(input, repo)
+				result, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 				if err != nil {
 					t.Errorf("Expected valid email %s to be accepted, got error: %v", email, err)
 				}
@@ -186,7 +236,7 @@ func TestHandleCreate(t *testing.T) {
 					Email:    email,
 				}
 
-				_, err := handlers.HandleCreate(input, repo)
+				_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
 				if err == nil {
 					t.Errorf("Expected invalid email %s to be rejected, but it was accepted", email)
 				}
@@ -194,3 +244,47 @@ func TestHandleCreate(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleConfirm(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	t.Run("Token from HandleCreate confirms the subscriber", func(t *testing.T) {
+		created, err := handlers.HandleCreate(dto.DefaultListID, dto.MailingList{Username: "reader", Email: "reader@example.com"}, repo, validators.NewMailingListValidator(), normalize.Normalizer{}, handlers.Consent{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		confirmed, err := handlers.HandleConfirm(dto.DefaultListID, created.ConfirmationToken, repo, handlers.Consent{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if confirmed.Status != dto.StatusActive {
+			t.Errorf("Expected status %s, got %s", dto.StatusActive, confirmed.Status)
+		}
+		if confirmed.Email != created.Email {
+			t.Errorf("Expected email %s, got %s", created.Email, confirmed.Email)
+		}
+	})
+
+	t.Run("Empty token is rejected", func(t *testing.T) {
+		_, err := handlers.HandleConfirm(dto.DefaultListID, "", repo, handlers.Consent{})
+		if !errors.Is(err, handlers.ErrTokenRequired) {
+			t.Errorf("Expected ErrTokenRequired, got %v", err)
+		}
+	})
+
+	t.Run("Unknown token is rejected", func(t *testing.T) {
+		_, err := handlers.HandleConfirm(dto.DefaultListID, "unknown", repo, handlers.Consent{})
+		if !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+}
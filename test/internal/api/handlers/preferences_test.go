@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"backend-go/internal/api/handlers"
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"backend-go/internal/validators"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPreferences(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create topic repository: %v", err)
+	}
+	for _, slug := range []string{"go", "rust"} {
+		if err := topics.CreateTopic(&dto.Topic{Slug: slug, Name: slug}); err != nil {
+			t.Fatalf("Failed to create topic: %v", err)
+		}
+	}
+
+	signer := tokens.NewSigner([]byte("test-secret"))
+	validator := validators.NewMailingListValidator()
+	token := signer.Sign(tokens.PurposePreferences, "reader@example.com", time.Time{})
+
+	t.Run("Signups choose topics", func(t *testing.T) {
+		input := dto.MailingList{Username: "reader", Email: "reader@example.com", Topics: []string{"go"}}
+		if _, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		preferences, err := handlers.HandlePreferences(dto.DefaultListID, token, signer, topics)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !slices.Equal(preferences.Topics, []string{"go"}) || preferences.Frequency != dto.FrequencyImmediate {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+	})
+
+	t.Run("Signups with unknown topics fail validation", func(t *testing.T) {
+		input := dto.MailingList{Username: "other", Email: "other@example.com", Topics: []string{"haskell"}}
+		_, err := handlers.HandleCreate(dto.DefaultListID, input, repo, validator, normalize.Normalizer{}, handlers.Consent{})
+
+		var validationErr *handlers.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors[0].Code != validators.TopicsUnknown {
+			t.Errorf("Expected a topics.unknown validation error, got %v", err)
+		}
+	})
+
+	t.Run("Updates change the given preferences", func(t *testing.T) {
+		username := "  Reader  "
+		monthly := dto.FrequencyMonthly
+		chosen := []string{"go", "rust"}
+		preferences, err := handlers.HandleUpdatePreferences(dto.DefaultListID, token, dto.PreferencesUpdate{Username: &username, Frequency: &monthly, Topics: &chosen}, signer, topics)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if preferences.Username != "Reader" || preferences.Frequency != dto.FrequencyMonthly || !slices.Equal(preferences.Topics, chosen) {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+	})
+
+	t.Run("Invalid updates are rejected", func(t *testing.T) {
+		daily := "daily"
+		_, err := handlers.HandleUpdatePreferences(dto.DefaultListID, token, dto.PreferencesUpdate{Frequency: &daily}, signer, topics)
+		var validationErr *handlers.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors[0].Code != validators.FrequencyInvalid {
+			t.Errorf("Expected a frequency.invalid validation error, got %v", err)
+		}
+
+		unknown := []string{"haskell"}
+		_, err = handlers.HandleUpdatePreferences(dto.DefaultListID, token, dto.PreferencesUpdate{Topics: &unknown}, signer, topics)
+		if !errors.As(err, &validationErr) || validationErr.Errors[0].Code != validators.TopicsUnknown {
+			t.Errorf("Expected a topics.unknown validation error, got %v", err)
+		}
+	})
+
+	t.Run("Tokens must be signed for preferences", func(t *testing.T) {
+		if _, err := handlers.HandlePreferences(dto.DefaultListID, "", signer, topics); !errors.Is(err, handlers.ErrTokenRequired) {
+			t.Errorf("Expected ErrTokenRequired, got %v", err)
+		}
+
+		unsubscribe := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+		if _, err := handlers.HandlePreferences(dto.DefaultListID, unsubscribe, signer, topics); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+
+		stranger := signer.Sign(tokens.PurposePreferences, "nobody@example.com", time.Time{})
+		if _, err := handlers.HandlePreferences(dto.DefaultListID, stranger, signer, topics); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected ErrSubscriberNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+func TestMultipleLists(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	lists, err := repositories.NewSqliteListRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create list repository: %v", err)
+	}
+	notes := dto.List{ID: "notes", Name: "Field Notes", MailFrom: "notes@example.com", AllowedOrigins: []string{"https://notes.example.com"}}
+	if err := lists.CreateList(&notes); err != nil {
+		t.Fatalf("Failed to create list: %v", err)
+	}
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+	manager := apikeys.NewManager(keys)
+	key, _, err := manager.Create("test", []string{dto.ScopeSubscribersRead})
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+
+	mailer := mailers.NewMemoryMailer()
+	signer := tokens.NewSigner([]byte("test-secret"))
+	notesBuilder, err := emails.NewListBuilder(notes, "blog@example.com", "https://api.example.com", "", signer)
+	if err != nil {
+		t.Fatalf("Failed to create list builder: %v", err)
+	}
+	srv := api.NewApiServer(repo,
+		api.WithMailer(mailer),
+		api.WithSigner(signer),
+		api.WithEmailBuilder(emails.NewBuilder("blog@example.com", "https://api.example.com", signer)),
+		api.WithList(notesBuilder),
+		api.WithAPIKeys(manager),
+	)
+
+	serve := func(method, target, body string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	signup := `{"email":"reader@example.com","username":"reader"}`
+
+	t.Run("Signups to a list are confirmed from the list's email", func(t *testing.T) {
+		if w := serve(http.MethodPost, "/mailing_list", signup, nil); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		w := serve(http.MethodPost, "/lists/notes/subscribers", signup, nil)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var created dto.MailingList
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if created.ListID != "notes" || created.Status != dto.StatusPending {
+			t.Errorf("Expected a pending signup to notes, got %+v", created)
+		}
+
+		email := mailer.Sent()[1]
+		if email.From != "notes@example.com" {
+			t.Errorf("Expected the list's sender, got %s", email.From)
+		}
+		confirm := linkFromEmail(t, email, "https://api.example.com/lists/notes/subscribers/confirm")
+		if w := serve(http.MethodGet, confirm.RequestURI(), "", nil); w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(active) != 0 {
+			t.Errorf("Expected the default list signup to stay pending, got %+v", active)
+		}
+	})
+
+	t.Run("Unsubscribe links only work on their list", func(t *testing.T) {
+		unsubscribe := linkFromEmail(t, mailer.Sent()[2], "https://api.example.com/lists/notes/subscribers/unsubscribe")
+		if w := serve(http.MethodGet, "/mailing_list/unsubscribe?"+unsubscribe.RawQuery, "", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d on the default list, got %d", http.StatusBadRequest, w.Code)
+		}
+		if w := serve(http.MethodGet, unsubscribe.RequestURI(), "", nil); w.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Unknown lists are not found", func(t *testing.T) {
+		if w := serve(http.MethodPost, "/lists/nope/subscribers", signup, nil); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("Each list allows its own origins", func(t *testing.T) {
+		for _, tt := range []struct {
+			path, origin string
+			allowed      bool
+		}{
+			{"/lists/notes/subscribers", "https://notes.example.com", true},
+			{"/lists/notes/subscribers", "https://zhisme.com", false},
+			{"/mailing_list", "https://zhisme.com", true},
+			{"/mailing_list", "https://notes.example.com", false},
+		} {
+			header := http.Header{
+				"Origin":                        {tt.origin},
+				"Access-Control-Request-Method": {http.MethodPost},
+			}
+			w := serve(http.MethodOptions, tt.path, "", header)
+			if allowed := w.Header().Get("Access-Control-Allow-Origin") == tt.origin; allowed != tt.allowed {
+				t.Errorf("%s from %s: expected allowed %v, got %v", tt.path, tt.origin, tt.allowed, allowed)
+			}
+		}
+	})
+
+	t.Run("Admins list each list's subscribers", func(t *testing.T) {
+		auth := http.Header{"Authorization": {"Bearer " + key}}
+
+		w := serve(http.MethodGet, "/admin/lists", "", auth)
+		var served []dto.List
+		if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(served) != 2 || served[0].ID != dto.DefaultListID || served[1].ID != "notes" {
+			t.Errorf("Unexpected lists: %+v", served)
+		}
+
+		w = serve(http.MethodGet, "/admin/lists/notes/subscribers?status=unsubscribed", "", auth)
+		var page dto.SubscriberPage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(page.Subscribers) != 1 || page.Subscribers[0].ListID != "notes" {
+			t.Errorf("Unexpected subscribers: %+v", page.Subscribers)
+		}
+
+		if w := serve(http.MethodGet, "/admin/lists/nope/subscribers", "", auth); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/queue"
+	"backend-go/internal/repositories"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	mailQueue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+	manager := apikeys.NewManager(keys)
+	token, _, err := manager.Create("prometheus", []string{dto.ScopeMetricsRead})
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+	reader, _, err := manager.Create("dashboard", []string{dto.ScopeSubscribersRead})
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+
+	srv := api.NewApiServer(repo,
+		api.WithMailer(queue.NewMailer(mailQueue)),
+		api.WithMailQueue(mailQueue),
+		api.WithAPIKeys(manager),
+	)
+	get := func(srv *api.Server, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Signup confirmation email is queued", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"email":"queued@example.com","username":"queued"}`)
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", body)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Metrics report queue depth by status", func(t *testing.T) {
+		w := get(srv, "/admin/metrics", token)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+
+		for _, line := range []string{`mail_queue_jobs{status="pending"} 1`, `mail_queue_jobs{status="dead"} 0`} {
+			if !strings.Contains(w.Body.String(), line) {
+				t.Errorf("Expected metrics to contain %q, got:\n%s", line, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Metrics need an api key with the metrics scope", func(t *testing.T) {
+		tests := map[string]struct {
+			path   string
+			token  string
+			status int
+		}{
+			"public path":   {"/metrics", token, http.StatusNotFound},
+			"missing key":   {"/admin/metrics", "", http.StatusUnauthorized},
+			"missing scope": {"/admin/metrics", reader, http.StatusForbidden},
+		}
+		for name, tt := range tests {
+			if w := get(srv, tt.path, tt.token); w.Code != tt.status {
+				t.Errorf("%s: expected status %d, got %d", name, tt.status, w.Code)
+			}
+		}
+	})
+
+	t.Run("Metrics are not exposed without a queue", func(t *testing.T) {
+		w := get(api.NewApiServer(repo, api.WithAPIKeys(manager)), "/admin/metrics", token)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
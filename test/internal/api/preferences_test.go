@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+func TestPreferenceCenter(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create topic repository: %v", err)
+	}
+	for _, topic := range []dto.Topic{{Slug: "go", Name: "Go"}, {Slug: "rust", Name: "Rust"}} {
+		if err := topics.CreateTopic(&topic); err != nil {
+			t.Fatalf("Failed to create topic: %v", err)
+		}
+	}
+
+	mailer := mailers.NewMemoryMailer()
+	signer := tokens.NewSigner([]byte("test-secret"))
+	builder := emails.NewBuilder("blog@example.com", "https://api.example.com", signer,
+		emails.WithPreferencesPage("https://zhisme.com/preferences/"))
+	srv := api.NewApiServer(repo,
+		api.WithMailer(mailer),
+		api.WithSigner(signer),
+		api.WithEmailBuilder(builder),
+		api.WithTopics(topics),
+	)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) dto.Preferences {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var preferences dto.Preferences
+		if err := json.Unmarshal(w.Body.Bytes(), &preferences); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return preferences
+	}
+
+	var preferencesPath string
+
+	t.Run("Topics are listed for signup forms", func(t *testing.T) {
+		w := serve(http.MethodGet, "/mailing_list/topics", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var list []dto.Topic
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(list) != 2 || list[0].Slug != "go" {
+			t.Errorf("Unexpected topics: %+v", list)
+		}
+	})
+
+	t.Run("Signup with topics links to the preference page once confirmed", func(t *testing.T) {
+		w := serve(http.MethodPost, "/mailing_list", `{"email":"reader@example.com","username":"reader","topics":["go"]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+
+		confirm := linkFromEmail(t, mailer.Sent()[0], "https://api.example.com/mailing_list/confirm")
+		if w := serve(http.MethodGet, confirm.RequestURI(), ""); w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		page := linkFromEmail(t, mailer.Sent()[1], "https://zhisme.com/preferences/")
+		preferencesPath = "/mailing_list/preferences?" + page.RawQuery
+	})
+
+	t.Run("Preferences are read with the link's token", func(t *testing.T) {
+		preferences := decode(serve(http.MethodGet, preferencesPath, ""))
+		if preferences.Email != "reader@example.com" || !slices.Equal(preferences.Topics, []string{"go"}) || len(preferences.AvailableTopics) != 2 {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+	})
+
+	t.Run("Preferences are changed without re-subscribing", func(t *testing.T) {
+		sent := len(mailer.Sent())
+		preferences := decode(serve(http.MethodPut, preferencesPath, `{"username":"Reader","frequency":"weekly","topics":["go","rust"]}`))
+		if preferences.Username != "Reader" || preferences.Frequency != dto.FrequencyWeekly || len(preferences.Topics) != 2 {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+		if len(mailer.Sent()) != sent {
+			t.Error("Expected no email for a preferences change")
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(active) != 1 || active[0].Status != dto.StatusActive {
+			t.Errorf("Expected the subscription to stay active, got %+v", active)
+		}
+	})
+
+	t.Run("Invalid changes are a validation problem", func(t *testing.T) {
+		w := serve(http.MethodPut, preferencesPath, `{"frequency":"daily","topics":["haskell"]}`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		var problem dto.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(problem.Errors) != 1 || problem.Errors[0].Field != "frequency" {
+			t.Errorf("Unexpected problem: %+v", problem)
+		}
+	})
+
+	t.Run("Unknown topics at signup are a validation problem", func(t *testing.T) {
+		w := serve(http.MethodPost, "/mailing_list", `{"email":"other@example.com","username":"other","topics":["haskell"]}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Tokens are required", func(t *testing.T) {
+		for _, target := range []string{"/mailing_list/preferences", "/mailing_list/preferences?token=forged"} {
+			if w := serve(http.MethodGet, target, ""); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status %d, got %d", target, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"backend-go/internal/api"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+var privacyLinkRe = regexp.MustCompile(`https://api\.example\.com(/privacy/requests/confirm\?token=\S+)`)
+
+func TestPrivacyRequests(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	privacy, err := repositories.NewSqlitePrivacyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create privacy repository: %v", err)
+	}
+
+	signer := tokens.NewSigner([]byte("test-secret"))
+	builder := emails.NewBuilder("blog@example.com", "https://api.example.com", signer)
+	mailer := mailers.NewMemoryMailer()
+	srv := api.NewApiServer(repo, api.WithSigner(signer), api.WithEmailBuilder(builder), api.WithMailer(mailer), api.WithPrivacy(privacy))
+
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "reader", Email: "reader@example.com"}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+
+	request := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/privacy/requests", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+	// requestLink requests kind for email and returns the link emailed back
+	requestLink := func(t *testing.T, kind, email string) string {
+		t.Helper()
+		mailer.Reset()
+		w := request(`{"email": "` + email + `", "type": "` + kind + `"}`)
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+
+		sent := mailer.Sent()
+		if len(sent) != 1 || sent[0].To != email {
+			t.Fatalf("Expected one email to %s, got %+v", email, sent)
+		}
+		match := privacyLinkRe.FindStringSubmatch(sent[0].TextBody)
+		if match == nil {
+			t.Fatalf("Expected a confirmation link in %q", sent[0].TextBody)
+		}
+		return match[1]
+	}
+
+	t.Run("Access link returns the stored data", func(t *testing.T) {
+		link := requestLink(t, dto.PrivacyAccess, "reader@example.com")
+
+		for range 2 {
+			w := get(link)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var result dto.PrivacyResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if result.Data == nil || len(result.Data.Subscriptions) != 1 || result.Data.Subscriptions[0].Username != "reader" {
+				t.Errorf("Unexpected export: %+v", result.Data)
+			}
+			if result.Request.Status != dto.PrivacyCompleted {
+				t.Errorf("Expected the request to be completed, got %s", result.Request.Status)
+			}
+		}
+	})
+
+	t.Run("Addresses without data get the same response and no email", func(t *testing.T) {
+		mailer.Reset()
+		w := request(`{"email": "stranger@example.com", "type": "erasure"}`)
+		if w.Code != http.StatusAccepted {
+			t.Errorf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+		}
+		if len(mailer.Sent()) != 0 {
+			t.Errorf("Expected no email, got %+v", mailer.Sent())
+		}
+	})
+
+	t.Run("Invalid requests are rejected", func(t *testing.T) {
+		for _, body := range []string{`{"email": "reader@example.com", "type": "delete"}`, `{"email": "", "type": "access"}`} {
+			w := request(body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, w.Code)
+			}
+		}
+		if w := get("/privacy/requests/confirm?token=forged"); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for a forged token, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("Opening the erasure link only asks for confirmation", func(t *testing.T) {
+		link := requestLink(t, dto.PrivacyErasure, "reader@example.com")
+
+		for range 2 {
+			w := get(link)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+				t.Errorf("Expected a confirmation page, got %s", contentType)
+			}
+			if !strings.Contains(w.Body.String(), `<form method="post">`) {
+				t.Errorf("Expected a form posting the confirmation, got %s", w.Body.String())
+			}
+		}
+
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(page.Subscribers) != 1 {
+			t.Errorf("Expected the subscriber to be kept, got %+v", page.Subscribers)
+		}
+	})
+
+	t.Run("Erasure link deletes the subscriber once", func(t *testing.T) {
+		link := requestLink(t, dto.PrivacyErasure, "reader@example.com")
+
+		w := post(link)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result dto.PrivacyResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if result.Erased == nil || result.Erased.Subscribers != 1 {
+			t.Errorf("Expected the subscriber to be erased, got %+v", result.Erased)
+		}
+
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if err != nil {
+			t.Fatalf("Failed to list subscribers: %v", err)
+		}
+		if len(page.Subscribers) != 0 {
+			t.Errorf("Expected no subscribers, got %+v", page.Subscribers)
+		}
+
+		for _, w := range []*httptest.ResponseRecorder{get(link), post(link)} {
+			if w.Code != http.StatusNotFound {
+				t.Errorf("Expected a used erasure link to be rejected, got %d", w.Code)
+			}
+		}
+
+		requests, err := privacy.ListRequests("")
+		if err != nil {
+			t.Fatalf("Failed to list requests: %v", err)
+		}
+		if len(requests) != 4 {
+			t.Errorf("Expected every request in the audit log, got %+v", requests)
+		}
+		for _, request := range requests {
+			if request.Status != dto.PrivacyNoData && request.EmailHash != signer.Digest(tokens.PurposePrivacyEmail, "reader@example.com") {
+				t.Errorf("Expected the address to be logged as a keyed digest, got %s", request.EmailHash)
+			}
+		}
+	})
+}
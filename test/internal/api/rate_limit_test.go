@@ -0,0 +1,122 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend-go/internal/api"
+	"backend-go/internal/ratelimit"
+	"backend-go/internal/repositories"
+)
+
+func TestRateLimit(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	proxies, err := api.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+
+	subscribe := func(srv *api.Server, remoteAddr, forwardedFor, email string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"email": email, "username": "reader"})
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list", bytes.NewBuffer(body))
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Requests over the IP limit return 429 with Retry-After", func(t *testing.T) {
+		srv := api.NewApiServer(repo, api.WithIPRateLimit(ratelimit.NewLimiter(2, time.Hour)))
+
+		for i, email := range []string{"ip1@example.com", "ip2@example.com"} {
+			if w := subscribe(srv, "203.0.113.1:1234", "", email); w.Code != http.StatusCreated {
+				t.Fatalf("Request %d: expected status %d, got %d", i+1, http.StatusCreated, w.Code)
+			}
+		}
+
+		w := subscribe(srv, "203.0.113.1:1234", "", "ip3@example.com")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+		}
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		if err != nil || retryAfter < 1 {
+			t.Errorf("Expected Retry-After in seconds, got %q", w.Header().Get("Retry-After"))
+		}
+
+		var response map[string]map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse error response: %v", err)
+		}
+		if response["error"]["message"] == "" {
+			t.Error("Expected an error message")
+		}
+
+		if w := subscribe(srv, "203.0.113.2:1234", "", "ip3@example.com"); w.Code != http.StatusCreated {
+			t.Errorf("Expected other IPs to be allowed, got %d", w.Code)
+		}
+	})
+
+	t.Run("Requests over the email limit return 429", func(t *testing.T) {
+		srv := api.NewApiServer(repo, api.WithEmailRateLimit(ratelimit.NewLimiter(1, time.Hour)))
+
+		subscribe(srv, "203.0.113.1:1234", "", "target@example.com")
+		w := subscribe(srv, "203.0.113.9:1234", "", "Target@Example.com ")
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("Expected Retry-After header")
+		}
+	})
+
+	t.Run("X-Forwarded-For is honoured only from trusted proxies", func(t *testing.T) {
+		srv := api.NewApiServer(repo,
+			api.WithIPRateLimit(ratelimit.NewLimiter(1, time.Hour)),
+			api.WithTrustedProxies(proxies),
+		)
+
+		// Distinct clients behind the proxy get their own limits.
+		if w := subscribe(srv, "10.0.0.5:80", "198.51.100.1", "xff1@example.com"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		if w := subscribe(srv, "10.0.0.5:80", "198.51.100.2, 192.168.1.1", "xff2@example.com"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+
+		// A client can't escape its limit by prepending addresses.
+		if w := subscribe(srv, "10.0.0.5:80", "1.2.3.4, 198.51.100.1", "xff3@example.com"); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+		}
+
+		// Untrusted peers can't choose their IP with the header.
+		if w := subscribe(srv, "203.0.113.50:80", "198.51.100.3", "xff4@example.com"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		if w := subscribe(srv, "203.0.113.50:80", "198.51.100.4", "xff5@example.com"); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+		}
+	})
+
+	t.Run("Invalid trusted proxies are rejected", func(t *testing.T) {
+		if _, err := api.ParseTrustedProxies([]string{"not-an-ip"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/api"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+)
+
+func TestUnsubscribeMailingList(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	signer := tokens.NewSigner([]byte("test-secret"))
+	builder := emails.NewBuilder("blog@example.com", "https://api.example.com", signer)
+	mailer := mailers.NewMemoryMailer()
+	srv := api.NewApiServer(repo, api.WithSigner(signer), api.WithEmailBuilder(builder), api.WithMailer(mailer))
+
+	for _, email := range []string{"link@example.com", "delete@example.com", "oneclick@example.com"} {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "reader", Email: email}); err != nil {
+			t.Fatalf("Failed to save %s: %v", email, err)
+		}
+	}
+
+	t.Run("GET with link from email unsubscribes", func(t *testing.T) {
+		link, err := url.Parse(builder.UnsubscribeURL("link@example.com"))
+		if err != nil {
+			t.Fatalf("Failed to parse unsubscribe URL: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, link.RequestURI(), nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response["status"] != dto.StatusUnsubscribed {
+			t.Errorf("Expected status %s, got %v", dto.StatusUnsubscribed, response["status"])
+		}
+
+		sent := mailer.Sent()
+		if len(sent) != 1 || sent[0].To != "link@example.com" || sent[0].Subject != "You have been unsubscribed" {
+			t.Errorf("Expected an unsubscribe receipt to link@example.com, got %+v", sent)
+		}
+	})
+
+	t.Run("DELETE with token in body unsubscribes", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "delete@example.com", time.Time{})
+		body, _ := json.Marshal(map[string]string{"token": token})
+
+		req := httptest.NewRequest(http.MethodDelete, "/mailing_list", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("RFC 8058 one-click POST unsubscribes", func(t *testing.T) {
+		email, err := builder.Confirmation(dto.MailingList{Username: "reader", Email: "oneclick@example.com"})
+		if err != nil {
+			t.Fatalf("Failed to build email: %v", err)
+		}
+		if email.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+			t.Errorf("Expected List-Unsubscribe-Post header, got %q", email.Headers["List-Unsubscribe-Post"])
+		}
+
+		header := email.Headers["List-Unsubscribe"]
+		if !strings.HasPrefix(header, "<") || !strings.HasSuffix(header, ">") {
+			t.Fatalf("Expected List-Unsubscribe header in angle brackets, got %q", header)
+		}
+		link, err := url.Parse(strings.Trim(header, "<>"))
+		if err != nil {
+			t.Fatalf("Failed to parse List-Unsubscribe URL: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, link.RequestURI(), strings.NewReader("List-Unsubscribe=One-Click"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response["email"] != "oneclick@example.com" || response["status"] != dto.StatusUnsubscribed {
+			t.Errorf("Expected oneclick@example.com to be unsubscribed, got %v", response)
+		}
+	})
+
+	t.Run("One-click POST without form body returns 400 Bad Request", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "link@example.com", time.Time{})
+
+		req := httptest.NewRequest(http.MethodPost, "/mailing_list/unsubscribe?token="+url.QueryEscape(token), strings.NewReader(""))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Forged token returns 400 Bad Request", func(t *testing.T) {
+		forged := tokens.NewSigner([]byte("attacker")).Sign(tokens.PurposeUnsubscribe, "link@example.com", time.Time{})
+
+		req := httptest.NewRequest(http.MethodGet, "/mailing_list/unsubscribe?token="+url.QueryEscape(forged), nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Token for unknown subscriber returns 404 Not Found", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "unknown@example.com", time.Time{})
+
+		req := httptest.NewRequest(http.MethodGet, "/mailing_list/unsubscribe?token="+url.QueryEscape(token), nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("DELETE with empty body returns 400 Bad Request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/mailing_list", http.NoBody)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+
+		var response map[string]map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse error response: %v", err)
+		}
+		if response["error"]["message"] != "request body is empty" {
+			t.Errorf("Expected 'request body is empty' message, got %s", response["error"]["message"])
+		}
+	})
+}
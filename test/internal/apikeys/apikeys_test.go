@@ -0,0 +1,97 @@
+package apikeys_test
+
+import (
+	"backend-go/internal/apikeys"
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestManager(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+	manager := apikeys.NewManager(keys)
+
+	t.Run("Create returns a token that authenticates", func(t *testing.T) {
+		token, key, err := manager.Create("ci", []string{dto.ScopeSubscribersRead, dto.ScopeSubscribersRead})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(token, "blog_"+key.Prefix+"_") {
+			t.Errorf("Expected token to start with the key prefix, got %s", token)
+		}
+		if len(key.Scopes) != 1 {
+			t.Errorf("Expected duplicate scopes to be dropped, got %v", key.Scopes)
+		}
+
+		authenticated, err := manager.Authenticate(token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if authenticated.ID != key.ID || !authenticated.HasScope(dto.ScopeSubscribersRead) {
+			t.Errorf("Unexpected key: %+v", authenticated)
+		}
+		if authenticated.HasScope(dto.ScopeCampaignsSend) {
+			t.Error("Expected key not to have the campaigns:send scope")
+		}
+		if authenticated.LastUsedAt == nil {
+			t.Error("Expected last used to be recorded")
+		}
+	})
+
+	t.Run("Only the hash of the token is stored", func(t *testing.T) {
+		token, _, err := manager.Create("hashed", []string{dto.ScopeCampaignsSend})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var count int
+		if err := repo.DB().QueryRow("SELECT COUNT(*) FROM api_keys WHERE key_hash = ?", token).Scan(&count); err != nil {
+			t.Fatalf("Failed to query api keys: %v", err)
+		}
+		if count != 0 {
+			t.Error("Expected the plain token not to be stored")
+		}
+		if _, err := keys.FindByHash(apikeys.Hash(token)); err != nil {
+			t.Errorf("Expected key to be found by hash, got %v", err)
+		}
+	})
+
+	t.Run("Unknown scopes are rejected", func(t *testing.T) {
+		for _, scopes := range [][]string{nil, {"subscribers:delete"}} {
+			if _, _, err := manager.Create("bad", scopes); !errors.Is(err, apikeys.ErrUnknownScope) {
+				t.Errorf("%v: expected ErrUnknownScope, got %v", scopes, err)
+			}
+		}
+	})
+
+	t.Run("Invalid and revoked tokens are rejected", func(t *testing.T) {
+		token, key, err := manager.Create("revoked", []string{dto.ScopeSubscribersRead})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := manager.Revoke(key.Prefix); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		for _, candidate := range []string{"", "secret-token", "blog_00000000_nope", token} {
+			if _, err := manager.Authenticate(candidate); !errors.Is(err, apikeys.ErrInvalidKey) {
+				t.Errorf("%q: expected ErrInvalidKey, got %v", candidate, err)
+			}
+		}
+	})
+}
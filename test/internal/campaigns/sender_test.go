@@ -0,0 +1,221 @@
+package campaigns_test
+
+import (
+	"backend-go/internal/campaigns"
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/mailers"
+	"backend-go/internal/newsletter"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// flakyMailer fails for the listed recipients and records the rest.
+type flakyMailer struct {
+	*mailers.MemoryMailer
+	failFor map[string]bool
+}
+
+func (m *flakyMailer) Send(email *dto.Email) error {
+	if m.failFor[email.To] {
+		return errors.New("temporary failure")
+	}
+	return m.MemoryMailer.Send(email)
+}
+
+func TestSender(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	deliveries, err := repositories.NewSqliteDeliveryRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create delivery repository: %v", err)
+	}
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "reader", Email: email, Status: dto.StatusActive}); err != nil {
+			t.Fatalf("Failed to save %s: %v", email, err)
+		}
+	}
+	pending := &dto.MailingList{Username: "pending", Email: "pending@example.com", Status: dto.StatusPending, ConfirmationToken: "token"}
+	if err := repo.Save(dto.DefaultListID, pending); err != nil {
+		t.Fatalf("Failed to save pending subscriber: %v", err)
+	}
+
+	issue, err := newsletter.Parse("# Issue 1\n\nHello!", newsletter.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Failed to parse newsletter: %v", err)
+	}
+
+	mailer := &flakyMailer{MemoryMailer: mailers.NewMemoryMailer(), failFor: map[string]bool{"b@example.com": true}}
+	builder := emails.NewBuilder("blog@example.com", "https://api.example.com", tokens.NewSigner([]byte("secret")))
+	sender := campaigns.NewSender(repo, deliveries, mailer, builder)
+
+	t.Run("Dry run lists active subscribers without sending", func(t *testing.T) {
+		report, err := sender.Send("issue-1", "Issue 1", issue, campaigns.Options{DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(report.Planned) != 3 {
+			t.Errorf("Expected 3 planned recipients, got %v", report.Planned)
+		}
+		if len(mailer.Sent()) != 0 {
+			t.Errorf("Expected no emails in dry run, got %d", len(mailer.Sent()))
+		}
+	})
+
+	t.Run("Test send goes only to the preview address", func(t *testing.T) {
+		report, err := sender.Send("issue-1", "Issue 1", issue, campaigns.Options{TestTo: "me@example.com"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		sent := mailer.Sent()
+		if report.Sent != 1 || len(sent) != 1 || sent[0].To != "me@example.com" {
+			t.Fatalf("Expected a single preview to me@example.com, got %+v", sent)
+		}
+		if !strings.HasPrefix(sent[0].Subject, "[TEST]") {
+			t.Errorf("Expected preview subject to be marked, got %s", sent[0].Subject)
+		}
+		mailer.Reset()
+	})
+
+	t.Run("Send delivers multipart email to confirmed subscribers only", func(t *testing.T) {
+		report, err := sender.Send("issue-1", "Issue 1", issue, campaigns.Options{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Sent != 2 || report.Failed != 1 {
+			t.Errorf("Expected 2 sent and 1 failed, got %+v", report)
+		}
+
+		for _, email := range mailer.Sent() {
+			if email.To == "pending@example.com" {
+				t.Error("Pending subscriber must not receive the campaign")
+			}
+			if email.HTMLBody == "" || !strings.Contains(email.TextBody, "/mailing_list/unsubscribe?token=") {
+				t.Errorf("Expected HTML body and unsubscribe footer, got %+v", email)
+			}
+			if email.Headers["List-Unsubscribe"] == "" {
+				t.Error("Expected List-Unsubscribe header")
+			}
+		}
+	})
+
+	t.Run("Resumed run only retries failed recipients", func(t *testing.T) {
+		mailer.Reset()
+		mailer.failFor = nil
+
+		report, err := sender.Send("issue-1", "Issue 1", issue, campaigns.Options{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		sent := mailer.Sent()
+		if len(sent) != 1 || sent[0].To != "b@example.com" {
+			t.Fatalf("Expected only b@example.com to be retried, got %+v", sent)
+		}
+		if report.Skipped != 2 {
+			t.Errorf("Expected 2 skipped recipients, got %d", report.Skipped)
+		}
+	})
+
+	t.Run("Interrupted sends are not repeated", func(t *testing.T) {
+		mailer.Reset()
+		if err := deliveries.MarkSending(dto.DefaultListID, "issue-2", "c@example.com"); err != nil {
+			t.Fatalf("Failed to mark delivery: %v", err)
+		}
+
+		report, err := sender.Send("issue-2", "Issue 2", issue, campaigns.Options{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(report.Uncertain) != 1 || report.Uncertain[0] != "c@example.com" {
+			t.Errorf("Expected c@example.com to be reported as uncertain, got %v", report.Uncertain)
+		}
+		for _, email := range mailer.Sent() {
+			if email.To == "c@example.com" {
+				t.Error("Interrupted recipient must not be sent a second copy")
+			}
+		}
+	})
+
+	t.Run("Interrupted sends are resolved explicitly", func(t *testing.T) {
+		mailer.Reset()
+		for _, email := range []string{"a@example.com", "b@example.com"} {
+			if err := deliveries.MarkSending(dto.DefaultListID, "issue-3", email); err != nil {
+				t.Fatalf("Failed to mark delivery: %v", err)
+			}
+		}
+
+		report, err := sender.Send("issue-3", "Issue 3", issue, campaigns.Options{MarkSent: []string{"a@example.com"}, RetryUncertain: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(report.Uncertain) != 0 || report.Skipped != 1 || report.Sent != 2 {
+			t.Errorf("Expected a@example.com marked as sent and the rest sent, got %+v", report)
+		}
+		for _, email := range mailer.Sent() {
+			if email.To == "a@example.com" {
+				t.Error("Recipient marked as sent must not get a second copy")
+			}
+		}
+
+		statuses, err := deliveries.Statuses(dto.DefaultListID, "issue-3")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for email, status := range statuses {
+			if status != dto.DeliverySent {
+				t.Errorf("Expected %s to be sent, got %s", email, status)
+			}
+		}
+	})
+
+	t.Run("Digests go to subscribers of the topic and frequency", func(t *testing.T) {
+		topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create topic repository: %v", err)
+		}
+		for _, slug := range []string{"go", "rust"} {
+			if err := topics.CreateTopic(&dto.Topic{Slug: slug, Name: slug}); err != nil {
+				t.Fatalf("Failed to create topic: %v", err)
+			}
+		}
+		for _, subscriber := range []dto.MailingList{
+			{Username: "gopher", Email: "gopher@example.com", Status: dto.StatusActive, Frequency: dto.FrequencyWeekly, Topics: []string{"go"}},
+			{Username: "rustacean", Email: "rustacean@example.com", Status: dto.StatusActive, Frequency: dto.FrequencyWeekly, Topics: []string{"rust"}},
+			{Username: "monthly", Email: "monthly@example.com", Status: dto.StatusActive, Frequency: dto.FrequencyMonthly},
+		} {
+			subscriber := subscriber
+			if err := repo.Save(dto.DefaultListID, &subscriber); err != nil {
+				t.Fatalf("Failed to save %s: %v", subscriber.Email, err)
+			}
+		}
+
+		report, err := sender.Send("weekly-1", "Weekly", issue, campaigns.Options{Topic: "go", Frequency: dto.FrequencyWeekly, DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Recipients != 1 || len(report.Planned) != 1 || report.Planned[0] != "gopher@example.com" {
+			t.Errorf("Expected only gopher@example.com, got %+v", report)
+		}
+
+		report, err = sender.Send("go-1", "Go", issue, campaigns.Options{Topic: "go", DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Recipients != 5 {
+			t.Errorf("Expected everyone but rustacean@example.com, got %v", report.Planned)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package captcha_test
+
+import (
+	"backend-go/internal/captcha"
+	"backend-go/internal/config"
+	"testing"
+)
+
+func TestNewVerifier(t *testing.T) {
+	t.Run("Disabled without a provider", func(t *testing.T) {
+		verifier, err := captcha.NewVerifier(&config.Config{})
+		if err != nil || verifier != nil {
+			t.Errorf("Expected no verifier, got %v, %v", verifier, err)
+		}
+	})
+
+	t.Run("Site verifiers require a secret", func(t *testing.T) {
+		for _, provider := range []string{captcha.ProviderHCaptcha, captcha.ProviderTurnstile} {
+			if _, err := captcha.NewVerifier(&config.Config{CaptchaProvider: provider}); err == nil {
+				t.Errorf("%s: expected an error", provider)
+			}
+			verifier, err := captcha.NewVerifier(&config.Config{CaptchaProvider: provider, CaptchaSecret: "secret"})
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", provider, err)
+			}
+			if _, ok := verifier.(*captcha.SiteVerifier); !ok {
+				t.Errorf("%s: expected a SiteVerifier, got %T", provider, verifier)
+			}
+		}
+	})
+
+	t.Run("Unknown providers are rejected", func(t *testing.T) {
+		if _, err := captcha.NewVerifier(&config.Config{CaptchaProvider: "recaptcha"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
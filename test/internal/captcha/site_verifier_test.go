@@ -0,0 +1,93 @@
+package captcha_test
+
+import (
+	"backend-go/internal/captcha"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newFakeSiteverify serves a siteverify API that accepts validToken when it
+// is sent with secret.
+func newFakeSiteverify(t *testing.T, secret, validToken string) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var remoteIPs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		remoteIPs = append(remoteIPs, r.PostForm.Get("remoteip"))
+
+		response := map[string]any{"success": true}
+		switch {
+		case r.PostForm.Get("secret") != secret:
+			response = map[string]any{"success": false, "error-codes": []string{"invalid-input-secret"}}
+		case r.PostForm.Get("response") != validToken:
+			response = map[string]any{"success": false, "error-codes": []string{"invalid-input-response"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &remoteIPs
+}
+
+func TestSiteVerifier(t *testing.T) {
+	server, remoteIPs := newFakeSiteverify(t, "secret", "solved")
+	ctx := context.Background()
+
+	t.Run("Valid tokens pass", func(t *testing.T) {
+		verifier := captcha.NewSiteVerifier(server.URL, "secret", nil)
+		if err := verifier.Verify(ctx, "solved", "203.0.113.1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := (*remoteIPs)[len(*remoteIPs)-1]; got != "203.0.113.1" {
+			t.Errorf("Expected remoteip to be sent, got %q", got)
+		}
+	})
+
+	t.Run("Invalid tokens fail with the provider's error codes", func(t *testing.T) {
+		verifier := captcha.NewSiteVerifier(server.URL, "secret", nil)
+		err := verifier.Verify(ctx, "wrong", "")
+		if !errors.Is(err, captcha.ErrFailed) {
+			t.Fatalf("Expected ErrFailed, got %v", err)
+		}
+		if err.Error() != "captcha verification failed: invalid-input-response" {
+			t.Errorf("Unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("Empty tokens fail without calling the provider", func(t *testing.T) {
+		calls := len(*remoteIPs)
+		verifier := captcha.NewSiteVerifier(server.URL, "secret", nil)
+		if err := verifier.Verify(ctx, "", ""); !errors.Is(err, captcha.ErrFailed) {
+			t.Errorf("Expected ErrFailed, got %v", err)
+		}
+		if len(*remoteIPs) != calls {
+			t.Error("Expected the provider not to be called")
+		}
+	})
+
+	t.Run("Provider errors are not verification failures", func(t *testing.T) {
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "down", http.StatusBadGateway)
+		}))
+		defer broken.Close()
+
+		verifier := captcha.NewSiteVerifier(broken.URL, "secret", nil)
+		err := verifier.Verify(ctx, "solved", "")
+		if err == nil || errors.Is(err, captcha.ErrFailed) {
+			t.Errorf("Expected a non-verification error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package captcha_test
+
+import (
+	"backend-go/internal/captcha"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStubVerifier(t *testing.T) {
+	verifier := captcha.NewStubVerifier()
+	ctx := context.Background()
+
+	if err := verifier.Verify(ctx, "anything", ""); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	for _, token := range []string{"", captcha.StubFailToken} {
+		if err := verifier.Verify(ctx, token, ""); !errors.Is(err, captcha.ErrFailed) {
+			t.Errorf("%q: expected ErrFailed, got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package emails_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/tokens"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	builder := emails.NewBuilder("blog@example.com", "https://api.example.com/", tokens.NewSigner([]byte("test-secret")))
+	subscriber := dto.MailingList{
+		Username:              "<b>reader</b>",
+		Email:                 "reader@example.com",
+		ConfirmationToken:     "abc",
+		ConfirmationExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("Confirmation email", func(t *testing.T) {
+		email, err := builder.Confirmation(subscriber)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if email.From != "blog@example.com" || email.To != subscriber.Email {
+			t.Errorf("Unexpected addresses: from %s to %s", email.From, email.To)
+		}
+		if email.Subject != "Confirm your subscription" {
+			t.Errorf("Unexpected subject %q", email.Subject)
+		}
+		if !strings.Contains(email.TextBody, "https://api.example.com/mailing_list/confirm?token=abc") {
+			t.Errorf("Expected confirm link in text body, got:\n%s", email.TextBody)
+		}
+		if strings.Contains(email.HTMLBody, "<b>reader</b>") {
+			t.Error("Expected username to be escaped in the HTML body")
+		}
+		if !strings.Contains(email.HTMLBody, `class="button" href="https://api.example.com/mailing_list/confirm?token=abc" style="`) {
+			t.Errorf("Expected inlined button style, got:\n%s", email.HTMLBody)
+		}
+		if email.Headers["List-Unsubscribe"] == "" {
+			t.Error("Expected List-Unsubscribe header")
+		}
+	})
+
+	t.Run("Privacy request email", func(t *testing.T) {
+		for kind, subject := range map[string]string{
+			dto.PrivacyAccess:  "Confirm your request for your data",
+			dto.PrivacyErasure: "Confirm the deletion of your data",
+		} {
+			email, err := builder.PrivacyRequest("reader@example.com", kind, "xyz", time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if email.Subject != subject {
+				t.Errorf("Unexpected subject %q", email.Subject)
+			}
+			for _, want := range []string{"https://api.example.com/privacy/requests/confirm?token=xyz", "4 Mar 2026 05:06 UTC"} {
+				if !strings.Contains(email.TextBody, want) {
+					t.Errorf("Expected text body to contain %q, got:\n%s", want, email.TextBody)
+				}
+			}
+		}
+	})
+
+	t.Run("New post email", func(t *testing.T) {
+		post := dto.Post{Title: "Go & SQLite", Link: "https://zhisme.com/posts/go/", Summary: "A summary"}
+		email, err := builder.NewPost(subscriber, post)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if email.Subject != "New post: Go & SQLite" {
+			t.Errorf("Unexpected subject %q", email.Subject)
+		}
+		for _, want := range []string{post.Link, post.Summary, "Unsubscribe: https://api.example.com/mailing_list/unsubscribe?token="} {
+			if !strings.Contains(email.TextBody, want) {
+				t.Errorf("Expected text body to contain %q, got:\n%s", want, email.TextBody)
+			}
+		}
+		if !strings.Contains(email.HTMLBody, "Go &amp; SQLite") {
+			t.Errorf("Expected escaped title in HTML body, got:\n%s", email.HTMLBody)
+		}
+	})
+
+	t.Run("Newsletter keeps the body of a complete HTML document", func(t *testing.T) {
+		issue := `<html><head><style>.lead { color: red; }</style></head><body><p class="lead">Issue body</p></body></html>`
+		email, err := builder.Newsletter(subscriber, "Issue 1", "Issue body\n", issue)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if strings.Count(email.HTMLBody, "<html>") != 1 {
+			t.Errorf("Expected a single HTML document, got:\n%s", email.HTMLBody)
+		}
+		if !strings.Contains(email.HTMLBody, `<p class="lead" style="margin: 0 0 16px; color: red">Issue body</p>`) {
+			t.Errorf("Expected issue styles to be inlined, got:\n%s", email.HTMLBody)
+		}
+		if !strings.HasPrefix(email.TextBody, "Issue body\n\n--\n") {
+			t.Errorf("Expected footer after the text body, got:\n%s", email.TextBody)
+		}
+	})
+
+	t.Run("Newsletter without HTML is plain text", func(t *testing.T) {
+		email, err := builder.Newsletter(subscriber, "Issue 2", "Just text", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if email.HTMLBody != "" {
+			t.Errorf("Expected no HTML body, got:\n%s", email.HTMLBody)
+		}
+	})
+
+	t.Run("Emails link to the preference page when there is one", func(t *testing.T) {
+		if strings.Contains(mustNewPost(t, builder).TextBody, "Manage preferences") {
+			t.Error("Expected no preferences link without a preference page")
+		}
+
+		withPage := emails.NewBuilder("blog@example.com", "https://api.example.com", tokens.NewSigner([]byte("test-secret")),
+			emails.WithPreferencesPage("https://zhisme.com/preferences/"))
+		email := mustNewPost(t, withPage)
+		if !strings.Contains(email.TextBody, "Manage preferences: https://zhisme.com/preferences/?token=") {
+			t.Errorf("Expected preferences link in text footer, got:\n%s", email.TextBody)
+		}
+		if !strings.Contains(email.HTMLBody, `href="https://zhisme.com/preferences/?token=`) {
+			t.Errorf("Expected preferences link in HTML footer, got:\n%s", email.HTMLBody)
+		}
+
+		confirmation, err := withPage.Confirmation(subscriber)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strings.Contains(confirmation.TextBody, "Manage preferences") {
+			t.Error("Expected no preferences link before the address is confirmed")
+		}
+	})
+
+	t.Run("List emails link to the list's endpoints", func(t *testing.T) {
+		signer := tokens.NewSigner([]byte("test-secret"))
+		list := dto.List{ID: "notes", Name: "Field Notes", MailFrom: "notes@example.com"}
+		listBuilder, err := emails.NewListBuilder(list, "blog@example.com", "https://api.example.com", "", signer,
+			emails.WithPreferencesPage("https://notes.example.com/preferences/"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		confirmation, err := listBuilder.Confirmation(subscriber)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if confirmation.From != "notes@example.com" {
+			t.Errorf("Expected the list's sender, got %s", confirmation.From)
+		}
+		for _, want := range []string{"https://api.example.com/lists/notes/subscribers/confirm?token=abc", "the Field Notes mailing list"} {
+			if !strings.Contains(confirmation.TextBody, want) {
+				t.Errorf("Expected text body to contain %q, got:\n%s", want, confirmation.TextBody)
+			}
+		}
+
+		email := mustNewPost(t, listBuilder)
+		if !strings.Contains(email.TextBody, "https://notes.example.com/preferences/?token=") || !strings.Contains(email.TextBody, "&list=notes") {
+			t.Errorf("Expected a preferences link naming the list, got:\n%s", email.TextBody)
+		}
+		unsubscribe, _ := strings.CutPrefix(listBuilder.UnsubscribeURL("reader@example.com"), "https://api.example.com/lists/notes/subscribers/unsubscribe?token=")
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, unsubscribe); err == nil {
+			t.Error("Expected the list's unsubscribe token to be rejected for the default list")
+		}
+		if _, err := signer.Verify(tokens.ListPurpose(tokens.PurposeUnsubscribe, "notes"), unsubscribe); err != nil {
+			t.Errorf("Expected the list's unsubscribe token to be valid for the list, got %v", err)
+		}
+
+		welcome, err := builder.Welcome(subscriber)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if welcome.Subject != "Welcome to the zhisme.com mailing list" {
+			t.Errorf("Expected the default list's name, got %q", welcome.Subject)
+		}
+	})
+}
+
+func mustNewPost(t *testing.T, builder *emails.Builder) *dto.Email {
+	t.Helper()
+	email, err := builder.NewPost(dto.MailingList{Username: "reader", Email: "reader@example.com"}, dto.Post{Title: "Post", Link: "https://zhisme.com/posts/post/"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return email
+}
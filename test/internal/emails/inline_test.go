@@ -0,0 +1,64 @@
+package emails_test
+
+import (
+	"backend-go/internal/emails"
+	"strings"
+	"testing"
+)
+
+func TestInlineCSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Type and class selectors",
+			input:    `<style>p { color: red; } .note { font-size: 12px; }</style><body><p class="note">Hi</p></body>`,
+			contains: []string{`<p class="note" style="color: red; font-size: 12px">`},
+			excludes: []string{"<style>"},
+		},
+		{
+			name:     "More specific selectors win",
+			input:    `<style>.note { color: blue; } p { color: red; } #intro { color: green; }</style><p id="intro" class="note">Hi</p>`,
+			contains: []string{`style="color: green"`},
+		},
+		{
+			name:     "Inline styles take precedence",
+			input:    `<style>a { color: red; text-decoration: none; }</style><a href="/x" style="color: black">x</a>`,
+			contains: []string{`<a href="/x" style="color: black; text-decoration: none">`},
+		},
+		{
+			name:     "Media queries and complex selectors stay in a style block",
+			input:    `<style>p { margin: 0; } div p { color: red; } @media (max-width: 600px) { p { margin: 4px; } }</style><body><p>x</p></body>`,
+			contains: []string{`<p style="margin: 0">`, "div p { color: red; }", "@media (max-width: 600px)"},
+		},
+		{
+			name:     "Quotes in values are escaped",
+			input:    `<style>body { font-family: "Segoe UI", Arial; }</style><body>x</body>`,
+			contains: []string{`<body style="font-family: &#34;Segoe UI&#34;, Arial">`},
+		},
+		{
+			name:     "Elements in head are not styled",
+			input:    `<html><head><meta charset="utf-8"><style>meta { color: red; } p { color: red; }</style></head><body><p>x</p></body></html>`,
+			contains: []string{`<meta charset="utf-8">`, `<p style="color: red">`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := emails.InlineCSS(tt.input)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package emails_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	data := emails.TemplateData{
+		MailingList: dto.MailingList{
+			Username:              "reader",
+			Email:                 "reader@example.com",
+			ConfirmationExpiresAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		Post:           dto.Post{Title: "Hello world", Link: "https://zhisme.com/posts/hello/"},
+		ConfirmURL:     "https://api.example.com/confirm",
+		UnsubscribeURL: "https://api.example.com/unsubscribe",
+		Subject:        "Issue 1",
+		Text:           "Issue text",
+	}
+
+	t.Run("Embedded defaults render every email", func(t *testing.T) {
+		templates, err := emails.LoadTemplates("")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		names := []string{
+			emails.TemplateConfirmation,
+			emails.TemplateWelcome,
+			emails.TemplateNewPost,
+			emails.TemplateUnsubscribed,
+			emails.TemplateNewsletter,
+		}
+		for _, name := range names {
+			rendered, err := templates.Render(name, data)
+			if err != nil {
+				t.Fatalf("Failed to render %s: %v", name, err)
+			}
+			if rendered.Subject == "" || strings.Contains(rendered.Subject, "\n") {
+				t.Errorf("Expected single-line subject for %s, got %q", name, rendered.Subject)
+			}
+			if !strings.Contains(rendered.Text, "reader") && name != emails.TemplateNewsletter {
+				t.Errorf("Expected username in %s text body, got:\n%s", name, rendered.Text)
+			}
+			if !strings.Contains(rendered.HTML, "<html>") {
+				t.Errorf("Expected %s HTML to use the layout, got:\n%s", name, rendered.HTML)
+			}
+		}
+	})
+
+	t.Run("Directory overrides single templates", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "welcome.txt", `{{define "subject"}}Hey {{.Username}}{{end}}Custom welcome for {{.Email}}`)
+
+		templates, err := emails.LoadTemplates(dir)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		rendered, err := templates.Render(emails.TemplateWelcome, data)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rendered.Subject != "Hey reader" {
+			t.Errorf("Expected custom subject, got %q", rendered.Subject)
+		}
+		if rendered.Text != "Custom welcome for reader@example.com\n" {
+			t.Errorf("Expected custom text body, got %q", rendered.Text)
+		}
+		if !strings.Contains(rendered.HTML, "Your subscription is confirmed") {
+			t.Errorf("Expected default HTML body, got:\n%s", rendered.HTML)
+		}
+	})
+
+	t.Run("Template without subject is rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "new_post.txt", `{{.Post.Title}}`)
+
+		if _, err := emails.LoadTemplates(dir); err == nil {
+			t.Error("Expected an error for a template without subject")
+		}
+	})
+
+	t.Run("Invalid template is rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "confirmation.html", `{{define "content"}}{{.Username}{{end}}`)
+
+		if _, err := emails.LoadTemplates(dir); err == nil {
+			t.Error("Expected a parse error")
+		}
+	})
+}
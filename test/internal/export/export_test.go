@@ -0,0 +1,182 @@
+package export_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/export"
+	"backend-go/internal/importer"
+	"backend-go/internal/repositories"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRepository(t *testing.T, count int) *repositories.SqliteMailingListRepository {
+	t.Helper()
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Close() })
+
+	subscribers := make([]dto.MailingList, count)
+	for n := range subscribers {
+		subscribers[n] = dto.MailingList{
+			Username:        fmt.Sprintf("reader%d", n),
+			Email:           fmt.Sprintf("reader%d@example.com", n),
+			NormalizedEmail: fmt.Sprintf("reader%d@example.com", n),
+			Status:          dto.StatusActive,
+			CreatedAt:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(n) * time.Hour),
+		}
+		if n%2 == 1 {
+			subscribers[n].Status = dto.StatusUnsubscribed
+		}
+	}
+	if _, err := repo.Import(dto.DefaultListID, subscribers, false); err != nil {
+		t.Fatalf("Failed to import subscribers: %v", err)
+	}
+	return repo
+}
+
+// failingRepository fails to list any subscribers.
+type failingRepository struct {
+	*repositories.SqliteMailingListRepository
+}
+
+func (failingRepository) List(string, dto.SubscriberQuery) (*dto.SubscriberPage, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestExport(t *testing.T) {
+	t.Run("CSV pages through the whole list and is read back by import", func(t *testing.T) {
+		repo := newRepository(t, export.PageSize+5)
+		var out bytes.Buffer
+
+		exported, err := export.Export(repo, dto.DefaultListID, dto.SubscriberQuery{Limit: 1}, export.FormatCSV, &out)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if exported != export.PageSize+5 {
+			t.Errorf("Expected %d subscribers, got %d", export.PageSize+5, exported)
+		}
+
+		source, err := importer.NewSource(importer.FormatExport, &out)
+		if err != nil {
+			t.Fatalf("Failed to read export: %v", err)
+		}
+		record, err := source.Next()
+		if err != nil || record.Err != nil {
+			t.Fatalf("Failed to read first row: %v, %v", err, record)
+		}
+		if record.Subscriber.Email != "reader204@example.com" || !record.Subscriber.CreatedAt.Equal(time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected the newest subscriber first, got %+v", record.Subscriber)
+		}
+	})
+
+	t.Run("Filters apply to NDJSON", func(t *testing.T) {
+		repo := newRepository(t, 6)
+		var out bytes.Buffer
+		query := dto.SubscriberQuery{Status: dto.StatusActive, CreatedAfter: time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)}
+
+		exported, err := export.Export(repo, dto.DefaultListID, query, export.FormatNDJSON, &out)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if exported != 2 {
+			t.Fatalf("Expected 2 subscribers, got %d", exported)
+		}
+
+		decoder := json.NewDecoder(&out)
+		for _, email := range []string{"reader4@example.com", "reader2@example.com"} {
+			var subscriber dto.MailingList
+			if err := decoder.Decode(&subscriber); err != nil {
+				t.Fatalf("Failed to decode line: %v", err)
+			}
+			if subscriber.Email != email || subscriber.Status != dto.StatusActive {
+				t.Errorf("Expected %s, got %+v", email, subscriber)
+			}
+		}
+	})
+
+	t.Run("vCards escape and fold values", func(t *testing.T) {
+		repo := newRepository(t, 0)
+		long := strings.Repeat("é", 40)
+		if _, err := repo.Import(dto.DefaultListID, []dto.MailingList{{
+			Username: "Doe, Jane; " + long, Email: "jane@example.com", NormalizedEmail: "jane@example.com",
+			Status: dto.StatusActive, CreatedAt: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+		}}, false); err != nil {
+			t.Fatalf("Failed to import subscriber: %v", err)
+		}
+		var out bytes.Buffer
+
+		if _, err := export.Export(repo, dto.DefaultListID, dto.SubscriberQuery{}, export.FormatVCard, &out); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		card := out.String()
+		for _, expected := range []string{"BEGIN:VCARD\r\nVERSION:3.0\r\n", "FN:Doe\\, Jane\\; ", "EMAIL;TYPE=INTERNET:jane@example.com\r\n", "NOTE:Mailing list: active since 2026-02-03\r\n", "END:VCARD\r\n"} {
+			if !strings.Contains(card, expected) {
+				t.Errorf("Expected %q in %q", expected, card)
+			}
+		}
+		for _, line := range strings.Split(card, "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("Expected lines of at most 75 bytes, got %d: %q", len(line), line)
+			}
+		}
+		unfolded := strings.ReplaceAll(card, "\r\n ", "")
+		if !strings.Contains(unfolded, long) {
+			t.Error("Expected folded lines to join back into the name")
+		}
+	})
+
+	t.Run("CSV neutralizes spreadsheet formulas and import restores them", func(t *testing.T) {
+		repo := newRepository(t, 0)
+		formula := `=HYPERLINK("http://example.com","click")`
+		if _, err := repo.Import(dto.DefaultListID, []dto.MailingList{{
+			Username: formula, Email: "jane@example.com", NormalizedEmail: "jane@example.com",
+			Status: dto.StatusActive, CreatedAt: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+		}}, false); err != nil {
+			t.Fatalf("Failed to import subscriber: %v", err)
+		}
+		var out bytes.Buffer
+
+		if _, err := export.Export(repo, dto.DefaultListID, dto.SubscriberQuery{}, export.FormatCSV, &out); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(out.String(), `"'=HYPERLINK(""http://example.com"",""click"")"`) {
+			t.Errorf("Expected the formula to be quoted, got %q", out.String())
+		}
+
+		source, err := importer.NewSource(importer.FormatExport, &out)
+		if err != nil {
+			t.Fatalf("Failed to read export: %v", err)
+		}
+		record, err := source.Next()
+		if err != nil || record.Err != nil {
+			t.Fatalf("Failed to read first row: %v, %v", err, record)
+		}
+		if record.Subscriber.Username != formula {
+			t.Errorf("Expected username %q, got %q", formula, record.Subscriber.Username)
+		}
+	})
+
+	t.Run("Nothing is written when listing fails", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := export.Export(failingRepository{}, dto.DefaultListID, dto.SubscriberQuery{}, export.FormatCSV, &out); err == nil {
+			t.Error("Expected an error")
+		}
+		if out.Len() != 0 {
+			t.Errorf("Expected no output, got %q", out.String())
+		}
+	})
+
+	t.Run("Unknown formats are rejected", func(t *testing.T) {
+		if _, err := export.Export(newRepository(t, 1), dto.DefaultListID, dto.SubscriberQuery{}, "xml", &bytes.Buffer{}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
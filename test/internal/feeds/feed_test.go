@@ -0,0 +1,166 @@
+package feeds_test
+
+import (
+	"backend-go/internal/feeds"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hugoRSS mirrors the RSS template shipped with Hugo.
+const hugoRSS = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>zhisme.com</title>
+    <link>https://zhisme.com/</link>
+    <item>
+      <title>Second post</title>
+      <link>https://zhisme.com/posts/second/</link>
+      <pubDate>Tue, 03 Feb 2026 10:00:00 +0000</pubDate>
+      <guid>https://zhisme.com/posts/second/</guid>
+      <description>&lt;p&gt;Writing about &lt;strong&gt;Go&lt;/strong&gt; &amp;amp; SQLite.&lt;/p&gt;</description>
+    </item>
+    <item>
+      <title>First post</title>
+      <link>https://zhisme.com/posts/first/</link>
+      <pubDate>Mon, 02 Feb 2026 10:00:00 +0000</pubDate>
+      <description>Hello</description>
+    </item>
+  </channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>zhisme.com</title>
+  <entry>
+    <id>tag:zhisme.com,2026:atom-post</id>
+    <title>Atom post</title>
+    <link rel="alternate" href="https://zhisme.com/posts/atom/"/>
+    <updated>2026-02-04T08:30:00Z</updated>
+    <summary>An Atom summary</summary>
+  </entry>
+</feed>`
+
+func TestParse(t *testing.T) {
+	t.Run("Hugo RSS feed", func(t *testing.T) {
+		posts, err := feeds.Parse([]byte(hugoRSS))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(posts) != 2 {
+			t.Fatalf("Expected 2 posts, got %d", len(posts))
+		}
+
+		post := posts[0]
+		if post.GUID != "https://zhisme.com/posts/second/" || post.Title != "Second post" {
+			t.Errorf("Unexpected post: %+v", post)
+		}
+		if !post.Published.Equal(time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected published date to be parsed, got %v", post.Published)
+		}
+		if post.Summary != "Writing about Go & SQLite." {
+			t.Errorf("Expected plain-text summary, got %q", post.Summary)
+		}
+
+		if posts[1].GUID != "https://zhisme.com/posts/first/" {
+			t.Errorf("Expected link as GUID fallback, got %q", posts[1].GUID)
+		}
+	})
+
+	t.Run("Atom feed", func(t *testing.T) {
+		posts, err := feeds.Parse([]byte(atomFeed))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(posts) != 1 {
+			t.Fatalf("Expected 1 post, got %d", len(posts))
+		}
+
+		post := posts[0]
+		if post.GUID != "tag:zhisme.com,2026:atom-post" || post.Link != "https://zhisme.com/posts/atom/" {
+			t.Errorf("Unexpected post: %+v", post)
+		}
+		if post.Published.IsZero() {
+			t.Error("Expected updated date to be used as published date")
+		}
+	})
+
+	t.Run("Categories become topic slugs", func(t *testing.T) {
+		rss := strings.Replace(hugoRSS, "<description>Hello</description>",
+			"<description>Hello</description><category>Go</category><category>Self Hosting</category><category>go</category>", 1)
+		posts, err := feeds.Parse([]byte(rss))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := posts[1].Categories; !slices.Equal(got, []string{"go", "self-hosting"}) {
+			t.Errorf("Expected RSS categories as slugs, got %v", got)
+		}
+		if posts[0].Categories != nil {
+			t.Errorf("Expected no categories, got %v", posts[0].Categories)
+		}
+
+		atom := strings.Replace(atomFeed, "<summary>", `<category term="SQLite"/><summary>`, 1)
+		posts, err = feeds.Parse([]byte(atom))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := posts[0].Categories; !slices.Equal(got, []string{"sqlite"}) {
+			t.Errorf("Expected Atom categories as slugs, got %v", got)
+		}
+	})
+
+	t.Run("Unknown document", func(t *testing.T) {
+		_, err := feeds.Parse([]byte(`<html><body>not a feed</body></html>`))
+		if !errors.Is(err, feeds.ErrUnknownFormat) {
+			t.Errorf("Expected ErrUnknownFormat, got %v", err)
+		}
+	})
+}
+
+func TestFetch(t *testing.T) {
+	t.Run("Local index.xml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "index.xml")
+		if err := os.WriteFile(path, []byte(hugoRSS), 0o600); err != nil {
+			t.Fatalf("Failed to write feed: %v", err)
+		}
+
+		posts, err := feeds.Fetch(http.DefaultClient, path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(posts) != 2 {
+			t.Errorf("Expected 2 posts, got %d", len(posts))
+		}
+	})
+
+	t.Run("Remote feed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/atom+xml")
+			_, _ = w.Write([]byte(atomFeed))
+		}))
+		defer server.Close()
+
+		posts, err := feeds.Fetch(server.Client(), server.URL+"/index.xml")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(posts) != 1 {
+			t.Errorf("Expected 1 post, got %d", len(posts))
+		}
+	})
+
+	t.Run("Remote error status", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		if _, err := feeds.Fetch(server.Client(), server.URL); err == nil {
+			t.Error("Expected an error for a 404 response")
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package feeds_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/emails"
+	"backend-go/internal/feeds"
+	"backend-go/internal/repositories"
+	"backend-go/internal/tokens"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const newPostItem = `
+    <item>
+      <title>Third post</title>
+      <link>https://zhisme.com/posts/third/</link>
+      <pubDate>Wed, 04 Feb 2026 10:00:00 +0000</pubDate>
+      <guid>https://zhisme.com/posts/third/</guid>
+      <description>Fresh</description>
+    </item>`
+
+func TestWatcherCheck(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	mailQueue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+	posts, err := repositories.NewSqlitePostRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create post repository: %v", err)
+	}
+
+	for _, subscriber := range []dto.MailingList{
+		{Username: "active", Email: "active@example.com", Status: dto.StatusActive},
+		{Username: "pending", Email: "pending@example.com", Status: dto.StatusPending, ConfirmationToken: "t", ConfirmationExpiresAt: time.Now().Add(time.Hour)},
+	} {
+		subscriber := subscriber
+		if err := repo.Save(dto.DefaultListID, &subscriber); err != nil {
+			t.Fatalf("Failed to save subscriber: %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "index.xml")
+	writeFeed := func(feed string) {
+		if err := os.WriteFile(path, []byte(feed), 0o600); err != nil {
+			t.Fatalf("Failed to write feed: %v", err)
+		}
+	}
+
+	signer := tokens.NewSigner([]byte("test-secret"))
+	watcher := feeds.NewWatcher(path, posts, repo, emails.NewBuilder("blog@example.com", "https://api.example.com", signer))
+
+	t.Run("First run records existing posts without sending", func(t *testing.T) {
+		writeFeed(hugoRSS)
+
+		announced, err := watcher.Check()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if announced != 0 {
+			t.Errorf("Expected no announcements, got %d", announced)
+		}
+
+		depth, _ := mailQueue.Depth()
+		if depth[dto.JobPending] != 0 {
+			t.Errorf("Expected empty queue, got %v", depth)
+		}
+	})
+
+	t.Run("New post is queued for active subscribers", func(t *testing.T) {
+		writeFeed(strings.Replace(hugoRSS, "<channel>", "<channel>"+newPostItem, 1))
+
+		announced, err := watcher.Check()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if announced != 1 {
+			t.Errorf("Expected 1 announcement, got %d", announced)
+		}
+
+		job, err := mailQueue.Claim(time.Now())
+		if err != nil || job == nil {
+			t.Fatalf("Expected a queued email, got %v, %v", job, err)
+		}
+		if job.Email.To != "active@example.com" {
+			t.Errorf("Expected email to the active subscriber, got %s", job.Email.To)
+		}
+		if !strings.Contains(job.Email.Subject, "Third post") || !strings.Contains(job.Email.TextBody, "https://zhisme.com/posts/third/") {
+			t.Errorf("Expected the post in the email, got %q: %s", job.Email.Subject, job.Email.TextBody)
+		}
+		if job.Email.Headers["List-Unsubscribe"] == "" {
+			t.Error("Expected List-Unsubscribe header")
+		}
+
+		if next, _ := mailQueue.Claim(time.Now()); next != nil {
+			t.Errorf("Expected only one email, got another to %s", next.Email.To)
+		}
+	})
+
+	t.Run("Announced post is not sent again", func(t *testing.T) {
+		announced, err := watcher.Check()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if announced != 0 {
+			t.Errorf("Expected no announcements, got %d", announced)
+		}
+	})
+
+	t.Run("Posts go to subscribers of their topics who want every post", func(t *testing.T) {
+		topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create topic repository: %v", err)
+		}
+		for _, slug := range []string{"go", "rust"} {
+			if err := topics.CreateTopic(&dto.Topic{Slug: slug, Name: slug}); err != nil {
+				t.Fatalf("Failed to create topic: %v", err)
+			}
+		}
+		for _, subscriber := range []dto.MailingList{
+			{Username: "gopher", Email: "gopher@example.com", Status: dto.StatusActive, Topics: []string{"go"}},
+			{Username: "rustacean", Email: "rustacean@example.com", Status: dto.StatusActive, Topics: []string{"rust"}},
+			{Username: "weekly", Email: "weekly@example.com", Status: dto.StatusActive, Frequency: dto.FrequencyWeekly},
+		} {
+			subscriber := subscriber
+			if err := repo.Save(dto.DefaultListID, &subscriber); err != nil {
+				t.Fatalf("Failed to save subscriber: %v", err)
+			}
+		}
+
+		goPost := strings.NewReplacer("third", "fourth", "Third", "Fourth", "<description>Fresh</description>", "<description>Fresh</description><category>Go</category>").Replace(newPostItem)
+		writeFeed(strings.Replace(hugoRSS, "<channel>", "<channel>"+goPost+newPostItem, 1))
+		if _, err := watcher.Check(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var recipients []string
+		for {
+			job, err := mailQueue.Claim(time.Now())
+			if err != nil {
+				t.Fatalf("Failed to claim email: %v", err)
+			}
+			if job == nil {
+				break
+			}
+			recipients = append(recipients, job.Email.To)
+		}
+		slices.Sort(recipients)
+		expected := []string{"active@example.com", "gopher@example.com"}
+		if !slices.Equal(recipients, expected) {
+			t.Errorf("Expected emails to %v, got %v", expected, recipients)
+		}
+	})
+}
+
+func TestWatcherStartingWithAnEmptyFeed(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	mailQueue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+	posts, err := repositories.NewSqlitePostRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create post repository: %v", err)
+	}
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "active", Email: "active@example.com", Status: dto.StatusActive}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.xml")
+	const emptyFeed = `<?xml version="1.0" encoding="utf-8"?><rss version="2.0"><channel><title>zhisme</title></channel></rss>`
+	watcher := feeds.NewWatcher(path, posts, repo, emails.NewBuilder("blog@example.com", "https://api.example.com", tokens.NewSigner([]byte("test-secret"))))
+
+	for _, feed := range []string{emptyFeed, strings.Replace(emptyFeed, "</title>", "</title>"+newPostItem, 1)} {
+		if err := os.WriteFile(path, []byte(feed), 0o600); err != nil {
+			t.Fatalf("Failed to write feed: %v", err)
+		}
+		if _, err := watcher.Check(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	depth, _ := mailQueue.Depth()
+	if depth[dto.JobPending] != 1 {
+		t.Errorf("Expected the blog's first post to be announced, got %v", depth)
+	}
+}
@@ -0,0 +1,101 @@
+package importer_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/importer"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAll(t *testing.T, source importer.Source) []*importer.Record {
+	t.Helper()
+	var records []*importer.Record
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVSource(t *testing.T) {
+	t.Run("Columns are mapped by header name", func(t *testing.T) {
+		input := "\ufeffSignup Date,E-mail Address,Ignored,Full Name\n2024-03-01 10:30:00,reader@example.com,x,Reader\n"
+		source, err := importer.NewCSVSource(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		records := readAll(t, source)
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 record, got %d", len(records))
+		}
+		subscriber := records[0].Subscriber
+		if subscriber.Email != "reader@example.com" || subscriber.Username != "Reader" {
+			t.Errorf("Unexpected subscriber: %+v", subscriber)
+		}
+		if !subscriber.CreatedAt.Equal(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)) {
+			t.Errorf("Expected the signup date to be kept, got %v", subscriber.CreatedAt)
+		}
+		if subscriber.Status != dto.StatusActive {
+			t.Errorf("Expected status active, got %s", subscriber.Status)
+		}
+		if records[0].Line != 2 {
+			t.Errorf("Expected line 2, got %d", records[0].Line)
+		}
+	})
+
+	t.Run("The old CSV repository format is read", func(t *testing.T) {
+		input := "Username,Email,CreatedAt\nold,old@example.com,2023-01-02T03:04:05Z\n"
+		source, err := importer.NewCSVSource(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		records := readAll(t, source)
+		if len(records) != 1 || records[0].Subscriber.Username != "old" || records[0].Subscriber.CreatedAt.Year() != 2023 {
+			t.Errorf("Unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("Header without an email column is rejected", func(t *testing.T) {
+		if _, err := importer.NewCSVSource(strings.NewReader("name,created\nx,2024-01-01\n")); err == nil {
+			t.Error("Expected an error")
+		}
+		if _, err := importer.NewCSVSource(strings.NewReader("")); err == nil {
+			t.Error("Expected an error for an empty file")
+		}
+	})
+
+	t.Run("Bad values are reported per row", func(t *testing.T) {
+		input := "email,created_at,status\n" +
+			"a@example.com,yesterday,\n" +
+			"b@example.com,,bounced\n" +
+			"c@example.com,,Unsubscribed\n"
+		source, err := importer.NewCSVSource(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		records := readAll(t, source)
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(records))
+		}
+		if records[0].Err == nil || records[1].Err == nil {
+			t.Errorf("Expected bad date and status to be errors, got %v and %v", records[0].Err, records[1].Err)
+		}
+		if records[2].Err != nil || records[2].Subscriber.Status != dto.StatusUnsubscribed {
+			t.Errorf("Expected status unsubscribed, got %+v", records[2])
+		}
+		if records[2].Subscriber.CreatedAt.IsZero() {
+			t.Error("Expected a missing date to default to the import time")
+		}
+	})
+}
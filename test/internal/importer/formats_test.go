@@ -0,0 +1,156 @@
+package importer_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/importer"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFormat(t *testing.T, format, input string) []*importer.Record {
+	t.Helper()
+	source, err := importer.NewSource(format, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return readAll(t, source)
+}
+
+func TestNewSource(t *testing.T) {
+	t.Run("Unknown formats are rejected", func(t *testing.T) {
+		if _, err := importer.NewSource("tinyletter", strings.NewReader("email\n")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Our own CSV format is the default layout", func(t *testing.T) {
+		records := readFormat(t, importer.FormatCSV, "\ufeffemail,status\nreader@example.com,pending\n")
+		if len(records) != 1 || records[0].Subscriber.Status != dto.StatusPending {
+			t.Errorf("Unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("Only exports have their formula quotes removed", func(t *testing.T) {
+		input := "Username,Email,CreatedAt,Status,ConfirmedAt,UnsubscribedAt\n'-dash-,reader@example.com,2026-01-02T00:00:00Z,active,,\n"
+
+		exported := readFormat(t, importer.FormatExport, input)
+		if len(exported) != 1 || exported[0].Subscriber.Username != "-dash-" {
+			t.Errorf("Expected the quote to be removed, got %+v", exported)
+		}
+		written := readFormat(t, importer.FormatCSV, input)
+		if len(written) != 1 || written[0].Subscriber.Username != "'-dash-" {
+			t.Errorf("Expected the quote to be kept, got %+v", written)
+		}
+	})
+}
+
+func TestMailchimpFormat(t *testing.T) {
+	header := "Email Address,First Name,Last Name,MEMBER_RATING,OPTIN_TIME,OPTIN_IP,CONFIRM_TIME,CONFIRM_IP,LAST_CHANGED,UNSUB_TIME,CLEAN_TIME\n"
+
+	t.Run("Subscribed members keep their opt-in time", func(t *testing.T) {
+		records := readFormat(t, importer.FormatMailchimp, header+
+			"ada@example.com,Ada,Lovelace,2,2021-04-05 06:07:08,,2021-04-05 06:10:00,,2022-01-01 00:00:00,,\n")
+		if len(records) != 1 || records[0].Err != nil {
+			t.Fatalf("Unexpected records: %+v", records)
+		}
+
+		subscriber := records[0].Subscriber
+		if subscriber.Email != "ada@example.com" || subscriber.Username != "Ada Lovelace" {
+			t.Errorf("Unexpected subscriber: %+v", subscriber)
+		}
+		if subscriber.Status != dto.StatusActive {
+			t.Errorf("Expected status active, got %s", subscriber.Status)
+		}
+		if !subscriber.CreatedAt.Equal(time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC)) {
+			t.Errorf("Expected the opt-in time as signup date, got %v", subscriber.CreatedAt)
+		}
+		if subscriber.ConfirmedAt == nil || subscriber.ConfirmedAt.Minute() != 10 {
+			t.Errorf("Expected the confirm time to be kept, got %v", subscriber.ConfirmedAt)
+		}
+	})
+
+	t.Run("Unsubscribed and cleaned members are unsubscribed", func(t *testing.T) {
+		records := readFormat(t, importer.FormatMailchimp, header+
+			"gone@example.com,,,1,,,2020-01-01 00:00:00,,,2023-02-03 04:05:06,\n"+
+			"bounced@example.com,,,1,2020-01-01 00:00:00,,,,,,2023-06-07 08:09:10\n")
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+
+		gone := records[0].Subscriber
+		if gone.Status != dto.StatusUnsubscribed || gone.UnsubscribedAt == nil || gone.UnsubscribedAt.Year() != 2023 {
+			t.Errorf("Expected unsubscribed with its time, got %+v", gone)
+		}
+		if gone.CreatedAt.Year() != 2020 {
+			t.Errorf("Expected the confirm time as signup date without an opt-in time, got %v", gone.CreatedAt)
+		}
+
+		bounced := records[1].Subscriber
+		if bounced.Status != dto.StatusUnsubscribed || bounced.UnsubscribedAt == nil || bounced.UnsubscribedAt.Month() != time.June {
+			t.Errorf("Expected cleaned to be unsubscribed at the clean time, got %+v", bounced)
+		}
+	})
+
+	t.Run("A status column takes precedence", func(t *testing.T) {
+		records := readFormat(t, importer.FormatMailchimp, "Email Address,Status\n"+
+			"a@example.com,cleaned\nb@example.com,pending\nc@example.com,archived\n")
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(records))
+		}
+		if records[0].Subscriber.Status != dto.StatusUnsubscribed || records[1].Subscriber.Status != dto.StatusPending {
+			t.Errorf("Unexpected statuses: %s, %s", records[0].Subscriber.Status, records[1].Subscriber.Status)
+		}
+		if records[2].Err == nil {
+			t.Error("Expected an unknown status to be an error")
+		}
+	})
+}
+
+func TestButtondownFormat(t *testing.T) {
+	t.Run("CSV subscriber types are mapped", func(t *testing.T) {
+		records := readFormat(t, importer.FormatButtondown, "email,creation_date,subscriber_type,notes\n"+
+			"paid@example.com,2022-05-06 07:08:09.123456+00:00,premium,\n"+
+			"new@example.com,2024-01-01T00:00:00Z,unactivated,\n"+
+			"left@example.com,2020-01-01T00:00:00Z,unsubscribed,\n"+
+			"odd@example.com,2020-01-01T00:00:00Z,vip,\n")
+		if len(records) != 4 {
+			t.Fatalf("Expected 4 records, got %d", len(records))
+		}
+
+		statuses := []string{dto.StatusActive, dto.StatusPending, dto.StatusUnsubscribed}
+		for n, status := range statuses {
+			if records[n].Err != nil || records[n].Subscriber.Status != status {
+				t.Errorf("Expected record %d to be %s, got %+v", n, status, records[n])
+			}
+		}
+		if !records[0].Subscriber.CreatedAt.Equal(time.Date(2022, 5, 6, 7, 8, 9, 123456000, time.UTC)) {
+			t.Errorf("Expected the creation date to be kept, got %v", records[0].Subscriber.CreatedAt)
+		}
+		if records[3].Err == nil {
+			t.Error("Expected an unknown subscriber type to be an error")
+		}
+	})
+}
+
+func TestSubstackFormat(t *testing.T) {
+	t.Run("Disabled emails are unsubscribed", func(t *testing.T) {
+		records := readFormat(t, importer.FormatSubstack, "email,active_subscription,expiry,plan,email_disabled,created_at,first_payment_at\n"+
+			"reader@example.com,false,,other,false,2021-03-04T05:06:07.000Z,\n"+
+			"quiet@example.com,false,,other,true,2021-03-04T05:06:07.000Z,\n"+
+			"weird@example.com,false,,other,maybe,,\n")
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(records))
+		}
+
+		if records[0].Subscriber.Status != dto.StatusActive || records[1].Subscriber.Status != dto.StatusUnsubscribed {
+			t.Errorf("Unexpected statuses: %s, %s", records[0].Subscriber.Status, records[1].Subscriber.Status)
+		}
+		if !records[0].Subscriber.CreatedAt.Equal(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)) {
+			t.Errorf("Expected the signup date to be kept, got %v", records[0].Subscriber.CreatedAt)
+		}
+		if records[2].Err == nil {
+			t.Error("Expected an invalid email_disabled value to be an error")
+		}
+	})
+}
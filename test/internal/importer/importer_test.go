@@ -0,0 +1,141 @@
+package importer_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/importer"
+	"backend-go/internal/repositories"
+	"backend-go/internal/validators"
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+const importFile = `email,name
+one@example.com,One
+not-an-email,Broken
+ONE@example.com,Dup
+two@example.com,Two
+existing@example.com,Existing
+three@example.com,Three
+`
+
+func newRepository(t *testing.T) *repositories.SqliteMailingListRepository {
+	t.Helper()
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Close() })
+
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "existing", Email: "existing@example.com"}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+	return repo
+}
+
+func runImport(t *testing.T, repo *repositories.SqliteMailingListRepository, options importer.Options) *importer.Report {
+	t.Helper()
+	source, err := importer.NewCSVSource(strings.NewReader(importFile))
+	if err != nil {
+		t.Fatalf("Failed to read header: %v", err)
+	}
+	report, err := importer.NewImporter(repo, validators.NewMailingListValidator(), options).Import(source)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return report
+}
+
+func countSubscribers(t *testing.T, repo *repositories.SqliteMailingListRepository) int {
+	t.Helper()
+	page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("Failed to list subscribers: %v", err)
+	}
+	return len(page.Subscribers)
+}
+
+func TestImporter(t *testing.T) {
+	t.Run("Valid rows are imported in batches and the rest reported", func(t *testing.T) {
+		repo := newRepository(t)
+		var rejects bytes.Buffer
+
+		report := runImport(t, repo, importer.Options{BatchSize: 2, Rejects: &rejects})
+
+		expected := importer.Report{Rows: 6, Imported: 3, Duplicates: 2, Rejected: 1}
+		if *report != expected {
+			t.Errorf("Expected report %+v, got %+v", expected, *report)
+		}
+		if count := countSubscribers(t, repo); count != 4 {
+			t.Errorf("Expected 4 subscribers, got %d", count)
+		}
+
+		rows, err := csv.NewReader(&rejects).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse rejects: %v", err)
+		}
+		expectedRows := [][]string{
+			{"line", "reason", "email", "name"},
+			{"3", "invalid email format", "not-an-email", "Broken"},
+			{"4", "duplicate of line 2", "ONE@example.com", "Dup"},
+			{"6", "already subscribed", "existing@example.com", "Existing"},
+		}
+		if len(rows) != len(expectedRows) {
+			t.Fatalf("Expected rejects %v, got %v", expectedRows, rows)
+		}
+		for i := range rows {
+			if strings.Join(rows[i], ",") != strings.Join(expectedRows[i], ",") {
+				t.Errorf("Expected reject row %v, got %v", expectedRows[i], rows[i])
+			}
+		}
+	})
+
+	t.Run("Dry run reports the same counts without storing anything", func(t *testing.T) {
+		repo := newRepository(t)
+
+		report := runImport(t, repo, importer.Options{DryRun: true})
+
+		if report.Imported != 3 || report.Duplicates != 2 || report.Rejected != 1 || !report.DryRun {
+			t.Errorf("Unexpected report %+v", *report)
+		}
+		if count := countSubscribers(t, repo); count != 1 {
+			t.Errorf("Expected only the existing subscriber, got %d", count)
+		}
+	})
+
+	t.Run("Importing the same file twice only finds duplicates", func(t *testing.T) {
+		repo := newRepository(t)
+		runImport(t, repo, importer.Options{})
+
+		report := runImport(t, repo, importer.Options{})
+		if report.Imported != 0 || report.Duplicates != 5 {
+			t.Errorf("Unexpected report %+v", *report)
+		}
+	})
+
+	t.Run("Imported subscribers are recorded in the consent ledger", func(t *testing.T) {
+		repo := newRepository(t)
+		ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create consent repository: %v", err)
+		}
+
+		runImport(t, repo, importer.Options{Consent: ledger, BatchSize: 2})
+
+		for email, expected := range map[string]int{"one@example.com": 1, "three@example.com": 1, "existing@example.com": 0} {
+			events, err := ledger.ListEvents(email, email)
+			if err != nil {
+				t.Fatalf("Failed to list consent events: %v", err)
+			}
+			if len(events) != expected {
+				t.Errorf("%s: expected %d events, got %+v", email, expected, events)
+			}
+			for _, event := range events {
+				if event.Event != dto.ConsentSubscribe || event.Source != dto.ConsentSourceImport {
+					t.Errorf("%s: unexpected event %+v", email, event)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package importer_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/importer"
+	"strings"
+	"testing"
+)
+
+func TestJSONSource(t *testing.T) {
+	t.Run("An array of subscribers is read", func(t *testing.T) {
+		input := `  [
+			{"email": "a@example.com", "creation_date": "2021-01-02T03:04:05Z", "subscriber_type": "regular", "tags": ["x"]},
+			{"email_address": "b@example.com", "type": "removed", "metadata": {"k": 1}}
+		]`
+		records := readFormat(t, importer.FormatButtondown, input)
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+
+		if records[0].Subscriber.Email != "a@example.com" || records[0].Subscriber.CreatedAt.Year() != 2021 {
+			t.Errorf("Unexpected subscriber: %+v", records[0].Subscriber)
+		}
+		if records[1].Subscriber.Email != "b@example.com" || records[1].Subscriber.Status != dto.StatusUnsubscribed {
+			t.Errorf("Unexpected subscriber: %+v", records[1].Subscriber)
+		}
+		if records[1].Line != 2 {
+			t.Errorf("Expected records to be numbered, got %d", records[1].Line)
+		}
+	})
+
+	t.Run("Results of an API page are read", func(t *testing.T) {
+		input := `{"count": 2, "next": null, "results": [{"email": "a@example.com"}, 42]}`
+		source, err := importer.NewSource(importer.FormatButtondown, strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if header := source.Header(); len(header) != 1 || header[0] != "record" {
+			t.Errorf("Unexpected header: %v", header)
+		}
+
+		records := readAll(t, source)
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+		if records[0].Err != nil || records[0].Subscriber.Status != dto.StatusActive {
+			t.Errorf("Unexpected record: %+v", records[0])
+		}
+		if records[1].Err == nil || records[1].Fields[0] != "42" {
+			t.Errorf("Expected a non-object to be rejected with its text, got %+v", records[1])
+		}
+	})
+
+	t.Run("Other JSON is rejected", func(t *testing.T) {
+		for _, input := range []string{`{"count": 0}`, `{"results": {}}`, `[`} {
+			source, err := importer.NewSource(importer.FormatButtondown, strings.NewReader(input))
+			if err == nil {
+				_, err = source.Next()
+			}
+			if err == nil {
+				t.Errorf("Expected an error for %s", input)
+			}
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package mailers_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/mailers"
+	"bytes"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := mailers.NewFileMailer(filepath.Join(dir, "outbox"))
+
+	err := mailer.Send(&dto.Email{
+		From:     "blog@example.com",
+		To:       "reader@example.com",
+		Subject:  "Hello",
+		TextBody: "body",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "outbox", "*.eml"))
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 .eml file, got %d", len(files))
+	}
+
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Failed to parse .eml file: %v", err)
+	}
+	if msg.Header.Get("To") != "<reader@example.com>" {
+		t.Errorf("Expected To <reader@example.com>, got %s", msg.Header.Get("To"))
+	}
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := mailers.NewMemoryMailer()
+
+	email := &dto.Email{From: "blog@example.com", To: "reader@example.com", Subject: "Hello", TextBody: "body"}
+	if err := mailer.Send(email); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sent := mailer.Sent()
+	if len(sent) != 1 || sent[0].To != "reader@example.com" {
+		t.Fatalf("Expected email to reader@example.com to be recorded, got %+v", sent)
+	}
+
+	mailer.Reset()
+	if len(mailer.Sent()) != 0 {
+		t.Error("Expected Reset to clear sent emails")
+	}
+
+	if err := mailer.Send(&dto.Email{From: "blog@example.com", To: "broken"}); err == nil {
+		t.Error("Expected error for invalid recipient, got nil")
+	}
+}
@@ -0,0 +1,136 @@
+package mailers_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/mailers"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	t.Run("Text and HTML bodies become multipart/alternative", func(t *testing.T) {
+		raw, err := mailers.BuildMessage(&dto.Email{
+			Headers:  map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+			From:     "Blog <blog@example.com>",
+			To:       "reader@example.com",
+			Subject:  "Hello",
+			TextBody: "plain body",
+			HTMLBody: "<p>html body</p>",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("Failed to parse message: %v", err)
+		}
+		if msg.Header.Get("Subject") != "Hello" {
+			t.Errorf("Expected subject Hello, got %s", msg.Header.Get("Subject"))
+		}
+		if msg.Header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+			t.Errorf("Expected custom header to be kept, got %q", msg.Header.Get("List-Unsubscribe-Post"))
+		}
+		if msg.Header.Get("Message-Id") == "" {
+			t.Error("Expected a Message-ID header")
+		}
+
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("Failed to parse Content-Type: %v", err)
+		}
+		if mediaType != "multipart/alternative" {
+			t.Fatalf("Expected multipart/alternative, got %s", mediaType)
+		}
+
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		var types, bodies []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read part: %v", err)
+			}
+			body, err := io.ReadAll(part)
+			if err != nil {
+				t.Fatalf("Failed to read part body: %v", err)
+			}
+			types = append(types, part.Header.Get("Content-Type"))
+			bodies = append(bodies, string(body))
+		}
+
+		if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+			t.Fatalf("Expected text/plain then text/html parts, got %v", types)
+		}
+		if bodies[0] != "plain body" || bodies[1] != "<p>html body</p>" {
+			t.Errorf("Unexpected part bodies: %q", bodies)
+		}
+	})
+
+	t.Run("Non-ASCII subject is encoded", func(t *testing.T) {
+		raw, err := mailers.BuildMessage(&dto.Email{
+			From:     "blog@example.com",
+			To:       "reader@example.com",
+			Subject:  "Привет",
+			TextBody: "body",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.Contains(string(raw), "Subject: =?utf-8?q?") {
+			t.Errorf("Expected encoded subject, got message:\n%s", raw)
+		}
+	})
+
+	t.Run("Header values with line breaks are rejected", func(t *testing.T) {
+		_, err := mailers.BuildMessage(&dto.Email{
+			Headers:  map[string]string{"List-Unsubscribe": "<https://example.com>\r\nBcc: victim@example.com"},
+			From:     "blog@example.com",
+			To:       "reader@example.com",
+			Subject:  "Hello",
+			TextBody: "body",
+		})
+		if err == nil {
+			t.Error("Expected error for header injection, got nil")
+		}
+	})
+
+	t.Run("Line breaks in the subject are encoded", func(t *testing.T) {
+		raw, err := mailers.BuildMessage(&dto.Email{
+			From:     "blog@example.com",
+			To:       "reader@example.com",
+			Subject:  "Hello\r\nBcc: victim@example.com",
+			TextBody: "body",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("Failed to parse message: %v", err)
+		}
+		if msg.Header.Get("Bcc") != "" {
+			t.Errorf("Subject must not inject headers, got Bcc %q", msg.Header.Get("Bcc"))
+		}
+	})
+
+	t.Run("Invalid recipient is rejected", func(t *testing.T) {
+		_, err := mailers.BuildMessage(&dto.Email{
+			From:     "blog@example.com",
+			To:       "not an address",
+			TextBody: "body",
+		})
+		if err == nil {
+			t.Error("Expected error for invalid recipient, got nil")
+		}
+	})
+}
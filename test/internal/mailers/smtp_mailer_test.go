@@ -0,0 +1,229 @@
+package mailers_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/mailers"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts a single SMTP session and records the commands and
+// message it receives.
+type fakeSMTPServer struct {
+	listener   net.Listener
+	done       chan struct{}
+	commands   []string
+	data       string
+	extensions []string
+}
+
+func newFakeSMTPServer(t *testing.T, extensions ...string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{}), extensions: extensions}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	text := textproto.NewConn(conn)
+	reply := func(format string, args ...interface{}) { _ = text.PrintfLine(format, args...) }
+	readLine := func() string {
+		line, _ := text.ReadLine()
+		return line
+	}
+
+	reply("220 fake.example.com ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+		verb := strings.ToUpper(strings.Fields(line + " ")[0])
+
+		switch verb {
+		case "EHLO":
+			lines := append([]string{"fake.example.com"}, s.extensions...)
+			for i, ext := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				reply("250%s%s", sep, ext)
+			}
+		case "AUTH":
+			if strings.HasPrefix(strings.ToUpper(line), "AUTH LOGIN") {
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:")))
+				s.commands = append(s.commands, decodeBase64(readLine()))
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:")))
+				s.commands = append(s.commands, decodeBase64(readLine()))
+			}
+			reply("235 authenticated")
+		case "MAIL", "RCPT":
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			lines, _ := text.ReadDotLines()
+			s.data = strings.Join(lines, "\n")
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func decodeBase64(value string) string {
+	decoded, _ := base64.StdEncoding.DecodeString(value)
+	return string(decoded)
+}
+
+func (s *fakeSMTPServer) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP session did not finish")
+	}
+}
+
+func testEmail() *dto.Email {
+	return &dto.Email{
+		From:     "Blog <blog@example.com>",
+		To:       "reader@example.com",
+		Subject:  "Hello",
+		TextBody: "Hello from the blog",
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	t.Run("Delivers message with AUTH LOGIN", func(t *testing.T) {
+		server := newFakeSMTPServer(t, "AUTH PLAIN LOGIN")
+		mailer := mailers.NewSMTPMailer(mailers.SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     server.port(),
+			Username: "user",
+			Password: "secret",
+			Security: mailers.SecurityNone,
+			Auth:     mailers.AuthLogin,
+		})
+
+		if err := mailer.Send(testEmail()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		server.wait(t)
+
+		commands := strings.Join(server.commands, "\n")
+		for _, expected := range []string{"AUTH LOGIN", "user", "secret", "MAIL FROM:<blog@example.com>", "RCPT TO:<reader@example.com>"} {
+			if !strings.Contains(commands, expected) {
+				t.Errorf("Expected SMTP session to contain %q, got:\n%s", expected, commands)
+			}
+		}
+		if !strings.Contains(server.data, "Hello from the blog") {
+			t.Errorf("Expected message body to be delivered, got:\n%s", server.data)
+		}
+	})
+
+	t.Run("Delivers message with AUTH PLAIN", func(t *testing.T) {
+		server := newFakeSMTPServer(t, "AUTH PLAIN LOGIN")
+		mailer := mailers.NewSMTPMailer(mailers.SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     server.port(),
+			Username: "user",
+			Password: "secret",
+			Security: mailers.SecurityNone,
+			Auth:     mailers.AuthPlain,
+		})
+
+		if err := mailer.Send(testEmail()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		server.wait(t)
+
+		expected := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00user\x00secret"))
+		if !strings.Contains(strings.Join(server.commands, "\n"), expected) {
+			t.Errorf("Expected %q in SMTP session, got:\n%s", expected, strings.Join(server.commands, "\n"))
+		}
+	})
+
+	t.Run("Internationalized domains are sent in ASCII form", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		mailer := mailers.NewSMTPMailer(mailers.SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     server.port(),
+			Security: mailers.SecurityNone,
+		})
+
+		email := testEmail()
+		email.To = "reader@bücher.de"
+		if err := mailer.Send(email); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		server.wait(t)
+
+		if commands := strings.Join(server.commands, "\n"); !strings.Contains(commands, "RCPT TO:<reader@xn--bcher-kva.de>") {
+			t.Errorf("Expected the recipient in ASCII form, got:\n%s", commands)
+		}
+		if !strings.Contains(server.data, "To: <reader@xn--bcher-kva.de>") {
+			t.Errorf("Expected the To header in ASCII form, got:\n%s", server.data)
+		}
+	})
+
+	t.Run("STARTTLS is required when configured", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		mailer := mailers.NewSMTPMailer(mailers.SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     server.port(),
+			Security: mailers.SecurityStartTLS,
+		})
+
+		err := mailer.Send(testEmail())
+		if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+			t.Errorf("Expected STARTTLS error, got %v", err)
+		}
+	})
+
+	t.Run("Connection failure is reported", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to listen: %v", err)
+		}
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+		_ = listener.Close()
+
+		mailer := mailers.NewSMTPMailer(mailers.SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     port,
+			Security: mailers.SecurityNone,
+			Timeout:  time.Second,
+		})
+		if err := mailer.Send(testEmail()); err == nil {
+			t.Error("Expected error for closed port, got nil")
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package markdown_test
+
+import (
+	"backend-go/internal/markdown"
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"Heading", "## Hello *world*", "<h2>Hello <em>world</em></h2>\n"},
+		{"Paragraph with inline formatting", "Some **bold**, `code` and [a link](https://example.com).", `<p>Some <strong>bold</strong>, <code>code</code> and <a href="https://example.com">a link</a>.</p>` + "\n"},
+		{"Unordered list", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{"Ordered list", "1. one\n2. two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"Blockquote", "> quoted", "<blockquote>\n<p>quoted</p>\n</blockquote>\n"},
+		{"Fenced code is escaped", "```go\nif a < b {}\n```", `<pre><code class="language-go">if a &lt; b {}</code></pre>` + "\n"},
+		{"Horizontal rule", "---", "<hr>\n"},
+		{"Image", `![alt](https://example.com/a.png "Title")`, `<p><img src="https://example.com/a.png" alt="alt" title="Title"></p>` + "\n"},
+		{"Raw HTML is escaped", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"Javascript links are neutralized", "[x](javascript:alert)", `<p><a href="#">x</a></p>` + "\n"},
+		{"Snake case is not emphasis", "use snake_case_names", "<p>use snake_case_names</p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := markdown.ToHTML(tt.source)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("Blocks are separated by blank lines", func(t *testing.T) {
+		got := markdown.ToHTML("# Title\n\nFirst paragraph\ncontinues here.\n\nSecond paragraph.")
+		if strings.Count(got, "<p>") != 2 {
+			t.Errorf("Expected 2 paragraphs, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package migrate_test
+
+import (
+	"backend-go/internal/migrate"
+	"backend-go/internal/repositories"
+	"backend-go/migrations"
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func openDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := repositories.OpenSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_posts.sql": {Data: []byte("CREATE TABLE posts (id INTEGER PRIMARY KEY);\n\n-- migrate:down\nDROP TABLE posts;\n")},
+		"002_add_title.sql":    {Data: []byte("ALTER TABLE posts ADD COLUMN title TEXT;\n\n-- migrate:down\nALTER TABLE posts DROP COLUMN title;\n")},
+		"README.md":            {Data: []byte("not a migration")},
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	t.Run("Loads migrations in version order", func(t *testing.T) {
+		runner, err := migrate.NewRunner(openDatabase(t), testMigrations())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		loaded := runner.Migrations()
+		if len(loaded) != 2 || loaded[0].Name != "create_posts" || loaded[1].Version != 2 {
+			t.Fatalf("Unexpected migrations: %+v", loaded)
+		}
+		if loaded[1].Down != "ALTER TABLE posts DROP COLUMN title;" {
+			t.Errorf("Unexpected down section: %q", loaded[1].Down)
+		}
+		if runner.Latest() != 2 {
+			t.Errorf("Expected latest version 2, got %d", runner.Latest())
+		}
+	})
+
+	t.Run("Rejects badly named and duplicate versions", func(t *testing.T) {
+		for name, source := range map[string]fstest.MapFS{
+			"bad name":  {"create_posts.sql": {Data: []byte("SELECT 1;")}},
+			"duplicate": {"001_a.sql": {Data: []byte("SELECT 1;")}, "1_b.sql": {Data: []byte("SELECT 1;")}},
+		} {
+			if _, err := migrate.NewRunner(openDatabase(t), source); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+
+	t.Run("Embedded migrations can all be rolled back", func(t *testing.T) {
+		runner, err := migrate.NewRunner(openDatabase(t), migrations.FS)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, migration := range runner.Migrations() {
+			if migration.Down == "" {
+				t.Errorf("Migration %d has no down section", migration.Version)
+			}
+		}
+	})
+}
+
+func TestRunner(t *testing.T) {
+	db := openDatabase(t)
+	runner, err := migrate.NewRunner(db, testMigrations())
+	if err != nil {
+		t.Fatalf("Failed to create runner: %v", err)
+	}
+
+	t.Run("Up applies pending migrations once", func(t *testing.T) {
+		applied, err := runner.Up()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(applied) != 2 {
+			t.Errorf("Expected 2 migrations applied, got %d", len(applied))
+		}
+		if _, err := db.Exec(`INSERT INTO posts (title) VALUES ('hello')`); err != nil {
+			t.Errorf("Expected schema to be migrated, got %v", err)
+		}
+
+		applied, err = runner.Up()
+		if err != nil || len(applied) != 0 {
+			t.Errorf("Expected nothing to apply, got %d, %v", len(applied), err)
+		}
+	})
+
+	t.Run("Down rolls back the newest migration", func(t *testing.T) {
+		rolledBack, err := runner.Down(1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(rolledBack) != 1 || rolledBack[0].Version != 2 {
+			t.Fatalf("Unexpected rollback: %+v", rolledBack)
+		}
+
+		statuses, err := runner.Status()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if statuses[0].AppliedAt == nil || statuses[1].AppliedAt != nil {
+			t.Errorf("Expected only version 1 applied, got %+v", statuses)
+		}
+	})
+
+	t.Run("A failing migration leaves the database unchanged", func(t *testing.T) {
+		source := testMigrations()
+		source["003_broken.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE tags (id INTEGER);\nNOT SQL;")}
+		broken, err := migrate.NewRunner(db, source)
+		if err != nil {
+			t.Fatalf("Failed to create runner: %v", err)
+		}
+
+		if _, err := broken.Up(); err == nil {
+			t.Fatal("Expected an error")
+		}
+
+		statuses, _ := runner.Status()
+		if statuses[1].AppliedAt != nil {
+			t.Error("Expected migration 2 to be rolled back with the failed batch")
+		}
+		if _, err := db.Exec(`SELECT title FROM posts`); err == nil {
+			t.Error("Expected title column to be absent")
+		}
+	})
+
+	t.Run("Refuses a database that is ahead", func(t *testing.T) {
+		if _, err := runner.Up(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		older, err := migrate.NewRunner(db, fstest.MapFS{"001_create_posts.sql": testMigrations()["001_create_posts.sql"]})
+		if err != nil {
+			t.Fatalf("Failed to create runner: %v", err)
+		}
+		if _, err := older.Up(); !errors.Is(err, migrate.ErrDatabaseAhead) {
+			t.Errorf("Expected ErrDatabaseAhead, got %v", err)
+		}
+		if _, err := older.Status(); !errors.Is(err, migrate.ErrDatabaseAhead) {
+			t.Errorf("Expected ErrDatabaseAhead from Status, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package newsletter_test
+
+import (
+	"backend-go/internal/newsletter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	source := "---\ntitle: \"Go generics in practice\"\ndate: 2024-01-01\n---\n\n# Hello\n\nRead the [post](https://zhisme.com/post).\n"
+
+	issue, err := newsletter.Parse(source, newsletter.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if issue.Title != "Go generics in practice" {
+		t.Errorf("Expected title from front matter, got %q", issue.Title)
+	}
+	if !strings.Contains(issue.HTML, `<h1>Hello</h1>`) || !strings.Contains(issue.HTML, `<a href="https://zhisme.com/post">post</a>`) {
+		t.Errorf("Unexpected HTML: %s", issue.HTML)
+	}
+	if strings.Contains(issue.Text, "title:") || !strings.HasPrefix(issue.Text, "# Hello") {
+		t.Errorf("Expected text part to be the Markdown body without front matter, got %q", issue.Text)
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	source := `<html><head><title>Weekly</title><style>p{color:red}</style></head>
+<body><h1>Hello</h1><p>Read the <a href="https://zhisme.com/post">post</a>.</p><ul><li>one</li><li>two</li></ul></body></html>`
+
+	issue, err := newsletter.Parse(source, newsletter.FormatHTML)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if issue.Title != "Weekly" {
+		t.Errorf("Expected title from <title>, got %q", issue.Title)
+	}
+	expected := "Hello\n\nRead the post (https://zhisme.com/post).\n\n- one\n- two\n"
+	if issue.Text != expected {
+		t.Errorf("Expected text %q, got %q", expected, issue.Text)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("Format is picked from the extension", func(t *testing.T) {
+		path := filepath.Join(dir, "issue.md")
+		if err := os.WriteFile(path, []byte("Hello *there*"), 0600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		issue, err := newsletter.Load(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if issue.HTML != "<p>Hello <em>there</em></p>\n" {
+			t.Errorf("Unexpected HTML: %q", issue.HTML)
+		}
+	})
+
+	t.Run("Unknown extension is rejected", func(t *testing.T) {
+		path := filepath.Join(dir, "issue.txt")
+		if err := os.WriteFile(path, []byte("Hello"), 0600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		if _, err := newsletter.Load(path); err == nil {
+			t.Error("Expected error for .txt file, got nil")
+		}
+	})
+}
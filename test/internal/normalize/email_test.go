@@ -0,0 +1,48 @@
+package normalize_test
+
+import (
+	"backend-go/internal/normalize"
+	"testing"
+)
+
+func TestEmail(t *testing.T) {
+	tests := map[string]string{
+		"  Alice@Example.COM ": "Alice@example.com",
+		"bob@example.com":      "bob@example.com",
+		"jörg@BÜCHER.de":       "jörg@xn--bcher-kva.de",
+		"not-an-email":         "not-an-email",
+	}
+	for input, want := range tests {
+		if got := normalize.Email(input); got != want {
+			t.Errorf("Email(%q) = %q, expected %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizerKey(t *testing.T) {
+	t.Run("Keys are case-insensitive", func(t *testing.T) {
+		normalizer := normalize.Normalizer{}
+		if normalizer.Key("Alice@Example.com") != normalizer.Key(" alice@example.COM") {
+			t.Error("Expected addresses differing in case to share a key")
+		}
+		if got := normalizer.Key("j.doe+news@gmail.com"); got != "j.doe+news@gmail.com" {
+			t.Errorf("Expected provider rules to be off by default, got %s", got)
+		}
+	})
+
+	t.Run("Folding applies provider rules", func(t *testing.T) {
+		normalizer := normalize.Normalizer{FoldAliases: true}
+		tests := map[string]string{
+			"J.Doe+news@gmail.com":       "jdoe@gmail.com",
+			"jdoe@googlemail.com":        "jdoe@gmail.com",
+			"first.last+tag@outlook.com": "first.last@outlook.com",
+			"first.last+tag@example.com": "first.last+tag@example.com",
+			"+only@gmail.com":            "+only@gmail.com",
+		}
+		for input, want := range tests {
+			if got := normalizer.Key(input); got != want {
+				t.Errorf("Key(%q) = %q, expected %q", input, got, want)
+			}
+		}
+	})
+}
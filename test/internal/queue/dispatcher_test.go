@@ -0,0 +1,241 @@
+package queue_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/mailers"
+	"backend-go/internal/queue"
+	"backend-go/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+// scriptedMailer returns the queued errors in order, then succeeds.
+type scriptedMailer struct {
+	*mailers.MemoryMailer
+	errs []error
+}
+
+func (m *scriptedMailer) Send(email *dto.Email) error {
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return err
+	}
+	return m.MemoryMailer.Send(email)
+}
+
+func newQueue(t *testing.T) *repositories.SqliteMailQueueRepository {
+	t.Helper()
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	t.Cleanup(func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	})
+
+	mailQueue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+	return mailQueue
+}
+
+func testEmail() *dto.Email {
+	return &dto.Email{From: "blog@example.com", To: "reader@example.com", Subject: "Hello", TextBody: "body"}
+}
+
+func TestDispatcherProcessNext(t *testing.T) {
+	options := queue.Options{Workers: 1, MaxAttempts: 3, PollInterval: time.Millisecond, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+
+	t.Run("Successful delivery marks job sent", func(t *testing.T) {
+		mailQueue := newQueue(t)
+		transport := &scriptedMailer{MemoryMailer: mailers.NewMemoryMailer()}
+		dispatcher := queue.NewDispatcher(mailQueue, transport, options)
+
+		if err := queue.NewMailer(mailQueue).Send(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+
+		processed, err := dispatcher.ProcessNext()
+		if err != nil || !processed {
+			t.Fatalf("Expected a job to be processed, got %v, %v", processed, err)
+		}
+		if len(transport.Sent()) != 1 {
+			t.Errorf("Expected 1 delivered email, got %d", len(transport.Sent()))
+		}
+
+		depth, _ := mailQueue.Depth()
+		if depth[dto.JobSent] != 1 {
+			t.Errorf("Expected 1 sent job, got %v", depth)
+		}
+	})
+
+	t.Run("Transient failure is retried later", func(t *testing.T) {
+		mailQueue := newQueue(t)
+		transport := &scriptedMailer{
+			MemoryMailer: mailers.NewMemoryMailer(),
+			errs:         []error{fmt.Errorf("smtp rcpt to: %w", &textproto.Error{Code: 451, Msg: "try again later"})},
+		}
+		dispatcher := queue.NewDispatcher(mailQueue, transport, options)
+
+		if err := mailQueue.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if _, err := dispatcher.ProcessNext(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		depth, _ := mailQueue.Depth()
+		if depth[dto.JobPending] != 1 {
+			t.Errorf("Expected job to be pending again, got %v", depth)
+		}
+
+		processed, err := dispatcher.ProcessNext()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if processed {
+			t.Error("Expected retry to wait for its backoff")
+		}
+	})
+
+	t.Run("Permanent failure moves job to dead letter", func(t *testing.T) {
+		mailQueue := newQueue(t)
+		transport := &scriptedMailer{
+			MemoryMailer: mailers.NewMemoryMailer(),
+			errs:         []error{fmt.Errorf("smtp rcpt to: %w", &textproto.Error{Code: 550, Msg: "no such user"})},
+		}
+		dispatcher := queue.NewDispatcher(mailQueue, transport, options)
+
+		if err := mailQueue.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if _, err := dispatcher.ProcessNext(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		depth, _ := mailQueue.Depth()
+		if depth[dto.JobDead] != 1 {
+			t.Errorf("Expected job to be dead, got %v", depth)
+		}
+	})
+
+	t.Run("Job is dead after max attempts", func(t *testing.T) {
+		mailQueue := newQueue(t)
+		transport := &scriptedMailer{MemoryMailer: mailers.NewMemoryMailer(), errs: []error{errors.New("connection reset")}}
+		lastAttempt := options
+		lastAttempt.MaxAttempts = 1
+		dispatcher := queue.NewDispatcher(mailQueue, transport, lastAttempt)
+
+		if err := mailQueue.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if _, err := dispatcher.ProcessNext(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		depth, _ := mailQueue.Depth()
+		if depth[dto.JobDead] != 1 {
+			t.Errorf("Expected job to be dead after max attempts, got %v", depth)
+		}
+	})
+}
+
+func TestDispatcherPurge(t *testing.T) {
+	mailQueue := newQueue(t)
+	transport := &scriptedMailer{MemoryMailer: mailers.NewMemoryMailer(), errs: []error{mailers.ErrInvalidMessage}}
+	dispatcher := queue.NewDispatcher(mailQueue, transport, queue.Options{Workers: 1, Retention: 24 * time.Hour})
+
+	for range 2 {
+		if err := mailQueue.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if _, err := dispatcher.ProcessNext(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	purged, err := dispatcher.Purge(time.Now())
+	if err != nil || purged != 0 {
+		t.Fatalf("Expected recently sent jobs to be kept, got %d, %v", purged, err)
+	}
+
+	purged, err = dispatcher.Purge(time.Now().Add(25 * time.Hour))
+	if err != nil || purged != 1 {
+		t.Fatalf("Expected the sent job to be purged, got %d, %v", purged, err)
+	}
+	depth, _ := mailQueue.Depth()
+	if depth[dto.JobSent] != 0 || depth[dto.JobDead] != 1 {
+		t.Errorf("Expected only the dead job to be kept, got %v", depth)
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	dispatcher := queue.NewDispatcher(nil, nil, queue.Options{BaseBackoff: time.Minute, MaxBackoff: 10 * time.Minute})
+
+	expected := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 4: 8 * time.Minute, 5: 10 * time.Minute, 20: 10 * time.Minute}
+	for attempts, delay := range expected {
+		if got := dispatcher.Backoff(attempts); got != delay {
+			t.Errorf("Expected backoff %v after %d attempts, got %v", delay, attempts, got)
+		}
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		err       error
+		name      string
+		permanent bool
+	}{
+		{&textproto.Error{Code: 550, Msg: "no such user"}, "5xx rejection", true},
+		{&textproto.Error{Code: 421, Msg: "busy"}, "4xx deferral", false},
+		{&textproto.Error{Code: 535, Msg: "bad credentials"}, "authentication failure", false},
+		{fmt.Errorf("wrapped: %w", mailers.ErrInvalidMessage), "invalid message", true},
+		{errors.New("connection refused"), "network error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queue.IsPermanent(tt.err); got != tt.permanent {
+				t.Errorf("Expected IsPermanent %v, got %v", tt.permanent, got)
+			}
+		})
+	}
+}
+
+func TestDispatcherRun(t *testing.T) {
+	mailQueue := newQueue(t)
+	transport := mailers.NewMemoryMailer()
+	dispatcher := queue.NewDispatcher(mailQueue, transport, queue.Options{Workers: 2, PollInterval: 5 * time.Millisecond})
+
+	for i := 0; i < 5; i++ {
+		if err := mailQueue.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(transport.Sent()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if len(transport.Sent()) != 5 {
+		t.Errorf("Expected 5 delivered emails, got %d", len(transport.Sent()))
+	}
+}
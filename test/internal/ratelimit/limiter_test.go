@@ -0,0 +1,68 @@
+package ratelimit_test
+
+import (
+	"backend-go/internal/ratelimit"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Allows a burst up to the limit", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			if ok, _ := limiter.AllowAt("a", start); !ok {
+				t.Fatalf("Expected request %d to be allowed", i+1)
+			}
+		}
+
+		ok, wait := limiter.AllowAt("a", start)
+		if ok {
+			t.Fatal("Expected request over the limit to be rejected")
+		}
+		if wait != 20*time.Second {
+			t.Errorf("Expected wait of 20s, got %s", wait)
+		}
+	})
+
+	t.Run("Keys have separate buckets", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(1, time.Minute)
+		if ok, _ := limiter.AllowAt("a", start); !ok {
+			t.Fatal("Expected first key to be allowed")
+		}
+		if ok, _ := limiter.AllowAt("b", start); !ok {
+			t.Error("Expected second key to be allowed")
+		}
+	})
+
+	t.Run("Tokens refill over time", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(2, time.Minute)
+		limiter.AllowAt("a", start)
+		limiter.AllowAt("a", start)
+
+		if ok, _ := limiter.AllowAt("a", start.Add(10*time.Second)); ok {
+			t.Error("Expected request before refill to be rejected")
+		}
+		if ok, _ := limiter.AllowAt("a", start.Add(30*time.Second)); !ok {
+			t.Error("Expected request after refill to be allowed")
+		}
+		if ok, wait := limiter.AllowAt("a", start.Add(30*time.Second)); ok || wait != 30*time.Second {
+			t.Errorf("Expected rejection with wait of 30s, got %v %s", ok, wait)
+		}
+	})
+
+	t.Run("Idle keys are forgotten", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(1, time.Minute)
+		limiter.AllowAt("a", start)
+		limiter.AllowAt("b", start)
+		if limiter.Len() != 2 {
+			t.Fatalf("Expected 2 keys, got %d", limiter.Len())
+		}
+
+		limiter.AllowAt("c", start.Add(2*time.Minute))
+		if limiter.Len() != 1 {
+			t.Errorf("Expected idle keys to be dropped, got %d keys", limiter.Len())
+		}
+	})
+}
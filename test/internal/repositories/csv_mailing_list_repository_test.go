@@ -2,9 +2,12 @@ package repositories_test
 
 import (
 	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
 	"backend-go/internal/repositories"
 	"encoding/csv"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -32,7 +35,7 @@ func TestSave(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -92,12 +95,12 @@ func TestSave(t *testing.T) {
 		}
 
 		// Save first entry
-		if err := repo.Save(ml1); err != nil {
+		if err := repo.Save(dto.DefaultListID, ml1); err != nil {
 			t.Fatalf("Failed to save first entry: %v", err)
 		}
 
 		// Save second entry
-		if err := repo.Save(ml2); err != nil {
+		if err := repo.Save(dto.DefaultListID, ml2); err != nil {
 			t.Fatalf("Failed to save second entry: %v", err)
 		}
 
@@ -141,7 +144,7 @@ func TestSave(t *testing.T) {
 		}
 
 		before := time.Now()
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		after := time.Now()
 
 		if err != nil {
@@ -187,7 +190,7 @@ func TestSave(t *testing.T) {
 		}
 
 		// Save first time
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error on first save, got %v", err)
 		}
@@ -199,7 +202,7 @@ func TestSave(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err = repo.Save(ml2)
+		err = repo.Save(dto.DefaultListID, ml2)
 		if err != nil {
 			t.Fatalf("Expected no error on duplicate save (should be silently handled), got %v", err)
 		}
@@ -236,7 +239,7 @@ func TestSave(t *testing.T) {
 			CreatedAt: specificTime,
 		}
 
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -268,7 +271,115 @@ func TestSave(t *testing.T) {
 			t.Errorf("Expected timestamp %v, got %v", specificTime, timestamp)
 		}
 	})
+
+	t.Run("Save stores pending signups as active", func(t *testing.T) {
+		_ = os.Remove(testFile)
+
+		ml := &dto.MailingList{
+			Username:              "testuser",
+			Email:                 "test@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if ml.Status != dto.StatusActive {
+			t.Errorf("Expected status %s, got %s", dto.StatusActive, ml.Status)
+		}
+		if ml.ConfirmationToken != "" {
+			t.Errorf("Expected the confirmation token to be cleared, got %s", ml.ConfirmationToken)
+		}
+	})
+}
+
+func TestCsvUnsubscribe(t *testing.T) {
+	testFile := "test_unsubscribe.csv"
+	defer func() { _ = os.Remove(testFile) }()
+	_ = os.Remove(testFile)
+
+	repo := repositories.NewCsvMailingListRepository(testFile)
+
+	for _, email := range []string{"stay@example.com", "leave@example.com"} {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "user", Email: email, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Failed to save %s: %v", email, err)
+		}
+	}
+
+	t.Run("Unsubscribe removes the row", func(t *testing.T) {
+		ml, err := repo.Unsubscribe(dto.DefaultListID, "leave@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ml.Email != "leave@example.com" {
+			t.Errorf("Expected email leave@example.com, got %s", ml.Email)
+		}
+
+		file, err := os.Open(testFile)
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil {
+				t.Errorf("Failed to close file: %v", closeErr)
+			}
+		}()
+
+		records, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read CSV: %v", err)
+		}
+		if len(records) != 2 { // Header + remaining record
+			t.Fatalf("Expected 2 records (header + 1 data), got %d", len(records))
+		}
+		if records[1][1] != "stay@example.com" {
+			t.Errorf("Expected stay@example.com to remain, got %s", records[1][1])
+		}
+	})
+
+	t.Run("Unknown email returns ErrSubscriberNotFound", func(t *testing.T) {
+		if _, err := repo.Unsubscribe(dto.DefaultListID, "leave@example.com"); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected ErrSubscriberNotFound, got %v", err)
+		}
+	})
 }
 
 // Note: TestEmailExists removed - it tested unexported emailExists() function
 // Duplicate detection is now tested through the public Save() API in TestSave
+
+func TestCsvList(t *testing.T) {
+	repo := repositories.NewCsvMailingListRepository(filepath.Join(t.TempDir(), "list.csv"))
+
+	for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "reader", Email: email}); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+	}
+
+	page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Subscribers) != 2 || page.Subscribers[0].Email != "third@example.com" || page.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %+v", page)
+	}
+
+	page, err = repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Subscribers) != 1 || page.Subscribers[0].Email != "first@example.com" || page.NextCursor != "" {
+		t.Errorf("Unexpected last page: %+v", page)
+	}
+
+	page, err = repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "SECOND", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Subscribers) != 1 || page.Subscribers[0].Email != "second@example.com" {
+		t.Errorf("Unexpected search result: %+v", page)
+	}
+}
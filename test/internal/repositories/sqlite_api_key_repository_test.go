@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/repositories"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSqliteAPIKeyRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	keys, err := repositories.NewSqliteAPIKeyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create api key repository: %v", err)
+	}
+
+	key := &dto.APIKey{Name: "ci", Prefix: "abcd1234", Scopes: []string{dto.ScopeCampaignsSend, dto.ScopeSubscribersRead}}
+
+	t.Run("Create stores the key and finds it by hash", func(t *testing.T) {
+		if err := keys.Create(key, "hash-1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if key.ID == 0 {
+			t.Error("Expected ID to be set")
+		}
+
+		found, err := keys.FindByHash("hash-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if found.Name != "ci" || found.Prefix != "abcd1234" || len(found.Scopes) != 2 {
+			t.Errorf("Unexpected key: %+v", found)
+		}
+		if found.LastUsedAt != nil || found.RevokedAt != nil {
+			t.Errorf("Expected new key to be unused and active, got %+v", found)
+		}
+	})
+
+	t.Run("Unknown hashes are not found", func(t *testing.T) {
+		_, err := keys.FindByHash("missing")
+		if !errors.Is(err, interfaces.ErrAPIKeyNotFound) {
+			t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+		}
+	})
+
+	t.Run("TouchLastUsed records the timestamp", func(t *testing.T) {
+		at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		if err := keys.TouchLastUsed(key.ID, at); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found, _ := keys.FindByHash("hash-1")
+		if found.LastUsedAt == nil || !found.LastUsedAt.Equal(at) {
+			t.Errorf("Expected last used %v, got %v", at, found.LastUsedAt)
+		}
+	})
+
+	t.Run("Revoke marks the key revoked", func(t *testing.T) {
+		if err := keys.Revoke("abcd1234"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		list, err := keys.List()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(list) != 1 || list[0].RevokedAt == nil {
+			t.Errorf("Expected one revoked key, got %+v", list)
+		}
+	})
+
+	t.Run("Revoking an unknown prefix fails", func(t *testing.T) {
+		if err := keys.Revoke("ffffffff"); !errors.Is(err, interfaces.ErrAPIKeyNotFound) {
+			t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestSqliteConsentRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create consent repository: %v", err)
+	}
+
+	signedUp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	subscribe := &dto.ConsentEvent{
+		CreatedAt:     signedUp,
+		Email:         "Jane@example.com",
+		Event:         dto.ConsentSubscribe,
+		Source:        dto.ConsentSourceForm,
+		IPHash:        "ip-hash",
+		UserAgent:     "Mozilla/5.0",
+		PageURL:       "https://zhisme.com/posts/hello/",
+		PolicyVersion: "2026-01-01",
+	}
+	confirm := &dto.ConsentEvent{Email: "Jane@example.com", Event: dto.ConsentConfirm, Source: dto.ConsentSourceEmail}
+	other := &dto.ConsentEvent{Email: "other@example.com", Event: dto.ConsentSubscribe, Source: dto.ConsentSourceImport}
+
+	t.Run("Records events and lists them per subscriber", func(t *testing.T) {
+		if err := ledger.Record(subscribe, confirm, other); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if subscribe.ID == 0 || confirm.ID == 0 || other.ID == 0 {
+			t.Fatal("Expected every event to get an id")
+		}
+		if confirm.CreatedAt.IsZero() || confirm.NormalizedEmail != "jane@example.com" {
+			t.Errorf("Expected the time and normalized email to default, got %+v", confirm)
+		}
+
+		events, err := ledger.ListEvents("jane@example.com", "jane@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, got %+v", events)
+		}
+		first := events[0]
+		if first.Event != dto.ConsentSubscribe || !first.CreatedAt.Equal(signedUp) || first.IPHash != "ip-hash" ||
+			first.UserAgent != "Mozilla/5.0" || first.PageURL != subscribe.PageURL || first.PolicyVersion != "2026-01-01" {
+			t.Errorf("Unexpected first event: %+v", first)
+		}
+		if events[1].Event != dto.ConsentConfirm || events[1].Source != dto.ConsentSourceEmail {
+			t.Errorf("Unexpected second event: %+v", events[1])
+		}
+	})
+
+	t.Run("Events cannot be changed", func(t *testing.T) {
+		if _, err := repo.DB().Exec(`UPDATE consent_events SET source = 'admin' WHERE id = ?`, subscribe.ID); err == nil {
+			t.Error("Expected updating a consent event to fail")
+		}
+	})
+
+	t.Run("Unknown subscribers have no events", func(t *testing.T) {
+		events, err := ledger.ListEvents("nobody@example.com", "nobody@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if events == nil || len(events) != 0 {
+			t.Errorf("Expected an empty list, got %#v", events)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"errors"
+	"testing"
+)
+
+func TestSqliteDeliveryRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	deliveries, err := repositories.NewSqliteDeliveryRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create delivery repository: %v", err)
+	}
+
+	if err := deliveries.MarkSending(dto.DefaultListID, "issue-1", "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := deliveries.MarkSent(dto.DefaultListID, "issue-1", "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := deliveries.MarkFailed(dto.DefaultListID, "issue-1", "b@example.com", errors.New("mailbox full")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := deliveries.MarkSending(dto.DefaultListID, "issue-2", "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	statuses, err := deliveries.Statuses(dto.DefaultListID, "issue-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 deliveries for issue-1, got %v", statuses)
+	}
+	if statuses["a@example.com"] != dto.DeliverySent {
+		t.Errorf("Expected a@example.com to be %s, got %s", dto.DeliverySent, statuses["a@example.com"])
+	}
+	if statuses["b@example.com"] != dto.DeliveryFailed {
+		t.Errorf("Expected b@example.com to be %s, got %s", dto.DeliveryFailed, statuses["b@example.com"])
+	}
+
+	// The default list's "foo-bar" and list foo's "bar" are different campaigns
+	if err := deliveries.MarkSent(dto.DefaultListID, "foo-bar", "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := deliveries.MarkSent("foo", "bar", "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := deliveries.MarkFailed("foo", "issue-1", "a@example.com", errors.New("mailbox full")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	statuses, err = deliveries.Statuses("foo", "issue-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != 1 || statuses["a@example.com"] != dto.DeliveryFailed {
+		t.Errorf("Expected only list foo's delivery, got %v", statuses)
+	}
+	statuses, err = deliveries.Statuses(dto.DefaultListID, "issue-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if statuses["a@example.com"] != dto.DeliverySent {
+		t.Errorf("Expected the default list's delivery to be kept, got %v", statuses)
+	}
+}
@@ -0,0 +1,101 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/repositories"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSqliteListRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	lists, err := repositories.NewSqliteListRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create list repository: %v", err)
+	}
+
+	t.Run("The default list exists", func(t *testing.T) {
+		list, err := lists.GetList(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if list.Name != "zhisme.com" || list.MailFrom != "" || list.AllowedOrigins != nil {
+			t.Errorf("Unexpected default list: %+v", list)
+		}
+	})
+
+	t.Run("Creates and lists lists", func(t *testing.T) {
+		notes := &dto.List{
+			ID:             "notes",
+			Name:           "Field Notes",
+			MailFrom:       "notes@example.com",
+			AllowedOrigins: []string{"https://notes.example.com", "http://localhost:1314"},
+			TemplateDir:    "/etc/notes/templates",
+		}
+		if err := lists.CreateList(notes); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		all, err := lists.ListLists()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(all) != 2 || all[0].ID != dto.DefaultListID || all[1].ID != "notes" {
+			t.Fatalf("Expected lists ordered by id, got %+v", all)
+		}
+		if all[1].MailFrom != notes.MailFrom || all[1].TemplateDir != notes.TemplateDir || !slices.Equal(all[1].AllowedOrigins, notes.AllowedOrigins) {
+			t.Errorf("Expected the list as created, got %+v", all[1])
+		}
+	})
+
+	t.Run("Ids are unique", func(t *testing.T) {
+		if err := lists.CreateList(&dto.List{ID: "notes", Name: "Other"}); !errors.Is(err, interfaces.ErrListExists) {
+			t.Errorf("Expected ErrListExists, got %v", err)
+		}
+	})
+
+	t.Run("Unknown lists are not found", func(t *testing.T) {
+		if _, err := lists.GetList("nope"); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Expected ErrListNotFound, got %v", err)
+		}
+		if err := lists.DeleteList("nope"); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Expected ErrListNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Lists in use can't be deleted", func(t *testing.T) {
+		if err := lists.DeleteList(dto.DefaultListID); !errors.Is(err, interfaces.ErrListInUse) {
+			t.Errorf("Expected ErrListInUse for the default list, got %v", err)
+		}
+
+		if err := repo.Save("notes", &dto.MailingList{Username: "reader", Email: "reader@example.com"}); err != nil {
+			t.Fatalf("Failed to save subscriber: %v", err)
+		}
+		if err := lists.DeleteList("notes"); !errors.Is(err, interfaces.ErrListInUse) {
+			t.Errorf("Expected ErrListInUse for a list with subscribers, got %v", err)
+		}
+	})
+
+	t.Run("Empty lists are deleted", func(t *testing.T) {
+		if err := lists.CreateList(&dto.List{ID: "empty", Name: "Empty"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := lists.DeleteList("empty"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := lists.GetList("empty"); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Expected the list to be gone, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSqliteMailQueueRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	queue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+
+	email := &dto.Email{
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.com/u>"},
+		From:     "blog@example.com",
+		To:       "reader@example.com",
+		Subject:  "Hello",
+		TextBody: "body",
+	}
+
+	t.Run("Enqueued email can be claimed once", func(t *testing.T) {
+		if err := queue.Enqueue(email); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		job, err := queue.Claim(time.Now())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if job == nil {
+			t.Fatal("Expected a job to be claimed")
+		}
+		if job.Attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", job.Attempts)
+		}
+		if job.Email.To != email.To || job.Email.Headers["List-Unsubscribe"] != email.Headers["List-Unsubscribe"] {
+			t.Errorf("Expected email to round-trip, got %+v", job.Email)
+		}
+
+		again, err := queue.Claim(time.Now())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if again != nil {
+			t.Errorf("Expected claimed job not to be claimed twice, got %+v", again)
+		}
+
+		if err := queue.MarkSent(job.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Retried job is not due before its next attempt", func(t *testing.T) {
+		if err := queue.Enqueue(email); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		job, err := queue.Claim(time.Now())
+		if err != nil || job == nil {
+			t.Fatalf("Expected a job, got %v, %v", job, err)
+		}
+
+		next := time.Now().Add(time.Hour)
+		if err := queue.Retry(job.ID, next, errors.New("421 try later")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if early, _ := queue.Claim(time.Now()); early != nil {
+			t.Errorf("Expected no due job before the retry time, got %+v", early)
+		}
+
+		later, err := queue.Claim(next.Add(time.Second))
+		if err != nil || later == nil {
+			t.Fatalf("Expected the job to be due after the retry time, got %v, %v", later, err)
+		}
+		if later.Attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", later.Attempts)
+		}
+
+		if err := queue.MarkDead(later.ID, errors.New("550 no such user")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Interrupted jobs are requeued", func(t *testing.T) {
+		if err := queue.Enqueue(email); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if job, err := queue.Claim(time.Now()); err != nil || job == nil {
+			t.Fatalf("Expected a job, got %v, %v", job, err)
+		}
+
+		requeued, err := queue.RequeueInterrupted()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if requeued != 1 {
+			t.Errorf("Expected 1 requeued job, got %d", requeued)
+		}
+	})
+
+	t.Run("Depth counts jobs per status", func(t *testing.T) {
+		depth, err := queue.Depth()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := map[string]int{dto.JobPending: 1, dto.JobSending: 0, dto.JobSent: 1, dto.JobDead: 1}
+		for status, count := range expected {
+			if depth[status] != count {
+				t.Errorf("Expected %d %s jobs, got %d", count, status, depth[status])
+			}
+		}
+	})
+}
@@ -2,8 +2,13 @@ package repositories_test
 
 import (
 	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/normalize"
 	"backend-go/internal/repositories"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -42,7 +47,7 @@ func TestSqliteSave(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -55,7 +60,7 @@ func TestSqliteSave(t *testing.T) {
 			CreatedAt: time.Time{}, // Zero value
 		}
 
-		err := repo.Save(ml)
+		err := repo.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -68,7 +73,7 @@ func TestSqliteSave(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err := repo.Save(ml1)
+		err := repo.Save(dto.DefaultListID, ml1)
 		if err != nil {
 			t.Fatalf("Expected no error on first save, got %v", err)
 		}
@@ -79,7 +84,7 @@ func TestSqliteSave(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err = repo.Save(ml2)
+		err = repo.Save(dto.DefaultListID, ml2)
 		if err != nil {
 			t.Fatalf("Expected no error on duplicate (should be handled gracefully), got %v", err)
 		}
@@ -99,7 +104,7 @@ func TestSqliteSave(t *testing.T) {
 				CreatedAt: time.Now(),
 			}
 
-			err := repo.Save(ml)
+			err := repo.Save(dto.DefaultListID, ml)
 			if err != nil {
 				t.Fatalf("Expected no error for entry %d, got %v", i, err)
 			}
@@ -142,7 +147,7 @@ func TestSqliteWithFileDatabase(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		err = repo1.Save(ml)
+		err = repo1.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Failed to save entry: %v", err)
 		}
@@ -162,7 +167,7 @@ func TestSqliteWithFileDatabase(t *testing.T) {
 		}()
 
 		// Try to save the same email - should be handled gracefully
-		err = repo2.Save(ml)
+		err = repo2.Save(dto.DefaultListID, ml)
 		if err != nil {
 			t.Fatalf("Expected no error on duplicate in existing db, got %v", err)
 		}
@@ -186,3 +191,709 @@ func TestSqliteClose(t *testing.T) {
 		t.Logf("Second close returned error: %v", err)
 	}
 }
+
+func TestSqliteConfirm(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	t.Run("Valid token activates pending subscriber", func(t *testing.T) {
+		ml := &dto.MailingList{
+			Username:              "pending",
+			Email:                 "pending@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "valid-token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+
+		confirmed, err := repo.Confirm(dto.DefaultListID, "valid-token")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if confirmed.Status != dto.StatusActive {
+			t.Errorf("Expected status %s, got %s", dto.StatusActive, confirmed.Status)
+		}
+		if confirmed.ConfirmedAt == nil {
+			t.Error("Expected ConfirmedAt to be set")
+		}
+
+		if _, err := repo.Confirm(dto.DefaultListID, "valid-token"); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected token to be single use, got %v", err)
+		}
+	})
+
+	t.Run("Expired token is rejected", func(t *testing.T) {
+		ml := &dto.MailingList{
+			Username:              "expired",
+			Email:                 "expired@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "expired-token",
+			ConfirmationExpiresAt: time.Now().Add(-time.Minute),
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+
+		if _, err := repo.Confirm(dto.DefaultListID, "expired-token"); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Unknown token is rejected", func(t *testing.T) {
+		if _, err := repo.Confirm(dto.DefaultListID, "unknown"); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Repeated signup refreshes the token of a pending subscriber", func(t *testing.T) {
+		first := &dto.MailingList{
+			Username:              "again",
+			Email:                 "again@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "first-token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+		second := *first
+		second.ConfirmationToken = "second-token"
+
+		if err := repo.Save(dto.DefaultListID, first); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if err := repo.Save(dto.DefaultListID, &second); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if second.ConfirmationToken != "second-token" {
+			t.Errorf("Expected refreshed token to be kept, got %q", second.ConfirmationToken)
+		}
+
+		if _, err := repo.Confirm(dto.DefaultListID, "first-token"); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected old token to be replaced, got %v", err)
+		}
+		if _, err := repo.Confirm(dto.DefaultListID, "second-token"); err != nil {
+			t.Errorf("Expected new token to confirm, got %v", err)
+		}
+	})
+
+	t.Run("Repeated signup keeps the username of a pending subscriber", func(t *testing.T) {
+		first := &dto.MailingList{
+			Username:              "original",
+			Email:                 "renamed@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "original-token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+		second := *first
+		second.Username = "intruder"
+		second.ConfirmationToken = "intruder-token"
+
+		if err := repo.Save(dto.DefaultListID, first); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if err := repo.Save(dto.DefaultListID, &second); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if second.Username != "original" {
+			t.Errorf("Expected username %q, got %q", "original", second.Username)
+		}
+
+		confirmed, err := repo.Confirm(dto.DefaultListID, "intruder-token")
+		if err != nil {
+			t.Fatalf("Expected new token to confirm, got %v", err)
+		}
+		if confirmed.Username != "original" {
+			t.Errorf("Expected stored username %q, got %q", "original", confirmed.Username)
+		}
+	})
+
+	t.Run("Signup for an active subscriber does not issue a token", func(t *testing.T) {
+		ml := &dto.MailingList{
+			Username:              "again",
+			Email:                 "again@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "third-token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if ml.Status != dto.StatusActive {
+			t.Errorf("Expected status %s, got %s", dto.StatusActive, ml.Status)
+		}
+		if ml.ConfirmationToken != "" {
+			t.Errorf("Expected token to be cleared, got %q", ml.ConfirmationToken)
+		}
+	})
+}
+
+func TestSqliteListActive(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	entries := []*dto.MailingList{
+		{Username: "active", Email: "active@example.com", Status: dto.StatusActive},
+		{
+			Username:              "pending",
+			Email:                 "pending@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "token",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		},
+	}
+	for _, entry := range entries {
+		if err := repo.Save(dto.DefaultListID, entry); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+	}
+
+	active, err := repo.ListActive(dto.DefaultListID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(active) != 1 || active[0].Email != "active@example.com" {
+		t.Errorf("Expected only active@example.com to be active, got %+v", active)
+	}
+}
+
+func TestSqliteUnsubscribe(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "leaving", Email: "leaving@example.com"}); err != nil {
+		t.Fatalf("Failed to save entry: %v", err)
+	}
+
+	t.Run("Unsubscribe marks subscriber unsubscribed", func(t *testing.T) {
+		ml, err := repo.Unsubscribe(dto.DefaultListID, "leaving@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ml.Status != dto.StatusUnsubscribed {
+			t.Errorf("Expected status %s, got %s", dto.StatusUnsubscribed, ml.Status)
+		}
+		if ml.UnsubscribedAt == nil {
+			t.Error("Expected UnsubscribedAt to be set")
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Failed to list active subscribers: %v", err)
+		}
+		if len(active) != 0 {
+			t.Errorf("Expected no active subscribers, got %d", len(active))
+		}
+	})
+
+	t.Run("Unsubscribing twice is not an error", func(t *testing.T) {
+		if _, err := repo.Unsubscribe(dto.DefaultListID, "leaving@example.com"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown email returns ErrSubscriberNotFound", func(t *testing.T) {
+		if _, err := repo.Unsubscribe(dto.DefaultListID, "unknown@example.com"); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected ErrSubscriberNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Unsubscribed address can sign up again", func(t *testing.T) {
+		ml := &dto.MailingList{
+			Username:              "leaving",
+			Email:                 "leaving@example.com",
+			Status:                dto.StatusPending,
+			ConfirmationToken:     "come-back",
+			ConfirmationExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+		if ml.Status != dto.StatusPending {
+			t.Errorf("Expected status %s, got %s", dto.StatusPending, ml.Status)
+		}
+	})
+}
+
+func TestSqliteList(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	// Five subscribers a day apart; the last two share a timestamp
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		createdAt := base.AddDate(0, 0, i)
+		if i == 4 {
+			createdAt = base.AddDate(0, 0, 3)
+		}
+		status := dto.StatusActive
+		if i%2 == 1 {
+			status = dto.StatusUnsubscribed
+		}
+		ml := &dto.MailingList{
+			Username:  fmt.Sprintf("user%d", i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			CreatedAt: createdAt,
+			Status:    status,
+		}
+		if err := repo.Save(dto.DefaultListID, ml); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+	}
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "under_score", Email: "100%@example.com", CreatedAt: base.AddDate(0, 0, -1)}); err != nil {
+		t.Fatalf("Failed to save entry: %v", err)
+	}
+
+	emails := func(page *dto.SubscriberPage) []string {
+		var result []string
+		for _, subscriber := range page.Subscribers {
+			result = append(result, subscriber.Email)
+		}
+		return result
+	}
+
+	t.Run("Pages through all subscribers newest first", func(t *testing.T) {
+		var (
+			seen   []string
+			cursor string
+		)
+		for pages := 0; pages < 10; pages++ {
+			page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 4, Cursor: cursor})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			seen = append(seen, emails(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		expected := []string{"user4@example.com", "user3@example.com", "user2@example.com", "user1@example.com", "user0@example.com", "100%@example.com"}
+		if fmt.Sprint(seen) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, seen)
+		}
+	})
+
+	t.Run("Filters by status", func(t *testing.T) {
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Status: dto.StatusUnsubscribed, Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if fmt.Sprint(emails(page)) != "[user3@example.com user1@example.com]" {
+			t.Errorf("Unexpected subscribers: %v", emails(page))
+		}
+	})
+
+	t.Run("Filters by created_at range", func(t *testing.T) {
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{
+			CreatedAfter:  base.AddDate(0, 0, 1),
+			CreatedBefore: base.AddDate(0, 0, 3),
+			Limit:         10,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if fmt.Sprint(emails(page)) != "[user2@example.com user1@example.com]" {
+			t.Errorf("Unexpected subscribers: %v", emails(page))
+		}
+	})
+
+	t.Run("Searches email and username", func(t *testing.T) {
+		tests := map[string]string{
+			"USER2": "[user2@example.com]",
+			"%":     "[100%@example.com]",
+			"_":     "[100%@example.com]",
+		}
+		for search, expected := range tests {
+			page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: search, Limit: 10})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if fmt.Sprint(emails(page)) != expected {
+				t.Errorf("Search %q: expected %s, got %v", search, expected, emails(page))
+			}
+		}
+	})
+
+	t.Run("Invalid cursor returns ErrInvalidCursor", func(t *testing.T) {
+		if _, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Cursor: "not-a-cursor", Limit: 10}); !errors.Is(err, interfaces.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestSqliteNormalizedEmail(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	t.Run("Emails differing only in case are the same subscriber", func(t *testing.T) {
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "alice", Email: "Alice@Example.com"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "alice", Email: "alice@example.com"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		page, err := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "alice", Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Subscribers) != 1 {
+			t.Errorf("Expected 1 subscriber, got %d", len(page.Subscribers))
+		}
+	})
+
+	t.Run("NormalizedEmail decides uniqueness when set", func(t *testing.T) {
+		first := &dto.MailingList{Username: "jdoe", Email: "j.doe@gmail.com", NormalizedEmail: "jdoe@gmail.com"}
+		second := &dto.MailingList{Username: "jdoe", Email: "jdoe+news@gmail.com", NormalizedEmail: "jdoe@gmail.com"}
+		if err := repo.Save(dto.DefaultListID, first); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := repo.Save(dto.DefaultListID, second); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "gmail.com", Limit: 10})
+		if len(page.Subscribers) != 1 {
+			t.Errorf("Expected 1 subscriber, got %+v", page.Subscribers)
+		}
+	})
+
+	t.Run("Unsubscribe matches the address case-insensitively", func(t *testing.T) {
+		unsubscribed, err := repo.Unsubscribe(dto.DefaultListID, "ALICE@example.COM")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if unsubscribed.Status != dto.StatusUnsubscribed {
+			t.Errorf("Expected status %s, got %s", dto.StatusUnsubscribed, unsubscribed.Status)
+		}
+	})
+}
+
+func TestSqliteDedupe(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "dedupe.db")
+
+	// A database from before migrations were tracked, holding the same
+	// address twice in different case.
+	legacy := createLegacyDatabase(t, testFile)
+	for _, statement := range []string{
+		`INSERT INTO mailing_list (username, email, created_at) VALUES ('bob', 'Bob@example.com', '2026-01-01 00:00:00+00:00')`,
+		`INSERT INTO mailing_list (username, email, created_at) VALUES ('bob', 'bob@example.com', '2026-02-01 00:00:00+00:00')`,
+		`INSERT INTO mailing_list (username, email, created_at) VALUES ('jane', 'j.ane@gmail.com', '2026-03-01 00:00:00+00:00')`,
+		`INSERT INTO mailing_list (username, email, created_at) VALUES ('jane', 'jane+blog@gmail.com', '2026-03-02 00:00:00+00:00')`,
+	} {
+		if _, err := legacy.Exec(statement); err != nil {
+			t.Fatalf("Failed to prepare legacy database: %v", err)
+		}
+	}
+	if err := legacy.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+
+	repo, err := repositories.NewSqliteMailingListRepository(testFile)
+	if err != nil {
+		t.Fatalf("Expected database with duplicates to open, got %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+	for _, statement := range []string{
+		`UPDATE mailing_list SET status = 'unsubscribed' WHERE email = 'Bob@example.com'`,
+		`UPDATE mailing_list SET confirmed_at = '2026-02-02 00:00:00+00:00' WHERE email = 'bob@example.com'`,
+		`UPDATE mailing_list SET status = 'pending' WHERE email = 'jane+blog@gmail.com'`,
+	} {
+		if _, err := repo.DB().Exec(statement); err != nil {
+			t.Fatalf("Failed to prepare subscribers: %v", err)
+		}
+	}
+
+	t.Run("Dry run reports duplicates without merging", func(t *testing.T) {
+		groups, err := repo.Dedupe(dto.DefaultListID, normalize.Normalizer{}, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(groups) != 1 || groups[0].Kept != "bob@example.com" || len(groups[0].Merged) != 1 {
+			t.Fatalf("Unexpected groups: %+v", groups)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if len(page.Subscribers) != 4 {
+			t.Errorf("Expected nothing to be merged, got %d subscribers", len(page.Subscribers))
+		}
+	})
+
+	t.Run("Merges into the active subscriber keeping the earliest signup", func(t *testing.T) {
+		if _, err := repo.Dedupe(dto.DefaultListID, normalize.Normalizer{}, false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "bob", Limit: 10})
+		if len(page.Subscribers) != 1 {
+			t.Fatalf("Expected 1 subscriber, got %+v", page.Subscribers)
+		}
+		bob := page.Subscribers[0]
+		if bob.Email != "bob@example.com" || bob.Status != dto.StatusActive {
+			t.Errorf("Expected active bob@example.com to be kept, got %+v", bob)
+		}
+		if !bob.CreatedAt.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected earliest signup time, got %v", bob.CreatedAt)
+		}
+	})
+
+	t.Run("Folding aliases merges Gmail variants", func(t *testing.T) {
+		groups, err := repo.Dedupe(dto.DefaultListID, normalize.Normalizer{FoldAliases: true}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(groups) != 1 || groups[0].NormalizedEmail != "jane@gmail.com" || groups[0].Kept != "j.ane@gmail.com" {
+			t.Errorf("Unexpected groups: %+v", groups)
+		}
+
+		// Later saves of another variant land on the merged subscriber.
+		if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "jane", Email: "jane@gmail.com", NormalizedEmail: "jane@gmail.com"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if len(page.Subscribers) != 2 {
+			t.Errorf("Expected 2 subscribers, got %+v", page.Subscribers)
+		}
+	})
+}
+
+func TestSqliteDelete(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "gone", Email: "Gone@example.com"}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+
+	t.Run("Delete removes the subscriber ignoring case", func(t *testing.T) {
+		if err := repo.Delete(dto.DefaultListID, "gone@EXAMPLE.com"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if len(page.Subscribers) != 0 {
+			t.Errorf("Expected no subscribers, got %+v", page.Subscribers)
+		}
+	})
+
+	t.Run("Delete of an unknown email returns ErrSubscriberNotFound", func(t *testing.T) {
+		if err := repo.Delete(dto.DefaultListID, "gone@example.com"); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected ErrSubscriberNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSqliteImport(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "kept", Email: "kept@example.com", Status: dto.StatusPending}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+
+	left := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	subscribers := []dto.MailingList{
+		{Username: "old", Email: "old@example.com", Status: dto.StatusUnsubscribed, CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), UnsubscribedAt: &left},
+		{Username: "replaced", Email: "KEPT@example.com", Status: dto.StatusActive},
+	}
+
+	t.Run("Dry run stores nothing", func(t *testing.T) {
+		inserted, err := repo.Import(dto.DefaultListID, subscribers, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !inserted[0] || inserted[1] {
+			t.Errorf("Expected only the new subscriber to be insertable, got %v", inserted)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Limit: 10})
+		if len(page.Subscribers) != 1 {
+			t.Errorf("Expected 1 subscriber, got %d", len(page.Subscribers))
+		}
+	})
+
+	t.Run("Import keeps status and dates and leaves existing subscribers alone", func(t *testing.T) {
+		inserted, err := repo.Import(dto.DefaultListID, subscribers, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !inserted[0] || inserted[1] {
+			t.Errorf("Expected only the new subscriber to be inserted, got %v", inserted)
+		}
+
+		page, _ := repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "old", Limit: 10})
+		if len(page.Subscribers) != 1 {
+			t.Fatalf("Expected the imported subscriber, got %+v", page.Subscribers)
+		}
+		old := page.Subscribers[0]
+		if old.Status != dto.StatusUnsubscribed || old.CreatedAt.Year() != 2020 || old.UnsubscribedAt == nil || !old.UnsubscribedAt.Equal(left) {
+			t.Errorf("Expected status and dates to be imported, got %+v", old)
+		}
+
+		page, _ = repo.List(dto.DefaultListID, dto.SubscriberQuery{Search: "kept", Limit: 10})
+		if len(page.Subscribers) != 1 || page.Subscribers[0].Status != dto.StatusPending || page.Subscribers[0].Username != "kept" {
+			t.Errorf("Expected the existing subscriber to be untouched, got %+v", page.Subscribers)
+		}
+	})
+}
+
+func TestSqliteMultipleLists(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	lists, err := repositories.NewSqliteListRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create list repository: %v", err)
+	}
+	if err := lists.CreateList(&dto.List{ID: "notes", Name: "Field Notes"}); err != nil {
+		t.Fatalf("Failed to create list: %v", err)
+	}
+
+	t.Run("The same address subscribes to each list separately", func(t *testing.T) {
+		for _, listID := range []string{dto.DefaultListID, "notes"} {
+			subscriber := &dto.MailingList{Username: "reader", Email: "reader@example.com", Status: dto.StatusActive}
+			if err := repo.Save(listID, subscriber); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if subscriber.ListID != listID {
+				t.Errorf("Expected the subscriber on %s, got %q", listID, subscriber.ListID)
+			}
+		}
+
+		for _, listID := range []string{dto.DefaultListID, "notes"} {
+			active, err := repo.ListActive(listID)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(active) != 1 || active[0].ListID != listID {
+				t.Errorf("Expected one subscriber on %s, got %+v", listID, active)
+			}
+		}
+	})
+
+	t.Run("Addresses are unique within a list", func(t *testing.T) {
+		if err := repo.Save("notes", &dto.MailingList{Username: "again", Email: "READER@example.com", Status: dto.StatusPending, ConfirmationToken: "abc", ConfirmationExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		page, err := repo.List("notes", dto.SubscriberQuery{Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Subscribers) != 1 || page.Subscribers[0].Status != dto.StatusActive {
+			t.Errorf("Expected the active subscriber to be kept, got %+v", page.Subscribers)
+		}
+	})
+
+	t.Run("Unsubscribing leaves the other lists alone", func(t *testing.T) {
+		unsubscribed, err := repo.Unsubscribe("notes", "reader@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if unsubscribed.ListID != "notes" {
+			t.Errorf("Expected the subscription to notes, got %+v", unsubscribed)
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(active) != 1 {
+			t.Errorf("Expected the default list subscription to stay active, got %+v", active)
+		}
+	})
+
+	t.Run("Tokens only confirm signups to their list", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		if err := repo.Save("notes", &dto.MailingList{Username: "new", Email: "new@example.com", Status: dto.StatusPending, ConfirmationToken: "notes-token", ConfirmationExpiresAt: expiresAt}); err != nil {
+			t.Fatalf("Failed to save subscriber: %v", err)
+		}
+
+		if _, err := repo.Confirm(dto.DefaultListID, "notes-token"); !errors.Is(err, interfaces.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken on the default list, got %v", err)
+		}
+		confirmed, err := repo.Confirm("notes", "notes-token")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if confirmed.ListID != "notes" || confirmed.Status != dto.StatusActive {
+			t.Errorf("Unexpected subscriber: %+v", confirmed)
+		}
+	})
+
+	t.Run("Unknown lists return ErrListNotFound", func(t *testing.T) {
+		if err := repo.Save("nope", &dto.MailingList{Username: "reader", Email: "reader@example.com"}); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Save: expected ErrListNotFound, got %v", err)
+		}
+		if _, err := repo.Unsubscribe("nope", "reader@example.com"); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Unsubscribe: expected ErrListNotFound, got %v", err)
+		}
+		if _, err := repo.ListActive("nope"); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("ListActive: expected ErrListNotFound, got %v", err)
+		}
+		if _, err := repo.Import("nope", []dto.MailingList{{Username: "reader", Email: "reader@example.com"}}, false); !errors.Is(err, interfaces.ErrListNotFound) {
+			t.Errorf("Import: expected ErrListNotFound, got %v", err)
+		}
+	})
+}
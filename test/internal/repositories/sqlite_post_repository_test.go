@@ -0,0 +1,65 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestSqlitePostRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	queue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue repository: %v", err)
+	}
+	posts, err := repositories.NewSqlitePostRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create post repository: %v", err)
+	}
+
+	post := dto.Post{GUID: "https://zhisme.com/posts/one/", Title: "One", Link: "https://zhisme.com/posts/one/", Published: time.Now()}
+	emails := []*dto.Email{
+		{From: "blog@example.com", To: "a@example.com", Subject: "One", TextBody: "body"},
+		{From: "blog@example.com", To: "b@example.com", Subject: "One", TextBody: "body"},
+	}
+
+	t.Run("Announce records the post and queues emails", func(t *testing.T) {
+		if err := posts.Announce(post, emails); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		guids, err := posts.AnnouncedGUIDs()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !guids[post.GUID] {
+			t.Errorf("Expected %s to be announced", post.GUID)
+		}
+
+		depth, _ := queue.Depth()
+		if depth[dto.JobPending] != 2 {
+			t.Errorf("Expected 2 queued emails, got %v", depth)
+		}
+	})
+
+	t.Run("Announcing twice does not queue emails again", func(t *testing.T) {
+		if err := posts.Announce(post, emails); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		depth, _ := queue.Depth()
+		if depth[dto.JobPending] != 2 {
+			t.Errorf("Expected 2 queued emails, got %v", depth)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/repositories"
+	"errors"
+	"testing"
+)
+
+func TestSqlitePrivacyRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	privacy, err := repositories.NewSqlitePrivacyRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create privacy repository: %v", err)
+	}
+	deliveries, err := repositories.NewSqliteDeliveryRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create delivery repository: %v", err)
+	}
+	queue, err := repositories.NewSqliteMailQueueRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create mail queue: %v", err)
+	}
+	ledger, err := repositories.NewSqliteConsentRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create consent repository: %v", err)
+	}
+
+	// The subscriber signed up with a capitalized address, which deliveries
+	// and queued emails use too
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "jane", Email: "Jane@example.com", NormalizedEmail: "jane@example.com"}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+	if err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "other", Email: "other@example.com"}); err != nil {
+		t.Fatalf("Failed to save subscriber: %v", err)
+	}
+	for _, email := range []string{"Jane@example.com", "other@example.com"} {
+		if err := deliveries.MarkFailed(dto.DefaultListID, "issue-1", email, errors.New("mailbox full")); err != nil {
+			t.Fatalf("Failed to record delivery: %v", err)
+		}
+		if err := queue.Enqueue(&dto.Email{To: email, Subject: "Welcome"}); err != nil {
+			t.Fatalf("Failed to queue email: %v", err)
+		}
+		if err := ledger.Record(&dto.ConsentEvent{Email: email, Event: dto.ConsentSubscribe, Source: dto.ConsentSourceForm}); err != nil {
+			t.Fatalf("Failed to record consent: %v", err)
+		}
+	}
+
+	newRequest := func(t *testing.T, kind string) *dto.PrivacyRequest {
+		t.Helper()
+		request := &dto.PrivacyRequest{Kind: kind, EmailHash: "hash-of-jane", Status: dto.PrivacyPending}
+		if err := privacy.CreateRequest(request); err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if request.ID == 0 {
+			t.Fatal("Expected the request to get an id")
+		}
+		return request
+	}
+
+	t.Run("Finds whether anything is stored about an address", func(t *testing.T) {
+		for email, expected := range map[string]bool{"jane@example.com": true, "nobody@example.com": false} {
+			found, err := privacy.HasData(email, email)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if found != expected {
+				t.Errorf("%s: expected %v, got %v", email, expected, found)
+			}
+		}
+	})
+
+	t.Run("Access collects every table and completes the request", func(t *testing.T) {
+		request := newRequest(t, dto.PrivacyAccess)
+
+		data, err := privacy.Access(request, "jane@example.com", "jane@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(data.Subscriptions) != 1 || data.Subscriptions[0].Email != "Jane@example.com" {
+			t.Errorf("Unexpected subscriptions: %+v", data.Subscriptions)
+		}
+		if len(data.Deliveries) != 1 || data.Deliveries[0].Campaign != "issue-1" || data.Deliveries[0].Error != "mailbox full" {
+			t.Errorf("Unexpected deliveries: %+v", data.Deliveries)
+		}
+		if len(data.Emails) != 1 || data.Emails[0].Subject != "Welcome" {
+			t.Errorf("Unexpected emails: %+v", data.Emails)
+		}
+		if len(data.ConsentEvents) != 1 || data.ConsentEvents[0].Event != dto.ConsentSubscribe {
+			t.Errorf("Unexpected consent events: %+v", data.ConsentEvents)
+		}
+		if len(data.PrivacyRequests) != 1 || data.PrivacyRequests[0].Status != dto.PrivacyCompleted {
+			t.Errorf("Expected the completed request in the export, got %+v", data.PrivacyRequests)
+		}
+
+		stored, err := privacy.FindRequest(request.ID)
+		if err != nil {
+			t.Fatalf("Failed to find request: %v", err)
+		}
+		if stored.Status != dto.PrivacyCompleted || stored.CompletedAt == nil {
+			t.Errorf("Expected the request to be completed, got %+v", stored)
+		}
+	})
+
+	t.Run("Erase deletes and anonymizes only the address's rows", func(t *testing.T) {
+		request := newRequest(t, dto.PrivacyErasure)
+
+		report, err := privacy.Erase(request, "jane@example.com", "jane@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := dto.ErasureReport{Subscribers: 1, Deliveries: 1, Emails: 1, ConsentEvents: 1}
+		if *report != expected {
+			t.Errorf("Expected %+v, got %+v", expected, *report)
+		}
+
+		if found, _ := privacy.HasData("Jane@example.com", "jane@example.com"); found {
+			t.Error("Expected nothing to be left about the address")
+		}
+		if found, _ := privacy.HasData("other@example.com", "other@example.com"); !found {
+			t.Error("Expected other subscribers to be kept")
+		}
+		statuses, err := deliveries.Statuses(dto.DefaultListID, "issue-1")
+		if err != nil {
+			t.Fatalf("Failed to load deliveries: %v", err)
+		}
+		if len(statuses) != 2 {
+			t.Errorf("Expected the anonymized delivery to be kept, got %v", statuses)
+		}
+
+		requests, err := privacy.ListRequests("hash-of-jane")
+		if err != nil {
+			t.Fatalf("Failed to list requests: %v", err)
+		}
+		if len(requests) != 2 || requests[0].ID != request.ID || requests[0].Details == "" {
+			t.Errorf("Expected the audit log to keep both requests, newest first, got %+v", requests)
+		}
+	})
+
+	t.Run("Unknown requests are not found", func(t *testing.T) {
+		if _, err := privacy.FindRequest(999); !errors.Is(err, interfaces.ErrPrivacyRequestNotFound) {
+			t.Errorf("Expected ErrPrivacyRequestNotFound, got %v", err)
+		}
+	})
+}
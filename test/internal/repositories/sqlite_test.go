@@ -0,0 +1,164 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/migrate"
+	"backend-go/internal/repositories"
+	"backend-go/migrations"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// createLegacyDatabase creates the schema of the first migration the way the
+// baseline initSchema did, without a schema_migrations table.
+func createLegacyDatabase(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := repositories.OpenSqlite(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	runner, err := migrate.NewRunner(db, migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if _, err := db.Exec(runner.Migrations()[0].Up); err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("Fresh database is migrated to the latest version", func(t *testing.T) {
+		db, err := repositories.OpenSqlite(":memory:")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+
+		if err := repositories.Migrate(db); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		runner, _ := repositories.NewMigrationRunner(db)
+		statuses, err := runner.Status()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				t.Errorf("Expected migration %d to be applied", status.Version)
+			}
+		}
+	})
+
+	t.Run("Database created before migrations were tracked is adopted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "legacy.db")
+		legacy := createLegacyDatabase(t, path)
+		if _, err := legacy.Exec(`INSERT INTO mailing_list (username, email) VALUES ('old', 'old@example.com')`); err != nil {
+			t.Fatalf("Failed to insert subscriber: %v", err)
+		}
+		if err := legacy.Close(); err != nil {
+			t.Fatalf("Failed to close database: %v", err)
+		}
+
+		repo, err := repositories.NewSqliteMailingListRepository(path)
+		if err != nil {
+			t.Fatalf("Expected legacy database to open, got %v", err)
+		}
+		defer func() { _ = repo.Close() }()
+
+		if _, err := repositories.NewSqliteAPIKeyRepository(repo.DB()); err != nil {
+			t.Errorf("Expected missing tables to be created, got %v", err)
+		}
+		runner, err := repositories.NewMigrationRunner(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create runner: %v", err)
+		}
+		statuses, err := runner.Status()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				t.Errorf("Expected migration %d to be applied", status.Version)
+			}
+		}
+		unsubscribed, err := repo.Unsubscribe(dto.DefaultListID, "old@example.com")
+		if err != nil {
+			t.Fatalf("Expected existing subscriber to be kept, got %v", err)
+		}
+		if unsubscribed.Status != "unsubscribed" {
+			t.Errorf("Expected status unsubscribed, got %s", unsubscribed.Status)
+		}
+	})
+
+	t.Run("Database migrated by a newer binary is refused", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ahead.db")
+		repo, err := repositories.NewSqliteMailingListRepository(path)
+		if err != nil {
+			t.Fatalf("Failed to create repository: %v", err)
+		}
+		if _, err := repo.DB().Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (999, 'from_the_future', CURRENT_TIMESTAMP)`); err != nil {
+			t.Fatalf("Failed to record migration: %v", err)
+		}
+		if err := repo.Close(); err != nil {
+			t.Fatalf("Failed to close repository: %v", err)
+		}
+
+		_, err = repositories.NewSqliteMailingListRepository(path)
+		if !errors.Is(err, migrate.ErrDatabaseAhead) {
+			t.Errorf("Expected ErrDatabaseAhead, got %v", err)
+		}
+	})
+
+	t.Run("Lists are not rolled back while other lists have subscribers", func(t *testing.T) {
+		repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+		if err != nil {
+			t.Fatalf("Failed to create repository: %v", err)
+		}
+		defer func() { _ = repo.Close() }()
+
+		lists, err := repositories.NewSqliteListRepository(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create list repository: %v", err)
+		}
+		if err := lists.CreateList(&dto.List{ID: "go-weekly", Name: "Go Weekly"}); err != nil {
+			t.Fatalf("Failed to create list: %v", err)
+		}
+		if err := repo.Save("go-weekly", &dto.MailingList{Username: "reader", Email: "reader@example.com"}); err != nil {
+			t.Fatalf("Failed to save subscriber: %v", err)
+		}
+
+		runner, err := repositories.NewMigrationRunner(repo.DB())
+		if err != nil {
+			t.Fatalf("Failed to create runner: %v", err)
+		}
+		// Roll back to before 012_create_lists
+		if _, err := runner.Down(runner.Latest() - 11); err == nil {
+			t.Fatal("Expected the rollback to be refused")
+		}
+
+		subscribers, err := repo.ListActive("go-weekly")
+		if err != nil {
+			t.Fatalf("Expected the schema to be kept, got %v", err)
+		}
+		if len(subscribers) != 1 {
+			t.Errorf("Expected the subscriber to be kept, got %+v", subscribers)
+		}
+	})
+
+	t.Run("Repositories refuse an unmigrated database", func(t *testing.T) {
+		db, err := repositories.OpenSqlite(":memory:")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+
+		if _, err := repositories.NewSqliteMailQueueRepository(db); err == nil {
+			t.Error("Expected an error for a missing mail_queue table")
+		}
+	})
+}
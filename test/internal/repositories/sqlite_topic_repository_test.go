@@ -0,0 +1,165 @@
+package repositories_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/interfaces"
+	"backend-go/internal/repositories"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSqliteTopicRepository(t *testing.T) {
+	repo, err := repositories.NewSqliteMailingListRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() {
+		if closeErr := repo.Close(); closeErr != nil {
+			t.Errorf("Failed to close repository: %v", closeErr)
+		}
+	}()
+
+	topics, err := repositories.NewSqliteTopicRepository(repo.DB())
+	if err != nil {
+		t.Fatalf("Failed to create topic repository: %v", err)
+	}
+
+	t.Run("Creates and lists topics", func(t *testing.T) {
+		for _, topic := range []*dto.Topic{
+			{Slug: "rust", Name: "Rust"},
+			{Slug: "go", Name: "Go", Description: "Posts about Go"},
+			{Slug: "sqlite", Name: "SQLite"},
+		} {
+			if err := topics.CreateTopic(topic); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if topic.ID == 0 {
+				t.Errorf("Expected %s to get an id", topic.Slug)
+			}
+		}
+
+		list, err := topics.ListTopics()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(list) != 3 || list[0].Slug != "go" || list[0].Description != "Posts about Go" || list[2].Slug != "sqlite" {
+			t.Errorf("Expected topics ordered by slug, got %+v", list)
+		}
+	})
+
+	t.Run("Slugs are unique", func(t *testing.T) {
+		err := topics.CreateTopic(&dto.Topic{Slug: "go", Name: "Golang"})
+		if !errors.Is(err, interfaces.ErrTopicExists) {
+			t.Errorf("Expected ErrTopicExists, got %v", err)
+		}
+	})
+
+	t.Run("Signups store their topics and frequency", func(t *testing.T) {
+		subscriber := &dto.MailingList{Username: "reader", Email: "Reader@example.com", Status: dto.StatusActive, Topics: []string{"go", "sqlite"}}
+		if err := repo.Save(dto.DefaultListID, subscriber); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if subscriber.Frequency != dto.FrequencyImmediate {
+			t.Errorf("Expected the frequency to default to immediate, got %q", subscriber.Frequency)
+		}
+
+		active, err := repo.ListActive(dto.DefaultListID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(active) != 1 || !slices.Equal(active[0].Topics, []string{"go", "sqlite"}) || active[0].Frequency != dto.FrequencyImmediate {
+			t.Errorf("Expected the subscriber with topics, got %+v", active)
+		}
+	})
+
+	t.Run("Signups with unknown topics are not stored", func(t *testing.T) {
+		err := repo.Save(dto.DefaultListID, &dto.MailingList{Username: "new", Email: "new@example.com", Topics: []string{"go", "haskell"}})
+		if !errors.Is(err, interfaces.ErrTopicNotFound) {
+			t.Fatalf("Expected ErrTopicNotFound, got %v", err)
+		}
+		if _, err := topics.Preferences(dto.DefaultListID, "new@example.com"); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected the signup to be rolled back, got %v", err)
+		}
+	})
+
+	t.Run("Preferences are found by address", func(t *testing.T) {
+		preferences, err := topics.Preferences(dto.DefaultListID, "reader@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if preferences.Email != "Reader@example.com" || preferences.Username != "reader" || preferences.Frequency != dto.FrequencyImmediate {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+		if !slices.Equal(preferences.Topics, []string{"go", "sqlite"}) || len(preferences.AvailableTopics) != 3 {
+			t.Errorf("Expected chosen and available topics, got %+v", preferences)
+		}
+	})
+
+	t.Run("Updates change only the given fields", func(t *testing.T) {
+		weekly := dto.FrequencyWeekly
+		chosen := []string{"rust"}
+		preferences, err := topics.UpdatePreferences(dto.DefaultListID, "reader@example.com", dto.PreferencesUpdate{Frequency: &weekly, Topics: &chosen})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if preferences.Username != "reader" || preferences.Frequency != dto.FrequencyWeekly || !slices.Equal(preferences.Topics, chosen) {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+
+		name := "Reader"
+		none := []string{}
+		preferences, err = topics.UpdatePreferences(dto.DefaultListID, "reader@example.com", dto.PreferencesUpdate{Username: &name, Topics: &none})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if preferences.Username != "Reader" || preferences.Frequency != dto.FrequencyWeekly || len(preferences.Topics) != 0 {
+			t.Errorf("Unexpected preferences: %+v", preferences)
+		}
+	})
+
+	t.Run("Updates with unknown topics change nothing", func(t *testing.T) {
+		name := "Changed"
+		chosen := []string{"haskell"}
+		_, err := topics.UpdatePreferences(dto.DefaultListID, "reader@example.com", dto.PreferencesUpdate{Username: &name, Topics: &chosen})
+		if !errors.Is(err, interfaces.ErrTopicNotFound) {
+			t.Fatalf("Expected ErrTopicNotFound, got %v", err)
+		}
+
+		preferences, err := topics.Preferences(dto.DefaultListID, "reader@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if preferences.Username != "Reader" {
+			t.Errorf("Expected the update to be rolled back, got %+v", preferences)
+		}
+	})
+
+	t.Run("Unknown subscribers have no preferences", func(t *testing.T) {
+		if _, err := topics.Preferences(dto.DefaultListID, "nobody@example.com"); !errors.Is(err, interfaces.ErrSubscriberNotFound) {
+			t.Errorf("Expected ErrSubscriberNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Deleting a topic removes it from subscribers", func(t *testing.T) {
+		chosen := []string{"go", "rust"}
+		if _, err := topics.UpdatePreferences(dto.DefaultListID, "reader@example.com", dto.PreferencesUpdate{Topics: &chosen}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := topics.DeleteTopic("rust"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		preferences, err := topics.Preferences(dto.DefaultListID, "reader@example.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !slices.Equal(preferences.Topics, []string{"go"}) || len(preferences.AvailableTopics) != 2 {
+			t.Errorf("Expected rust to be gone, got %+v", preferences)
+		}
+
+		if err := topics.DeleteTopic("rust"); !errors.Is(err, interfaces.ErrTopicNotFound) {
+			t.Errorf("Expected ErrTopicNotFound, got %v", err)
+		}
+	})
+}
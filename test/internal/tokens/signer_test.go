@@ -0,0 +1,95 @@
+package tokens_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/tokens"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	signer := tokens.NewSigner([]byte("secret"))
+
+	t.Run("Signed token verifies and returns subject", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+
+		subject, err := signer.Verify(tokens.PurposeUnsubscribe, token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if subject != "reader@example.com" {
+			t.Errorf("Expected subject reader@example.com, got %s", subject)
+		}
+	})
+
+	t.Run("Token for another purpose is rejected", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+
+		if _, err := signer.Verify("other", token); !errors.Is(err, tokens.ErrSignature) {
+			t.Errorf("Expected ErrSignature, got %v", err)
+		}
+	})
+
+	t.Run("Token signed with another secret is rejected", func(t *testing.T) {
+		other := tokens.NewSigner([]byte("other-secret"))
+		token := other.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, token); !errors.Is(err, tokens.ErrSignature) {
+			t.Errorf("Expected ErrSignature, got %v", err)
+		}
+	})
+
+	t.Run("Tampered subject is rejected", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Time{})
+		forged := signer.Sign(tokens.PurposeUnsubscribe, "victim@example.com", time.Time{})
+
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(token, ".")
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, payload+"."+signature); !errors.Is(err, tokens.ErrSignature) {
+			t.Errorf("Expected ErrSignature, got %v", err)
+		}
+	})
+
+	t.Run("Expired token is rejected", func(t *testing.T) {
+		token := signer.Sign(tokens.PurposeUnsubscribe, "reader@example.com", time.Now().Add(-time.Minute))
+
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, token); !errors.Is(err, tokens.ErrExpired) {
+			t.Errorf("Expected ErrExpired, got %v", err)
+		}
+	})
+
+	t.Run("Malformed token is rejected", func(t *testing.T) {
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, "not-a-token"); !errors.Is(err, tokens.ErrMalformed) {
+			t.Errorf("Expected ErrMalformed, got %v", err)
+		}
+	})
+
+	t.Run("Digest is keyed and depends on the purpose", func(t *testing.T) {
+		digest := signer.Digest(tokens.PurposeConsentIP, "203.0.113.7")
+		if digest != signer.Digest(tokens.PurposeConsentIP, "203.0.113.7") {
+			t.Error("Expected the same digest for the same value")
+		}
+		if digest == tokens.NewSigner([]byte("other-secret")).Digest(tokens.PurposeConsentIP, "203.0.113.7") {
+			t.Error("Expected a different digest with another secret")
+		}
+		if digest == signer.Digest(tokens.PurposeUnsubscribe, "203.0.113.7") {
+			t.Error("Expected a different digest for another purpose")
+		}
+	})
+
+	t.Run("List purposes keep tokens to their list", func(t *testing.T) {
+		if purpose := tokens.ListPurpose(tokens.PurposeUnsubscribe, dto.DefaultListID); purpose != tokens.PurposeUnsubscribe {
+			t.Errorf("Expected the default list to keep the bare purpose, got %q", purpose)
+		}
+
+		token := signer.Sign(tokens.ListPurpose(tokens.PurposeUnsubscribe, "notes"), "reader@example.com", time.Time{})
+		if _, err := signer.Verify(tokens.ListPurpose(tokens.PurposeUnsubscribe, "other"), token); !errors.Is(err, tokens.ErrSignature) {
+			t.Errorf("Expected ErrSignature on another list, got %v", err)
+		}
+		if _, err := signer.Verify(tokens.PurposeUnsubscribe, token); !errors.Is(err, tokens.ErrSignature) {
+			t.Errorf("Expected ErrSignature on the default list, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package validators_test
+
+import (
+	"backend-go/internal/validators"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDomainSet(t *testing.T) {
+	t.Run("Parses domains skipping comments and blank lines", func(t *testing.T) {
+		domains, err := validators.ParseDomainSet(strings.NewReader("# comment\n\nThrowaway.test\nwegwerf-bücher.test.\n"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(domains) != 2 {
+			t.Errorf("Expected 2 domains, got %v", domains)
+		}
+		if !domains.Contains("throwaway.test") || !domains.Contains("xn--wegwerf-bcher-4ob.test") {
+			t.Errorf("Expected domains to be lowercased and converted to ASCII, got %v", domains)
+		}
+	})
+
+	t.Run("Subdomains of listed domains match", func(t *testing.T) {
+		domains := validators.DomainSet{"throwaway.test": {}}
+		if !domains.Contains("inbox.throwaway.test") {
+			t.Error("Expected subdomain to match")
+		}
+		if domains.Contains("notthrowaway.test") || domains.Contains("test") {
+			t.Error("Expected unrelated domains not to match")
+		}
+	})
+
+	t.Run("Loads domains from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "disposable.txt")
+		if err := os.WriteFile(path, []byte("throwaway.test\n"), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		domains, err := validators.LoadDomainSet(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !domains.Contains("throwaway.test") {
+			t.Error("Expected domain from file")
+		}
+
+		if _, err := validators.LoadDomainSet(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+
+	t.Run("Invalid domains are rejected", func(t *testing.T) {
+		if _, err := validators.ParseDomainSet(strings.NewReader("not a domain\n")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Built-in list is not empty", func(t *testing.T) {
+		if !validators.DefaultDisposableDomains().Contains("mailinator.com") {
+			t.Error("Expected mailinator.com in the built-in list")
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package validators_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/validators"
+	"context"
+	"net"
+	"testing"
+)
+
+// fakeResolver answers DNS lookups from maps and records the names asked
+// for. Domains in neither map don't exist.
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	err     error
+	queries []string
+}
+
+func (r *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.queries = append(r.queries, name)
+	if r.err != nil {
+		return nil, r.err
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if hosts, ok := r.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func emailErrorCode(t *testing.T, err *dto.FieldError) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	if err.Field != "email" {
+		t.Errorf("Expected field email, got %s", err.Field)
+	}
+	return err.Code
+}
+
+func TestEmailValidatorSyntax(t *testing.T) {
+	validator := validators.NewEmailValidator()
+	ctx := context.Background()
+
+	valid := []string{
+		"simple@example.com",
+		"user+tag@example.co.uk",
+		"o'brien@example.ie",
+		"jörg@bücher.de",
+		"用户@例子.中国",
+		"USER@EXAMPLE.COM",
+	}
+	for _, email := range valid {
+		if err := validator.Validate(ctx, email); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", email, err)
+		}
+	}
+
+	invalid := []string{
+		"notanemail",
+		"@example.com",
+		"user@",
+		"user @example.com",
+		"user@example",
+		"Name <user@example.com>",
+		"user@exa_mple.com",
+		"user@-example.com",
+		"user@example..com",
+		"user@1.2.3.4",
+		"user@[192.0.2.1]",
+		`"john doe"@example.com`,
+	}
+	for _, email := range invalid {
+		if code := emailErrorCode(t, validator.Validate(ctx, email)); code != validators.EmailInvalid {
+			t.Errorf("Expected %q to be invalid, got %q", email, code)
+		}
+	}
+
+	if code := emailErrorCode(t, validator.Validate(ctx, "")); code != validators.EmailRequired {
+		t.Errorf("Expected empty email to be required, got %q", code)
+	}
+}
+
+func TestEmailValidatorTypos(t *testing.T) {
+	validator := validators.NewEmailValidator()
+	ctx := context.Background()
+
+	suggestions := map[string]string{
+		"reader@gmial.com":     "reader@gmail.com",
+		"reader@gmail.con":     "reader@gmail.com",
+		"reader@hotmial.com":   "reader@hotmail.com",
+		"reader@yahooo.com":    "reader@yahoo.com",
+		"reader@outlok.com":    "reader@outlook.com",
+		"reader@protonmal.com": "reader@protonmail.com",
+	}
+	for email, want := range suggestions {
+		emailErr := validator.Validate(ctx, email)
+		if emailErr == nil || emailErr.Code != validators.EmailTypo {
+			t.Errorf("Expected a typo error for %s", email)
+			continue
+		}
+		if emailErr.Suggestion != want {
+			t.Errorf("Expected suggestion %s for %s, got %s", want, email, emailErr.Suggestion)
+		}
+	}
+
+	for _, email := range []string{"reader@gmail.com", "reader@mail.com", "reader@me.com", "reader@yahoo.fr", "reader@ymail.com", "reader@email.com", "reader@zhisme.com"} {
+		if err := validator.Validate(ctx, email); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", email, err)
+		}
+	}
+}
+
+func TestEmailValidatorDisposable(t *testing.T) {
+	ctx := context.Background()
+
+	validator := validators.NewEmailValidator()
+	for _, email := range []string{"bot@mailinator.com", "bot@eu.mailinator.com", "bot@YOPMAIL.com"} {
+		if code := emailErrorCode(t, validator.Validate(ctx, email)); code != validators.EmailDisposable {
+			t.Errorf("Expected %s to be disposable, got %q", email, code)
+		}
+	}
+
+	custom := validators.NewEmailValidator(validators.WithDisposableDomains(validators.DomainSet{"throwaway.test": {}}))
+	if code := emailErrorCode(t, custom.Validate(ctx, "bot@throwaway.test")); code != validators.EmailDisposable {
+		t.Errorf("Expected custom list to be used, got %q", code)
+	}
+	if err := custom.Validate(ctx, "bot@mailinator.com"); err != nil {
+		t.Errorf("Expected custom list to replace the built-in one, got %v", err)
+	}
+}
+
+func TestEmailValidatorDNS(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com.":       {{Host: "mx.example.com.", Pref: 10}},
+			"nullmx.example.":    {{Host: ".", Pref: 0}},
+			"xn--bcher-kva.com.": {{Host: "mx.xn--bcher-kva.com.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"a-only.example.": {"192.0.2.1"},
+		},
+	}
+	validator := validators.NewEmailValidator(validators.WithResolver(resolver, validators.DefaultLookupTimeout))
+
+	t.Run("Domains with MX or address records are accepted", func(t *testing.T) {
+		for _, email := range []string{"reader@example.com", "reader@a-only.example", "reader@Bücher.com"} {
+			if err := validator.Validate(ctx, email); err != nil {
+				t.Errorf("Expected %s to be accepted, got %v", email, err)
+			}
+		}
+	})
+
+	t.Run("Internationalized domains are looked up in ASCII form", func(t *testing.T) {
+		if last := resolver.queries[len(resolver.queries)-1]; last != "xn--bcher-kva.com." {
+			t.Errorf("Expected punycode lookup, got %s", last)
+		}
+	})
+
+	t.Run("Domains without records or with a null MX are undeliverable", func(t *testing.T) {
+		for _, email := range []string{"reader@missing.example", "reader@nullmx.example"} {
+			if code := emailErrorCode(t, validator.Validate(ctx, email)); code != validators.EmailUndeliverable {
+				t.Errorf("Expected %s to be undeliverable, got %q", email, code)
+			}
+		}
+	})
+
+	t.Run("DNS failures let the address through", func(t *testing.T) {
+		failing := validators.NewEmailValidator(validators.WithResolver(
+			&fakeResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}},
+			validators.DefaultLookupTimeout,
+		))
+		if err := failing.Validate(ctx, "reader@example.com"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
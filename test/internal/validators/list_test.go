@@ -0,0 +1,23 @@
+package validators_test
+
+import (
+	"backend-go/internal/validators"
+	"testing"
+)
+
+func TestValidListID(t *testing.T) {
+	for id, valid := range map[string]bool{
+		"default":     true,
+		"field-notes": true,
+		"site2":       true,
+		"":            false,
+		"Notes":       false,
+		"field notes": false,
+		"notes/":      false,
+		"-notes":      false,
+	} {
+		if got := validators.ValidListID(id); got != valid {
+			t.Errorf("ValidListID(%q) = %v, want %v", id, got, valid)
+		}
+	}
+}
@@ -70,18 +70,56 @@ func TestValidate(t *testing.T) {
 func TestValidatorEdgeCases(t *testing.T) {
 	validator := validators.NewMailingListValidator()
 
-	t.Run("Both empty username and email should fail on email first", func(t *testing.T) {
+	t.Run("Both empty username and email report both fields, email first", func(t *testing.T) {
 		ml := &dto.MailingList{
 			Username: "",
 			Email:    "",
 		}
 
-		err := validator.Validate(ml)
-		if err == nil {
-			t.Error("Expected error for both empty, got nil")
+		fieldErrors := validator.Validate(ml)
+		if len(fieldErrors) != 2 {
+			t.Fatalf("Expected 2 field errors, got %+v", fieldErrors)
+		}
+		if fieldErrors[0].Field != "email" || fieldErrors[0].Code != validators.EmailRequired {
+			t.Errorf("Expected email.required first, got %+v", fieldErrors[0])
+		}
+		if fieldErrors[1].Field != "username" || fieldErrors[1].Code != validators.UsernameRequired {
+			t.Errorf("Expected username.required second, got %+v", fieldErrors[1])
+		}
+		if !strings.Contains(fieldErrors[0].Message, "email is required") {
+			t.Errorf("Expected 'email is required' message, got %s", fieldErrors[0].Message)
 		}
-		if !strings.Contains(err.Error(), "email is required") {
-			t.Errorf("Expected 'email is required' error, got %v", err)
+	})
+
+	t.Run("Invalid email is reported with its code", func(t *testing.T) {
+		fieldErrors := validator.Validate(&dto.MailingList{Username: "testuser", Email: "notanemail"})
+		if len(fieldErrors) != 1 || fieldErrors[0].Code != validators.EmailInvalid {
+			t.Errorf("Expected email.invalid, got %+v", fieldErrors)
+		}
+	})
+
+	t.Run("Usernames longer than the maximum are rejected", func(t *testing.T) {
+		fieldErrors := validator.Validate(&dto.MailingList{
+			Username: strings.Repeat("ü", validators.MaxUsernameLength+1),
+			Email:    "test@example.com",
+		})
+		if len(fieldErrors) != 1 || fieldErrors[0].Code != validators.UsernameTooLong {
+			t.Errorf("Expected username.too_long, got %+v", fieldErrors)
+		}
+
+		fieldErrors = validator.Validate(&dto.MailingList{
+			Username: strings.Repeat("ü", validators.MaxUsernameLength),
+			Email:    "test@example.com",
+		})
+		if len(fieldErrors) != 0 {
+			t.Errorf("Expected a username of maximum length to be valid, got %+v", fieldErrors)
+		}
+	})
+
+	t.Run("Blank usernames are required", func(t *testing.T) {
+		fieldErrors := validator.Validate(&dto.MailingList{Username: "   ", Email: "test@example.com"})
+		if len(fieldErrors) != 1 || fieldErrors[0].Code != validators.UsernameRequired {
+			t.Errorf("Expected username.required, got %+v", fieldErrors)
 		}
 	})
 }
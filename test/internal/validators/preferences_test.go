@@ -0,0 +1,68 @@
+package validators_test
+
+import (
+	"backend-go/internal/dto"
+	"backend-go/internal/validators"
+	"strings"
+	"testing"
+)
+
+func TestValidTopicSlug(t *testing.T) {
+	for slug, valid := range map[string]bool{
+		"go":           true,
+		"self-hosting": true,
+		"web3":         true,
+		"":             false,
+		"Go":           false,
+		"self hosting": false,
+		"-go":          false,
+		"go--lang":     false,
+	} {
+		if got := validators.ValidTopicSlug(slug); got != valid {
+			t.Errorf("ValidTopicSlug(%q) = %v, want %v", slug, got, valid)
+		}
+	}
+}
+
+func TestValidatePreferences(t *testing.T) {
+	t.Run("Empty update is valid", func(t *testing.T) {
+		if fieldErrors := validators.ValidatePreferences(dto.PreferencesUpdate{}); len(fieldErrors) != 0 {
+			t.Errorf("Expected no errors, got %+v", fieldErrors)
+		}
+	})
+
+	t.Run("Valid update passes", func(t *testing.T) {
+		username := "reader"
+		frequency := dto.FrequencyMonthly
+		topics := []string{"go", "self-hosting"}
+		update := dto.PreferencesUpdate{Username: &username, Frequency: &frequency, Topics: &topics}
+		if fieldErrors := validators.ValidatePreferences(update); len(fieldErrors) != 0 {
+			t.Errorf("Expected no errors, got %+v", fieldErrors)
+		}
+	})
+
+	t.Run("Every invalid field is reported", func(t *testing.T) {
+		username := strings.Repeat("a", validators.MaxUsernameLength+1)
+		frequency := "daily"
+		topics := []string{"go", "Not A Slug"}
+		fieldErrors := validators.ValidatePreferences(dto.PreferencesUpdate{Username: &username, Frequency: &frequency, Topics: &topics})
+
+		codes := []string{validators.UsernameTooLong, validators.FrequencyInvalid, validators.TopicsInvalid}
+		if len(fieldErrors) != len(codes) {
+			t.Fatalf("Expected %d errors, got %+v", len(codes), fieldErrors)
+		}
+		for i, code := range codes {
+			if fieldErrors[i].Code != code {
+				t.Errorf("Error %d: expected %s, got %s", i, code, fieldErrors[i].Code)
+			}
+		}
+	})
+
+	t.Run("Empty username is rejected", func(t *testing.T) {
+		username := " "
+		fieldErrors := validators.ValidatePreferences(dto.PreferencesUpdate{Username: &username})
+		if len(fieldErrors) != 1 || fieldErrors[0].Code != validators.UsernameRequired {
+			t.Errorf("Expected username.required, got %+v", fieldErrors)
+		}
+	})
+}